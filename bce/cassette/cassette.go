@@ -0,0 +1,282 @@
+// Package cassette records the http requests and responses of bce.Client to a cassette file, and replays them
+// later, so the code using Baidu Cloud services can be tested by golden files without a live endpoint.
+//
+// The recorder and the player are plugged in by the WrapTransport of bce.Config:
+//
+//	recorder := cassette.NewRecorder("testdata/list_buckets.json")
+//	config.WrapTransport = recorder.Wrap
+//
+//	// send requests by the client of config, then
+//	err := recorder.Save()
+//
+//	player, err := cassette.NewPlayer("testdata/list_buckets.json", cassette.MATCH_STRICT)
+//	config.WrapTransport = player.Wrap
+//
+// The Authorization and other secrets are scrubbed before the cassette is saved.
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SCRUBBED is the value of the secrets scrubbed from a cassette.
+const SCRUBBED = "SCRUBBED"
+
+// BODY_ENCODING_BASE64 is the encoding of the body which is not a valid UTF-8 string.
+const BODY_ENCODING_BASE64 = "base64"
+
+// secretHeaders are the request headers scrubbed from a cassette.
+var secretHeaders = []string{
+	"Authorization",
+	"x-bce-security-token",
+	"x-bce-server-side-encryption-customer-key",
+	"x-bce-copy-source-server-side-encryption-customer-key",
+}
+
+// secretParams are the query parameters excluded from a cassette, such as the signature of a presigned URL.
+var secretParams = []string{"authorization"}
+
+// secretFields are the fields of JSON response body scrubbed from a cassette, such as the STS credentials.
+var secretFields = []string{"secretAccessKey", "sessionToken"}
+
+// Request defined a struct for the recorded http request.
+type Request struct {
+	Method string      `json:"method"`
+	Host   string      `json:"host"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query"`
+	Header http.Header `json:"header"`
+
+	// BodySHA256 is the hex encoded SHA256 of the body, the body itself is not recorded.
+	BodySHA256    string `json:"bodySha256"`
+	ContentLength int64  `json:"contentLength"`
+}
+
+// Response defined a struct for the recorded http response.
+type Response struct {
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header"`
+	Body         string      `json:"body"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// Interaction defined a struct for a recorded request and its response, Error is the error of the transport
+// if no response is received.
+type Interaction struct {
+	Request  Request   `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Cassette defined a struct for the recorded interactions in order.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Load reads a cassette from the file.
+func Load(path string) (*Cassette, error) {
+	byteArray, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := new(Cassette)
+
+	if err := json.Unmarshal(byteArray, cassette); err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+// Save writes the cassette to the file.
+func (cassette *Cassette) Save(path string) error {
+	byteArray, err := json.MarshalIndent(cassette, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(byteArray, '\n'), 0644)
+}
+
+// readBody reads the body of req and replaces it by a new reader of the same content.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+
+	byteArray, err := ioutil.ReadAll(req.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(byteArray))
+
+	return byteArray, nil
+}
+
+// newRequest returns the recorded request of req whose body is body, the secrets are scrubbed.
+func newRequest(req *http.Request, body []byte) Request {
+	header := make(http.Header)
+
+	for key, values := range req.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	for _, key := range secretHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, SCRUBBED)
+		}
+	}
+
+	host := req.Host
+
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	sum := sha256.Sum256(body)
+
+	return Request{
+		Method:        req.Method,
+		Host:          host,
+		Path:          req.URL.Path,
+		Query:         canonicalQuery(req.URL.RawQuery),
+		Header:        header,
+		BodySHA256:    hex.EncodeToString(sum[:]),
+		ContentLength: int64(len(body)),
+	}
+}
+
+// canonicalQuery sorts the query parameters by name and value, the secret parameters are excluded.
+func canonicalQuery(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+
+	if err != nil {
+		return rawQuery
+	}
+
+	for _, param := range secretParams {
+		query.Del(param)
+	}
+
+	for _, values := range query {
+		sort.Strings(values)
+	}
+
+	return query.Encode()
+}
+
+// newResponse returns the recorded response of resp whose body is body, the secrets are scrubbed.
+func newResponse(resp *http.Response, body []byte) *Response {
+	header := make(http.Header)
+
+	for key, values := range resp.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	if scrubbed := scrubBody(body); !bytes.Equal(scrubbed, body) {
+		body = scrubbed
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+
+	response := &Response{StatusCode: resp.StatusCode, Header: header}
+
+	if utf8.Valid(body) {
+		response.Body = string(body)
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(body)
+		response.BodyEncoding = BODY_ENCODING_BASE64
+	}
+
+	return response
+}
+
+// scrubBody replaces the secret fields of JSON body by SCRUBBED, the other bodies are returned as is.
+func scrubBody(body []byte) []byte {
+	var fields map[string]interface{}
+
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+
+	scrubbed := false
+
+	for _, key := range secretFields {
+		if _, ok := fields[key]; ok {
+			fields[key] = SCRUBBED
+			scrubbed = true
+		}
+	}
+
+	if !scrubbed {
+		return body
+	}
+
+	byteArray, err := json.Marshal(fields)
+
+	if err != nil {
+		return body
+	}
+
+	return byteArray
+}
+
+// body returns the decoded body of the recorded response.
+func (response *Response) body() ([]byte, error) {
+	if response.BodyEncoding == BODY_ENCODING_BASE64 {
+		return base64.StdEncoding.DecodeString(response.Body)
+	}
+
+	return []byte(response.Body), nil
+}
+
+// httpResponse builds the http response of req from the recorded response.
+func (response *Response) httpResponse(req *http.Request) (*http.Response, error) {
+	body, err := response.body()
+
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+
+	for key, values := range response.Header {
+		header[key] = append([]string(nil), values...)
+	}
+
+	// the Content-Length of a HEAD response is the size of the resource instead of the empty body
+	contentLength := int64(len(body))
+
+	if value, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		contentLength = value
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: contentLength,
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,162 @@
+package cassette
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func newTestClient(config *bce.Config, wrapTransport func(transport http.RoundTripper) http.RoundTripper) *bos.Client {
+	config.RetryPolicy = bce.NewDefaultRetryPolicy(0, 0)
+	config.Checksum = true
+	config.WrapTransport = wrapTransport
+
+	return bos.NewClient(bos.NewConfig(config))
+}
+
+func getObjectContent(bosClient *bos.Client, objectKey string) (string, error) {
+	object, err := bosClient.GetObject("bostest-cassette", objectKey, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer object.ObjectContent.Close()
+
+	byteArray, err := ioutil.ReadAll(object.ObjectContent)
+
+	return string(byteArray), err
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	file, err := ioutil.TempFile("", "cassette")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file.Close()
+	defer os.Remove(file.Name())
+
+	server := bostest.NewServer()
+	config := server.NewConfig()
+	recorder := NewRecorder(file.Name())
+	bosClient := newTestClient(config, recorder.Wrap)
+
+	if err := bosClient.CreateBucket("bostest-cassette", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bosClient.PutObject("bostest-cassette", "a.txt", "record and replay", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if content, err := getObjectContent(bosClient, "a.txt"); err != nil || content != "record and replay" {
+		t.Error(util.FormatTest("record", content, "record and replay"))
+	}
+
+	server.Close()
+
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	byteArray, _ := ioutil.ReadFile(file.Name())
+
+	if bytes.Contains(byteArray, []byte(config.SecretAccessKey)) || bytes.Contains(byteArray, []byte("bce-auth-v1")) {
+		t.Error(util.FormatTest("record", string(byteArray), "cassette without secrets"))
+	}
+
+	player, err := NewPlayer(file.Name(), MATCH_STRICT)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bosClient = newTestClient(server.NewConfig(), player.Wrap)
+
+	if err := bosClient.CreateBucket("bostest-cassette", nil); err != nil {
+		t.Error(util.FormatTest("replay", err.Error(), "nil"))
+	}
+
+	if _, err := bosClient.PutObject("bostest-cassette", "a.txt", "not recorded", nil, nil); err == nil {
+		t.Error(util.FormatTest("replay", "nil", "error of unmatched body"))
+	}
+
+	if _, err := bosClient.PutObject("bostest-cassette", "a.txt", "record and replay", nil, nil); err != nil {
+		t.Error(util.FormatTest("replay", err.Error(), "nil"))
+	}
+
+	if content, err := getObjectContent(bosClient, "a.txt"); err != nil || content != "record and replay" {
+		t.Error(util.FormatTest("replay", content, "record and replay"))
+	}
+
+	if unplayed := player.Unplayed(); len(unplayed) != 0 {
+		t.Error(util.FormatTest("replay", strconv.Itoa(len(unplayed)), "0"))
+	}
+
+	if _, err := getObjectContent(bosClient, "a.txt"); err == nil || !strings.Contains(err.Error(), "No recorded interaction") {
+		t.Error(util.FormatTest("replay", "nil", "error of replayed interaction"))
+	}
+
+	player, err = NewPlayer(file.Name(), MATCH_LENIENT)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bosClient = newTestClient(server.NewConfig(), player.Wrap)
+
+	for i := 0; i < 2; i++ {
+		if content, err := getObjectContent(bosClient, "a.txt"); err != nil || content != "record and replay" {
+			t.Error(util.FormatTest("replay lenient", content, "record and replay"))
+		}
+	}
+
+	if len(player.Unplayed()) != 2 {
+		t.Error(util.FormatTest("replay lenient", strconv.Itoa(len(player.Unplayed())), "2"))
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	cases := []struct {
+		rawQuery, expected string
+	}{
+		{"", ""},
+		{"uploads", "uploads="},
+		{"maxKeys=10&prefix=a%2Fb&delimiter=%2F", "delimiter=%2F&maxKeys=10&prefix=a%2Fb"},
+		{"b=2&a=1&b=1&authorization=bce-auth-v1", "a=1&b=1&b=2"},
+	}
+
+	for _, c := range cases {
+		if got := canonicalQuery(c.rawQuery); got != c.expected {
+			t.Error(util.FormatTest("canonicalQuery "+c.rawQuery, got, c.expected))
+		}
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	cases := []struct {
+		body, expected string
+	}{
+		{`{"accessKeyId":"ak","secretAccessKey":"sk","sessionToken":"token"}`,
+			`{"accessKeyId":"ak","secretAccessKey":"SCRUBBED","sessionToken":"SCRUBBED"}`},
+		{`{"buckets":[]}`, `{"buckets":[]}`},
+		{"plain text", "plain text"},
+	}
+
+	for _, c := range cases {
+		if got := string(scrubBody([]byte(c.body))); got != c.expected {
+			t.Error(util.FormatTest("scrubBody", got, c.expected))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// MatchMode is how the player matches a request with the recorded requests.
+type MatchMode int
+
+const (
+	// MATCH_STRICT matches the method, host, path, canonical query and body hash of a request,
+	// each recorded interaction is replayed only once.
+	MATCH_STRICT MatchMode = iota
+
+	// MATCH_LENIENT matches the method, host, path and canonical query of a request, the recorded interaction
+	// with the same body hash and not replayed yet is preferred, and an interaction can be replayed many times.
+	MATCH_LENIENT
+)
+
+// Player replays the recorded responses of a cassette instead of sending the requests.
+type Player struct {
+	Mode MatchMode
+
+	mutex    sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// NewPlayer loads the cassette from the file and returns a player of it.
+func NewPlayer(path string, mode MatchMode) (*Player, error) {
+	cassette, err := Load(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlayerFromCassette(cassette, mode), nil
+}
+
+// NewPlayerFromCassette returns a player of the cassette.
+func NewPlayerFromCassette(cassette *Cassette, mode MatchMode) *Player {
+	return &Player{
+		Mode:     mode,
+		cassette: cassette,
+		replayed: make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Wrap returns the player as the transport, the transport passed in is never used,
+// it is used as the WrapTransport of bce.Config.
+func (player *Player) Wrap(transport http.RoundTripper) http.RoundTripper {
+	return player
+}
+
+// RoundTrip returns the recorded response of the matched interaction, an error is returned
+// if no interaction is matched.
+func (player *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request is copied as its body is replaced by readBody
+	inReq := new(http.Request)
+	*inReq = *req
+	body, err := readBody(inReq)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := newRequest(inReq, body)
+
+	player.mutex.Lock()
+	interaction := player.match(recorded)
+	player.mutex.Unlock()
+
+	if interaction == nil {
+		return nil, fmt.Errorf("No recorded interaction matches %s %s%s?%s.",
+			recorded.Method, recorded.Host, recorded.Path, recorded.Query)
+	}
+
+	if interaction.Response == nil {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return interaction.Response.httpResponse(req)
+}
+
+// match returns the matched interaction which is not replayed and has the same body hash in preference,
+// the earlier recorded one is preferred if there are more than one.
+func (player *Player) match(recorded Request) *Interaction {
+	candidate, candidateScore := -1, -1
+
+	for index, interaction := range player.cassette.Interactions {
+		r := interaction.Request
+
+		if r.Method != recorded.Method || r.Host != recorded.Host || r.Path != recorded.Path ||
+			r.Query != recorded.Query {
+
+			continue
+		}
+
+		score := 0
+
+		if !player.replayed[index] {
+			score += 2
+		}
+
+		if r.BodySHA256 == recorded.BodySHA256 {
+			score += 1
+		}
+
+		if player.Mode == MATCH_STRICT && score < 3 {
+			continue
+		}
+
+		if score > candidateScore {
+			candidate, candidateScore = index, score
+		}
+	}
+
+	if candidate < 0 {
+		return nil
+	}
+
+	player.replayed[candidate] = true
+
+	return player.cassette.Interactions[candidate]
+}
+
+// Unplayed returns the recorded interactions which are not replayed yet.
+func (player *Player) Unplayed() []*Interaction {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	var interactions []*Interaction
+
+	for index, interaction := range player.cassette.Interactions {
+		if !player.replayed[index] {
+			interactions = append(interactions, interaction)
+		}
+	}
+
+	return interactions
+}
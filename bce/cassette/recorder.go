@@ -0,0 +1,100 @@
+package cassette
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Recorder records the requests and responses sent by the transport it wraps.
+type Recorder struct {
+	// Path is the file the cassette is saved to.
+	Path string
+
+	transport http.RoundTripper
+	mutex     sync.Mutex
+	cassette  Cassette
+}
+
+// NewRecorder returns a recorder which saves the cassette to the file.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{Path: path}
+}
+
+// Wrap returns the recorder as the transport, the requests are sent by transport and recorded,
+// it is used as the WrapTransport of bce.Config.
+func (recorder *Recorder) Wrap(transport http.RoundTripper) http.RoundTripper {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.transport = transport
+
+	return recorder
+}
+
+// RoundTrip sends the request by the wrapped transport and records the request and response.
+func (recorder *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder.mutex.Lock()
+	transport := recorder.transport
+	recorder.mutex.Unlock()
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	outReq := new(http.Request)
+	*outReq = *req
+	body, err := readBody(outReq)
+
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{Request: newRequest(outReq, body)}
+	resp, err := transport.RoundTrip(outReq)
+
+	if err != nil {
+		interaction.Error = err.Error()
+		recorder.record(interaction)
+
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		interaction.Error = err.Error()
+		recorder.record(interaction)
+
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	interaction.Response = newResponse(resp, respBody)
+	recorder.record(interaction)
+
+	return resp, nil
+}
+
+func (recorder *Recorder) record(interaction *Interaction) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, interaction)
+}
+
+// Cassette returns the interactions recorded so far.
+func (recorder *Recorder) Cassette() *Cassette {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return &Cassette{Interactions: append([]*Interaction(nil), recorder.cassette.Interactions...)}
+}
+
+// Save writes the recorded interactions to the file of the recorder.
+func (recorder *Recorder) Save() error {
+	return recorder.Cassette().Save(recorder.Path)
+}
@@ -0,0 +1,570 @@
+package bce
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// DEFAULT_PROFILE is the profile loaded by bce.LoadConfig if no profile is specified.
+const DEFAULT_PROFILE = "default"
+
+// CONFIG_FILES are the paths of config file relative to the home directory, the first existing one is loaded
+// by bce.LoadConfig. The files ending with ".yaml" or ".yml" are YAML, and the others are INI.
+var CONFIG_FILES = []string{".bce/config", ".bce/config.yaml", ".bce/config.yml", ".bce/credentials"}
+
+// configKeys are the keys of a profile, the environment variable of a key is "BCE_" + the upper case key,
+// such as BCE_ACCESS_KEY_ID.
+var configKeys = []string{
+	"access_key_id",
+	"secret_access_key",
+	"region",
+	"endpoint",
+	"api_version",
+	"protocol",
+	"user_agent",
+	"proxy_host",
+	"proxy_port",
+	"max_connections",
+	"timeout",
+	"checksum",
+	"max_error_retry",
+	"max_retry_delay",
+}
+
+// configEnvAliases are the other environment variables of a key, they are used if "BCE_" + key is not set.
+var configEnvAliases = map[string][]string{
+	"access_key_id":     {"BAIDU_BCE_AK"},
+	"secret_access_key": {"BAIDU_BCE_SK"},
+	"region":            {"BOS_REGION"},
+}
+
+// LoadConfigOption contains all options for bce.LoadConfig method.
+type LoadConfigOption struct {
+	// Profile is the section of config file, if it is empty, the environment variable BCE_PROFILE
+	// or DEFAULT_PROFILE is used.
+	Profile string
+
+	// ConfigFile is the path of config file, if it is empty, the environment variable BCE_CONFIG_FILE
+	// or the first existing file of CONFIG_FILES in the home directory is used.
+	ConfigFile string
+
+	// Overrides are applied at last, only the non-zero fields are used.
+	Overrides *Config
+}
+
+// LoadConfig loads bce.Config from the config file, the environment variables and the overrides.
+//
+// A value is overridden by the later ones in the order:
+//
+//  1. the defaults of bce.NewConfig
+//  2. the profile in the config file
+//  3. the environment variables, such as BCE_REGION and BCE_TIMEOUT
+//  4. the overrides of option
+//
+// A profile of INI file is a section of "key = value" lines, and a profile of YAML file is a top level
+// mapping of "key: value" lines:
+//
+//	[default]
+//	access_key_id = <access key id>
+//	secret_access_key = <secret access key>
+//	region = gz
+//	timeout = 30s
+//	max_error_retry = 5
+//
+// The keys are access_key_id, secret_access_key, region, endpoint, api_version, protocol, user_agent,
+// proxy_host, proxy_port, max_connections, timeout, checksum, max_error_retry and max_retry_delay.
+// The timeout and max_retry_delay are durations such as "1m30s", or integers in seconds.
+//
+// The loaded config is checked by Config.Validate, and an error is returned instead of a partial config.
+func LoadConfig(option *LoadConfigOption) (*Config, error) {
+	if option == nil {
+		option = &LoadConfigOption{}
+	}
+
+	values, err := loadProfile(option.Profile, option.ConfigFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range configKeys {
+		names := append([]string{"BCE_" + strings.ToUpper(key)}, configEnvAliases[key]...)
+
+		for _, name := range names {
+			if value := os.Getenv(name); value != "" {
+				values[key] = value
+				break
+			}
+		}
+	}
+
+	config := NewConfig(nil)
+
+	if err := config.setValues(values); err != nil {
+		return nil, err
+	}
+
+	if option.Overrides != nil {
+		config.merge(option.Overrides)
+	}
+
+	if config.Credentials == nil || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("No credentials, set access_key_id and secret_access_key in the config file, " +
+			"or the environment variables BCE_ACCESS_KEY_ID and BCE_SECRET_ACCESS_KEY.")
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Validate checks the values of bce.Config, such as unknown region, negative timeout and invalid proxy.
+// The error is a *bce.ValidationError whose field is the key of config file, such as "region" and "proxy_port".
+//
+// The region should be a key of bce.Region, a new region can be added to bce.Region before validating.
+func (config *Config) Validate() error {
+	if config.Region != "" {
+		if _, ok := Region[config.Region]; !ok {
+			return NewValidationError("region", fmt.Sprintf("The region %s is not supported. The valid regions are %s.",
+				config.Region, strings.Join(regions(), ", ")))
+		}
+	}
+
+	if config.Protocol != "" && config.Protocol != "http" && config.Protocol != "https" {
+		return NewValidationError("protocol", fmt.Sprintf(
+			"The protocol %s is not supported. The valid protocols are http and https.", config.Protocol))
+	}
+
+	if config.Timeout < 0 {
+		return NewValidationError("timeout", fmt.Sprintf("The timeout %v should not be negative.", config.Timeout))
+	}
+
+	if config.MaxConnections < 0 {
+		return NewValidationError("max_connections", fmt.Sprintf("The max connections %d should not be negative.",
+			config.MaxConnections))
+	}
+
+	if config.RetryPolicy != nil {
+		if config.RetryPolicy.GetMaxErrorRetry() < 0 {
+			return NewValidationError("max_error_retry", fmt.Sprintf("The max error retry %d should not be negative.",
+				config.RetryPolicy.GetMaxErrorRetry()))
+		}
+
+		if config.RetryPolicy.GetMaxDelay() < 0 {
+			return NewValidationError("max_retry_delay", fmt.Sprintf("The max retry delay %v should not be negative.",
+				config.RetryPolicy.GetMaxDelay()))
+		}
+	}
+
+	_, err := config.proxyURL()
+
+	return err
+}
+
+// proxyURL returns the URL of proxy, nil is returned if no proxy is specified.
+func (config *Config) proxyURL() (*url.URL, error) {
+	if config.ProxyPort < 0 || config.ProxyPort > 65535 {
+		return nil, NewValidationError("proxy_port", fmt.Sprintf("The proxy port %d should be from 0 to 65535.",
+			config.ProxyPort))
+	}
+
+	if config.ProxyHost == "" {
+		if config.ProxyPort > 0 {
+			return nil, NewValidationError("proxy_host", fmt.Sprintf(
+				"The proxy host should not be empty if the proxy port is %d.", config.ProxyPort))
+		}
+
+		return nil, nil
+	}
+
+	host := config.ProxyHost
+
+	if config.ProxyPort > 0 {
+		host += ":" + strconv.Itoa(config.ProxyPort)
+	}
+
+	proxyUrl, err := url.Parse(util.HostToURL(host, "http"))
+
+	if err != nil || proxyUrl.Host == "" || strings.Contains(proxyUrl.Host, " ") ||
+		(proxyUrl.Path != "" && proxyUrl.Path != "/") {
+
+		return nil, NewValidationError("proxy_host", fmt.Sprintf("The proxy %s is not a valid host or URL.", host))
+	}
+
+	return proxyUrl, nil
+}
+
+func regions() []string {
+	result := make([]string, 0, len(Region))
+
+	for region := range Region {
+		result = append(result, region)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// loadProfile returns the values of profile in the config file, the values are empty if the config file
+// is not specified and no default config file exists.
+func loadProfile(profile, configFile string) (map[string]string, error) {
+	profileSpecified := profile != "" || os.Getenv("BCE_PROFILE") != ""
+
+	if profile == "" {
+		profile = os.Getenv("BCE_PROFILE")
+	}
+
+	if profile == "" {
+		profile = DEFAULT_PROFILE
+	}
+
+	if configFile == "" {
+		configFile = os.Getenv("BCE_CONFIG_FILE")
+	}
+
+	if configFile == "" {
+		configFile = defaultConfigFile()
+	}
+
+	if configFile == "" {
+		if profileSpecified {
+			return nil, fmt.Errorf("Profile %s is not found, no config file exists.", profile)
+		}
+
+		return make(map[string]string), nil
+	}
+
+	var profiles map[string]map[string]string
+	var err error
+
+	if ext := filepath.Ext(configFile); ext == ".yaml" || ext == ".yml" {
+		profiles, err = readYamlProfiles(configFile)
+	} else {
+		profiles, err = readIniProfiles(configFile)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config file %s: %v", configFile, err)
+	}
+
+	values, ok := profiles[profile]
+
+	if !ok {
+		if profileSpecified {
+			return nil, fmt.Errorf("Profile %s is not found in %s.", profile, configFile)
+		}
+
+		values = make(map[string]string)
+	}
+
+	for key := range values {
+		if !util.Contains(configKeys, key, false) {
+			return nil, fmt.Errorf("Invalid key %s of profile %s in %s.", key, profile, configFile)
+		}
+	}
+
+	return values, nil
+}
+
+// defaultConfigFile returns the first existing file of CONFIG_FILES in the home directory.
+func defaultConfigFile() string {
+	homeDir, err := util.HomeDir()
+
+	if err != nil {
+		return ""
+	}
+
+	for _, configFile := range CONFIG_FILES {
+		filePath := filepath.Join(homeDir, filepath.FromSlash(configFile))
+
+		if util.CheckFileExists(filePath) {
+			return filePath
+		}
+	}
+
+	return ""
+}
+
+// setValues sets the fields of bce.Config by the values of a profile.
+func (config *Config) setValues(values map[string]string) error {
+	var maxErrorRetry, maxRetryDelay string
+
+	for _, key := range configKeys {
+		value, ok := values[key]
+
+		if !ok || value == "" {
+			continue
+		}
+
+		var err error
+
+		switch key {
+		case "access_key_id", "secret_access_key":
+			if config.Credentials == nil {
+				config.Credentials = &Credentials{}
+			}
+
+			if key == "access_key_id" {
+				config.AccessKeyID = value
+			} else {
+				config.SecretAccessKey = value
+			}
+		case "region":
+			config.Region = value
+		case "endpoint":
+			config.Endpoint = value
+		case "api_version":
+			config.APIVersion = value
+		case "protocol":
+			config.Protocol = value
+		case "user_agent":
+			config.UserAgent = value
+		case "proxy_host":
+			config.ProxyHost = value
+		case "proxy_port":
+			config.ProxyPort, err = strconv.Atoi(value)
+		case "max_connections":
+			config.MaxConnections, err = strconv.Atoi(value)
+		case "timeout":
+			config.Timeout, err = parseDuration(value)
+		case "checksum":
+			config.Checksum, err = strconv.ParseBool(value)
+		case "max_error_retry":
+			maxErrorRetry = value
+		case "max_retry_delay":
+			maxRetryDelay = value
+		}
+
+		if err != nil {
+			return fmt.Errorf("Invalid %s %s.", key, value)
+		}
+	}
+
+	if maxErrorRetry != "" || maxRetryDelay != "" {
+		retryPolicy := NewDefaultRetryPolicy(3, 20*time.Second)
+
+		if maxErrorRetry != "" {
+			var err error
+
+			if retryPolicy.MaxErrorRetry, err = strconv.Atoi(maxErrorRetry); err != nil {
+				return fmt.Errorf("Invalid max_error_retry %s.", maxErrorRetry)
+			}
+		}
+
+		if maxRetryDelay != "" {
+			var err error
+
+			if retryPolicy.MaxDelay, err = parseDuration(maxRetryDelay); err != nil {
+				return fmt.Errorf("Invalid max_retry_delay %s.", maxRetryDelay)
+			}
+		}
+
+		config.RetryPolicy = retryPolicy
+	}
+
+	return nil
+}
+
+// merge overrides the fields of bce.Config by the non-zero fields of overrides.
+func (config *Config) merge(overrides *Config) {
+	if overrides.Credentials != nil {
+		config.Credentials = overrides.Credentials
+	}
+
+	fields := [][2]*string{
+		{&config.Region, &overrides.Region},
+		{&config.Endpoint, &overrides.Endpoint},
+		{&config.APIVersion, &overrides.APIVersion},
+		{&config.Protocol, &overrides.Protocol},
+		{&config.UserAgent, &overrides.UserAgent},
+		{&config.ProxyHost, &overrides.ProxyHost},
+	}
+
+	for _, pair := range fields {
+		if *pair[1] != "" {
+			*pair[0] = *pair[1]
+		}
+	}
+
+	if overrides.ProxyPort != 0 {
+		config.ProxyPort = overrides.ProxyPort
+	}
+
+	if overrides.MaxConnections != 0 {
+		config.MaxConnections = overrides.MaxConnections
+	}
+
+	if overrides.Timeout != 0 {
+		config.Timeout = overrides.Timeout
+	}
+
+	if overrides.RetryPolicy != nil {
+		config.RetryPolicy = overrides.RetryPolicy
+	}
+
+	if overrides.Checksum {
+		config.Checksum = true
+	}
+
+	if overrides.WrapTransport != nil {
+		config.WrapTransport = overrides.WrapTransport
+	}
+
+	if overrides.Metrics != nil {
+		config.Metrics = overrides.Metrics
+	}
+
+	if overrides.Tracer != nil {
+		config.Tracer = overrides.Tracer
+	}
+
+	if overrides.HashTraceKey {
+		config.HashTraceKey = true
+	}
+}
+
+// parseDuration parses a duration such as "1m30s", or an integer in seconds.
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+// readIniProfiles reads the INI file of profiles, each section is a profile of "key = value" lines.
+func readIniProfiles(filePath string) (map[string]map[string]string, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	profiles := make(map[string]map[string]string)
+	var values map[string]string
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			values = make(map[string]string)
+			profiles[strings.TrimSpace(line[1:len(line)-1])] = values
+			continue
+		}
+
+		index := strings.Index(line, "=")
+
+		// the line is not echoed in the errors, as it may contain the secret access key
+		if index < 0 {
+			return nil, fmt.Errorf("Invalid line %d.", lineNumber)
+		}
+
+		key := strings.TrimSpace(line[:index])
+
+		if values == nil {
+			return nil, fmt.Errorf("Invalid line %d, key %s is not in any profile.", lineNumber, key)
+		}
+
+		values[key] = unquote(strings.TrimSpace(line[index+1:]))
+	}
+
+	return profiles, scanner.Err()
+}
+
+// readYamlProfiles reads the YAML file of profiles, each top level key is a profile, and the indented
+// "key: value" lines under it are the values. Only this subset of YAML is supported.
+func readYamlProfiles(filePath string) (map[string]map[string]string, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	profiles := make(map[string]map[string]string)
+	var values map[string]string
+	scanner := bufio.NewScanner(file)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		text := stripYamlComment(scanner.Text())
+		line := strings.TrimSpace(text)
+
+		if line == "" || line == "---" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		index := strings.Index(line, ":")
+
+		// the line is not echoed in the errors, as it may contain the secret access key
+		if index < 0 {
+			return nil, fmt.Errorf("Invalid line %d.", lineNumber)
+		}
+
+		key, value := strings.TrimSpace(line[:index]), unquote(strings.TrimSpace(line[index+1:]))
+		indented := strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")
+
+		if !indented && value == "" {
+			values = make(map[string]string)
+			profiles[unquote(key)] = values
+			continue
+		}
+
+		if !indented || values == nil {
+			return nil, fmt.Errorf("Invalid line %d, key %s is not in any profile.", lineNumber, key)
+		}
+
+		values[key] = value
+	}
+
+	return profiles, scanner.Err()
+}
+
+// stripYamlComment removes the comment from a YAML line, a "#" starts a comment only if it is outside
+// the quotes and at the beginning of the line or after a whitespace.
+func stripYamlComment(text string) string {
+	var quote byte
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+
+	return text
+}
+
+// unquote removes the single or double quotes around a value.
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
@@ -0,0 +1,255 @@
+package bce
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+const testIniConfig = `# profiles of bce
+[default]
+access_key_id = ak
+secret_access_key = sk
+
+[gz]
+access_key_id=ak2
+secret_access_key="sk2"
+region = gz
+timeout = 30s
+checksum = true
+max_error_retry = 5
+`
+
+const testYamlConfig = `---
+default:
+  access_key_id: ak
+  secret_access_key: 'sk #1' # quoted
+  proxy_host: 127.0.0.1
+  proxy_port: 8080
+hk:
+  access_key_id: ak3
+  secret_access_key: sk3
+  region: hk
+  max_retry_delay: 10
+`
+
+// withConfigFiles writes the config files to a temp directory, and clears the environment variables
+// of bce.LoadConfig while running f.
+func withConfigFiles(t *testing.T, files map[string]string, f func(dir string)) {
+	dir, err := ioutil.TempDir("", "bce-config")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := []string{"BCE_PROFILE", "BCE_CONFIG_FILE"}
+
+	for _, key := range configKeys {
+		names = append(names, "BCE_"+strings.ToUpper(key))
+		names = append(names, configEnvAliases[key]...)
+	}
+
+	for _, name := range names {
+		value := os.Getenv(name)
+		os.Setenv(name, "")
+		defer os.Setenv(name, value)
+	}
+
+	f(dir)
+}
+
+func TestLoadConfig(t *testing.T) {
+	files := map[string]string{"config": testIniConfig, "config.yaml": testYamlConfig}
+
+	withConfigFiles(t, files, func(dir string) {
+		iniFile := filepath.Join(dir, "config")
+		config, err := LoadConfig(&LoadConfigOption{Profile: "gz", ConfigFile: iniFile})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.AccessKeyID != "ak2" || config.SecretAccessKey != "sk2" || config.Region != "gz" ||
+			config.Timeout != 30*time.Second || !config.Checksum || config.RetryPolicy.GetMaxErrorRetry() != 5 {
+
+			t.Error(util.FormatTest("LoadConfig ini", fmt.Sprintf("%+v", config), "gz profile"))
+		}
+
+		os.Setenv("BCE_REGION", "hk")
+		os.Setenv("BAIDU_BCE_AK", "env-ak")
+
+		config, err = LoadConfig(&LoadConfigOption{
+			ConfigFile: iniFile,
+			Overrides:  &Config{Endpoint: "bos.example.com", Region: "bj"},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.AccessKeyID != "env-ak" || config.SecretAccessKey != "sk" || config.Region != "bj" ||
+			config.Endpoint != "bos.example.com" || config.Checksum || config.RetryPolicy != nil {
+
+			t.Error(util.FormatTest("LoadConfig env and overrides", fmt.Sprintf("%+v", config), "default profile"))
+		}
+
+		os.Setenv("BCE_REGION", "")
+		os.Setenv("BAIDU_BCE_AK", "")
+		os.Setenv("BCE_PROFILE", "hk")
+		os.Setenv("BCE_CONFIG_FILE", filepath.Join(dir, "config.yaml"))
+
+		config, err = LoadConfig(nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.AccessKeyID != "ak3" || config.Region != "hk" || config.RetryPolicy.GetMaxDelay() != 10*time.Second {
+			t.Error(util.FormatTest("LoadConfig yaml", fmt.Sprintf("%+v", config), "hk profile"))
+		}
+
+		config, err = LoadConfig(&LoadConfigOption{Profile: DEFAULT_PROFILE})
+
+		if err != nil || config.SecretAccessKey != "sk #1" || config.ProxyHost != "127.0.0.1" || config.ProxyPort != 8080 {
+			t.Error(util.FormatTest("LoadConfig yaml default", fmt.Sprintf("%+v", config), "default profile"))
+		}
+	})
+}
+
+func TestLoadConfigOverrides(t *testing.T) {
+	withConfigFiles(t, map[string]string{"config": testIniConfig}, func(dir string) {
+		observed := false
+		metrics := metricsFunc(func(requestMetrics *RequestMetrics) {
+			observed = true
+		})
+
+		tracer := &nopTracer{}
+		config, err := LoadConfig(&LoadConfigOption{
+			ConfigFile: filepath.Join(dir, "config"),
+			Overrides:  &Config{Metrics: metrics, Tracer: tracer, HashTraceKey: true},
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if config.Metrics != nil {
+			config.Metrics.ObserveRequest(&RequestMetrics{})
+		}
+
+		if !observed {
+			t.Error(util.FormatTest("LoadConfig overrides Metrics", fmt.Sprintf("%v", config.Metrics), "metrics"))
+		}
+
+		if config.Tracer != Tracer(tracer) || !config.HashTraceKey {
+			t.Error(util.FormatTest("LoadConfig overrides Tracer", fmt.Sprintf("%v %v", config.Tracer,
+				config.HashTraceKey), "tracer true"))
+		}
+	})
+}
+
+// nopTracer is a bce.Tracer which starts no spans.
+type nopTracer struct{}
+
+func (tracer *nopTracer) StartOperation(operation *Operation, header http.Header) Span {
+	return nil
+}
+
+func (tracer *nopTracer) StartAttempt(operationSpan Span, attempt int, header http.Header) Span {
+	return nil
+}
+
+func TestLoadConfigError(t *testing.T) {
+	files := map[string]string{
+		"config":         testIniConfig,
+		"region":         "[default]\naccess_key_id = ak\nsecret_access_key = sk\nregion = mars\n",
+		"timeout":        "[default]\naccess_key_id = ak\nsecret_access_key = sk\ntimeout = -1s\n",
+		"proxy":          "[default]\naccess_key_id = ak\nsecret_access_key = sk\nproxy_host = a b\n",
+		"proxy_port":     "[default]\naccess_key_id = ak\nsecret_access_key = sk\nproxy_port = 8080\n",
+		"unknown_key":    "[default]\naccess_key_id = ak\nsecret_access_key = sk\nregoin = bj\n",
+		"invalid_value":  "[default]\naccess_key_id = ak\nsecret_access_key = sk\nchecksum = maybe\n",
+		"no_credentials": "[default]\nregion = bj\n",
+		"invalid.yaml":   "default:\nregion: bj\n",
+		"invalid_line":   "[default]\naccess_key_id = ak\nsecret_access_key: s3cret\n",
+	}
+
+	withConfigFiles(t, files, func(dir string) {
+		cases := []struct {
+			profile, file, expected string
+		}{
+			{"", "region", "Invalid region. The region mars is not supported."},
+			{"", "timeout", "Invalid timeout. The timeout -1s should not be negative."},
+			{"", "proxy", "Invalid proxy_host. The proxy a b is not a valid host or URL."},
+			{"", "proxy_port", "Invalid proxy_host. The proxy host should not be empty"},
+			{"", "unknown_key", "Invalid key regoin"},
+			{"", "invalid_value", "Invalid checksum maybe."},
+			{"", "no_credentials", "No credentials"},
+			{"", "invalid.yaml", "Invalid line 2, key region"},
+			{"", "invalid_line", "Invalid line 3."},
+			{"", "missing", "no such file"},
+			{"bj", "config", "Profile bj is not found"},
+		}
+
+		for _, c := range cases {
+			_, err := LoadConfig(&LoadConfigOption{Profile: c.profile, ConfigFile: filepath.Join(dir, c.file)})
+
+			// the secret access key should never be in the error
+			if err == nil || !strings.Contains(err.Error(), c.expected) || strings.Contains(err.Error(), "s3cret") {
+				t.Error(util.FormatTest("LoadConfig "+c.file, fmt.Sprint(err), c.expected))
+			}
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	valid := []*Config{
+		{},
+		NewConfig(nil),
+		{Region: "gz", Protocol: "https", ProxyHost: "guoyao.me", ProxyPort: 8000},
+		{ProxyHost: "http://127.0.0.1:8080"},
+	}
+
+	for _, config := range valid {
+		if err := config.Validate(); err != nil {
+			t.Error(util.FormatTest("Validate", err.Error(), "nil"))
+		}
+	}
+
+	invalid := []struct {
+		config *Config
+		field  string
+	}{
+		{&Config{Region: "mars"}, "region"},
+		{&Config{Protocol: "ftp"}, "protocol"},
+		{&Config{Timeout: -time.Second}, "timeout"},
+		{&Config{MaxConnections: -1}, "max_connections"},
+		{&Config{RetryPolicy: NewDefaultRetryPolicy(-1, time.Second)}, "max_error_retry"},
+		{&Config{RetryPolicy: NewDefaultRetryPolicy(3, -time.Second)}, "max_retry_delay"},
+		{&Config{ProxyHost: "guoyao.me", ProxyPort: 70000}, "proxy_port"},
+		{&Config{ProxyHost: "guoyao.me/proxy"}, "proxy_host"},
+		{&Config{ProxyPort: 8080}, "proxy_host"},
+	}
+
+	for _, c := range invalid {
+		err, ok := c.config.Validate().(*ValidationError)
+
+		if !ok || err.Field != c.field {
+			t.Error(util.FormatTest("Validate "+fmt.Sprintf("%+v", c.config), fmt.Sprint(err), c.field))
+		}
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"log"
 	"math"
 	"net/http"
-	"net/url"
 	"runtime"
 	"sort"
 	"strconv"
@@ -82,6 +81,19 @@ type Config struct {
 	Timeout        time.Duration // default value: 0 in http.Client
 	RetryPolicy    RetryPolicy
 	Checksum       bool
+
+	// Metrics collects the metrics of each request sent by bce.Client if it is not nil.
+	Metrics Metrics
+
+	// Tracer starts the spans of each request sent by bce.Client if it is not nil.
+	Tracer Tracer
+
+	// HashTraceKey hashes the object keys set to the spans by SHA-256, if the keys contain private data.
+	HashTraceKey bool
+
+	// WrapTransport wraps the http transport of bce.Client if it is not nil, such as recording or replaying
+	// the requests and responses, the transport passed in has been configured by the proxy and connections.
+	WrapTransport func(transport http.RoundTripper) http.RoundTripper
 }
 
 func NewConfig(credentials *Credentials) *Config {
@@ -288,8 +300,9 @@ func (option *SignOption) init() {
 	} else if util.Contains(option.HeadersToSign, "date", true) {
 		if !util.MapContains(option.Headers, generateHeaderValidCompareFunc("date")) {
 			option.Headers["date"] = time.Now().Format(time.RFC1123)
-		} else {
-			option.Headers["date"] = util.TimeStringToRFC1123(util.GetMapValue(option.Headers, "date", true))
+		} else if date, err := util.TimeStringToRFC1123(util.GetMapValue(option.Headers, "date", true)); err == nil {
+			// an invalid date is sent as it is, and the request is rejected by the server
+			option.Headers["date"] = date
 		}
 	} else {
 		if !util.MapContains(option.Headers, generateHeaderValidCompareFunc("x-bce-date")) {
@@ -341,10 +354,17 @@ type Client struct {
 	*Config
 	httpClient *http.Client
 	debug      bool
+
+	// configError is the error of an invalid config, it is returned by SendRequest.
+	configError error
 }
 
+// NewClient returns a bce.Client, if the config is invalid, such as an invalid proxy,
+// the error is returned by each request of the client.
 func NewClient(config *Config) *Client {
-	return &Client{config, newHttpClient(config), false}
+	httpClient, err := newHttpClient(config)
+
+	return &Client{config, httpClient, false, err}
 }
 
 // SetDebug enables debug mode of bce.Client instance.
@@ -352,7 +372,7 @@ func (c *Client) SetDebug(debug bool) {
 	c.debug = debug
 }
 
-func newHttpClient(config *Config) *http.Client {
+func newHttpClient(config *Config) (*http.Client, error) {
 	transport := new(http.Transport)
 
 	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
@@ -361,19 +381,9 @@ func newHttpClient(config *Config) *http.Client {
 		transport.TLSHandshakeTimeout = defaultTransport.TLSHandshakeTimeout
 	}
 
-	if config.ProxyHost != "" {
-		host := config.ProxyHost
-
-		if config.ProxyPort > 0 {
-			host += ":" + strconv.Itoa(config.ProxyPort)
-		}
-
-		proxyUrl, err := url.Parse(util.HostToURL(host, "http"))
-
-		if err != nil {
-			panic(err)
-		}
+	proxyUrl, err := config.proxyURL()
 
+	if proxyUrl != nil {
 		transport.Proxy = http.ProxyURL(proxyUrl)
 	}
 
@@ -387,10 +397,16 @@ func newHttpClient(config *Config) *http.Client {
 		transport.MaxIdleConnsPerHost = config.MaxConnections
 	}
 
+	var roundTripper http.RoundTripper = transport
+
+	if config.WrapTransport != nil {
+		roundTripper = config.WrapTransport(transport)
+	}
+
 	return &http.Client{
-		Transport: transport,
+		Transport: roundTripper,
 		Timeout:   config.Timeout,
-	}
+	}, err
 }
 
 // GetURL generates the full URL of http request for Baidu Cloud API.
@@ -472,7 +488,7 @@ func (c *Client) GetSessionToken(sessionTokenRequest SessionTokenRequest,
 	option = CheckSignOption(option)
 	option.AddHeader("Content-Type", "application/json")
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.SendOperationRequest(&Operation{Service: "sts", Name: "GetSessionToken"}, req, option)
 
 	if err != nil {
 		return nil, err
@@ -495,11 +511,35 @@ func (c *Client) GetSessionToken(sessionTokenRequest SessionTokenRequest,
 }
 
 // SendRequest sends a http request to the endpoint of Baidu Cloud API.
-func (c *Client) SendRequest(req *Request, option *SignOption) (bceResponse *Response, err error) {
+func (c *Client) SendRequest(req *Request, option *SignOption) (*Response, error) {
+	return c.SendOperationRequest(nil, req, option)
+}
+
+// SendOperationRequest sends a http request of the operation to the endpoint of Baidu Cloud API,
+// the operation is reported to the bce.Metrics of bce.Config, it can be nil if unknown.
+func (c *Client) SendOperationRequest(operation *Operation, req *Request,
+	option *SignOption) (bceResponse *Response, err error) {
+
+	attempts, bytesSent, startTime := 0, int64(0), time.Now()
+
+	if c.Metrics != nil {
+		defer func() {
+			c.Metrics.ObserveRequest(newRequestMetrics(operation, req, bceResponse, err,
+				attempts, bytesSent, time.Since(startTime)))
+		}()
+	}
+
 	if option == nil {
 		option = &SignOption{}
 	}
 
+	trace := c.startTrace(operation, req, option)
+	defer func() { trace.end(bceResponse, err, attempts) }()
+
+	if c.configError != nil {
+		return nil, c.configError
+	}
+
 	option.AddHeader("User-Agent", c.GetUserAgent())
 
 	if c.RetryPolicy == nil {
@@ -508,6 +548,7 @@ func (c *Client) SendRequest(req *Request, option *SignOption) (bceResponse *Res
 
 	for i := 0; ; i++ {
 		bceResponse, err = nil, nil
+		trace.startAttempt(i+1, req)
 
 		if option.Credentials != nil {
 			GenerateAuthorization(*option.Credentials, *req, option)
@@ -515,11 +556,19 @@ func (c *Client) SendRequest(req *Request, option *SignOption) (bceResponse *Res
 			GenerateAuthorization(*c.Credentials, *req, option)
 		}
 
+		trace.signed(req, option)
+
 		if c.debug {
 			util.Debug("", fmt.Sprintf("Request: httpMethod = %s, requestUrl = %s, requestHeader = %v",
 				req.Method, req.URL.String(), req.Header))
 		}
 
+		attempts++
+
+		if req.ContentLength > 0 {
+			bytesSent += req.ContentLength
+		}
+
 		resp, httpError := c.httpClient.Do(req.raw())
 
 		if c.debug {
@@ -535,6 +584,7 @@ func (c *Client) SendRequest(req *Request, option *SignOption) (bceResponse *Res
 
 		if httpError != nil {
 			duration := c.RetryPolicy.GetDelayBeforeNextRetry(httpError, i+1)
+			trace.endAttempt(nil, httpError, duration)
 
 			if duration <= 0 {
 				err = httpError
@@ -553,10 +603,12 @@ func (c *Client) SendRequest(req *Request, option *SignOption) (bceResponse *Res
 		}
 
 		if err == nil {
+			trace.endAttempt(resp, nil, 0)
 			return
 		}
 
 		duration := c.RetryPolicy.GetDelayBeforeNextRetry(err, i+1)
+		trace.endAttempt(resp, err, duration)
 
 		if duration <= 0 {
 			return
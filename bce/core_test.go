@@ -2,6 +2,7 @@ package bce
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
@@ -360,7 +361,7 @@ func TestInit(t *testing.T) {
 		Headers:       map[string]string{"content-type": "text/plain", "date": "2015-11-16T07:33:15Z"},
 		HeadersToSign: []string{"content-type", "date"},
 	}
-	expectedDate := util.TimeStringToRFC1123(signOption.Headers["date"])
+	expectedDate, _ := util.TimeStringToRFC1123(signOption.Headers["date"])
 	signOption.init()
 
 	if signOption.Headers["date"] != expectedDate {
@@ -502,11 +503,33 @@ func TestNewHttpClient(t *testing.T) {
 		MaxConnections: 10,
 	}
 
-	httpClient := newHttpClient(config)
+	httpClient, err := newHttpClient(config)
 
-	if httpClient == nil {
+	if httpClient == nil || err != nil {
 		t.Error(util.FormatTest("newHttpClient", "nil", "valid http client"))
 	}
+
+	var wrapped http.RoundTripper
+	config.WrapTransport = func(transport http.RoundTripper) http.RoundTripper {
+		wrapped = transport
+		return http.DefaultTransport
+	}
+
+	httpClient, _ = newHttpClient(config)
+
+	if httpClient.Transport != http.DefaultTransport {
+		t.Error(util.FormatTest("newHttpClient", fmt.Sprintf("%T", httpClient.Transport), "wrapped transport"))
+	}
+
+	if transport, ok := wrapped.(*http.Transport); !ok || transport.MaxIdleConnsPerHost != 10 {
+		t.Error(util.FormatTest("newHttpClient", fmt.Sprintf("%T", wrapped), "configured *http.Transport"))
+	}
+
+	config.ProxyHost = "guoyao.me/proxy"
+
+	if _, err := NewClient(config).SendRequest(nil, nil); err == nil {
+		t.Error(util.FormatTest("NewClient with invalid proxy", "nil", "error"))
+	}
 }
 
 func TestSetDebug(t *testing.T) {
@@ -20,6 +20,22 @@ func (err *Error) Error() string {
 		err.Message, err.Code, err.StatusCode, err.RequestID)
 }
 
+// ValidationError is returned if an argument of a method is invalid, no request is sent for it.
+type ValidationError struct {
+	// Field is the name of the invalid argument, such as "bucketName" or "partNumber".
+	Field   string
+	Message string
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{field, message}
+}
+
+// Error returns the formatted error message.
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("Invalid %s. %s", err.Field, err.Message)
+}
+
 func buildError(resp *Response) error {
 	bodyContent, err := resp.GetBodyContent()
 
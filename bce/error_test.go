@@ -25,6 +25,15 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestValidationError(t *testing.T) {
+	result := NewValidationError("bucketName", "The bucket name should not be empty.").Error()
+	expected := "Invalid bucketName. The bucket name should not be empty."
+
+	if result != expected {
+		t.Error(util.FormatTest("ValidationError", result, expected))
+	}
+}
+
 func TestBuildError(t *testing.T) {
 	resp := &Response{BodyContent: []byte{}}
 	err := buildError(resp)
@@ -0,0 +1,90 @@
+package bce
+
+import (
+	"time"
+)
+
+// Operation is a logical call of Baidu Cloud API sent by bce.Client, such as PutObject of BOS.
+//
+// The service packages, such as bos, send each request with the operation of the method,
+// so that it can be reported to the bce.Metrics of bce.Config.
+type Operation struct {
+	Service string // Service is the service of Baidu Cloud, such as "bos" and "sts".
+	Name    string // Name is the name of method, such as "PutObject".
+	Bucket  string // Bucket is the BOS Bucket of the operation, it is empty if not a BOS operation.
+	Key     string // Key is the BOS Object of the operation, it is empty if not a BOS Object operation.
+}
+
+// RequestMetrics contains the metrics of a request sent by bce.Client, including all retries.
+type RequestMetrics struct {
+	Operation
+
+	// Method is the http method of the request.
+	Method string
+
+	// StatusCode is the status code of the last response, it is 0 if no response is received.
+	StatusCode int
+
+	// ErrorCode is the Code of bce.Error returned, it is empty if the request succeeds or fails without response.
+	ErrorCode string
+
+	// Err is the error returned by SendRequest, it is nil if the request succeeds.
+	Err error
+
+	// Attempts is the count of http requests sent, it is more than 1 if the request is retried.
+	Attempts int
+
+	// BytesSent is the total Content-Length of the requests sent by all attempts.
+	BytesSent int64
+
+	// BytesReceived is the Content-Length of the last response, the body of response is not read for it.
+	BytesReceived int64
+
+	// Duration is the time from the first attempt to the last response, including the delays of retries.
+	Duration time.Duration
+}
+
+// Metrics defined an interface for collecting the metrics of bce.Client, such as the count, latency and errors
+// of requests. The methods are called by concurrent requests, the implementations should be safe for it.
+//
+// Package bce/prometheus implements it for Prometheus.
+type Metrics interface {
+	// ObserveRequest is called when SendRequest returns.
+	ObserveRequest(requestMetrics *RequestMetrics)
+}
+
+func newRequestMetrics(operation *Operation, req *Request, bceResponse *Response, err error,
+	attempts int, bytesSent int64, duration time.Duration) *RequestMetrics {
+
+	requestMetrics := &RequestMetrics{
+		Method:    req.Method,
+		Err:       err,
+		Attempts:  attempts,
+		BytesSent: bytesSent,
+		Duration:  duration,
+	}
+
+	if operation != nil {
+		requestMetrics.Operation = *operation
+	}
+
+	if bceResponse != nil && bceResponse.Response != nil {
+		requestMetrics.StatusCode = bceResponse.StatusCode
+		requestMetrics.BytesReceived = bceResponse.ContentLength
+
+		// the body of an error response has been read by SendRequest
+		if requestMetrics.BytesReceived < 0 {
+			requestMetrics.BytesReceived = int64(len(bceResponse.BodyContent))
+		}
+	}
+
+	if bceError, ok := err.(*Error); ok {
+		requestMetrics.ErrorCode = bceError.Code
+
+		if requestMetrics.StatusCode == 0 {
+			requestMetrics.StatusCode = bceError.StatusCode
+		}
+	}
+
+	return requestMetrics
+}
@@ -0,0 +1,69 @@
+package bce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestNewRequestMetrics(t *testing.T) {
+	req, _ := NewRequest("PUT", "http://bucket.bj.bcebos.com/a.txt", nil)
+	operation := &Operation{Service: "bos", Name: "PutObject", Bucket: "bucket", Key: "a.txt"}
+	resp := NewResponse(&http.Response{StatusCode: http.StatusOK, ContentLength: 0})
+
+	requestMetrics := newRequestMetrics(operation, req, resp, nil, 2, 10, time.Second)
+	expected := RequestMetrics{Operation: *operation, Method: "PUT", StatusCode: http.StatusOK, Attempts: 2,
+		BytesSent: 10, Duration: time.Second}
+
+	if *requestMetrics != expected {
+		t.Error(util.FormatTest("newRequestMetrics", fmt.Sprintf("%+v", requestMetrics), fmt.Sprintf("%+v", expected)))
+	}
+
+	bceError := &Error{StatusCode: http.StatusNotFound, Code: "NoSuchKey"}
+	resp = NewResponse(&http.Response{StatusCode: http.StatusNotFound, ContentLength: -1})
+	resp.BodyContent = []byte(`{"code":"NoSuchKey"}`)
+
+	requestMetrics = newRequestMetrics(nil, req, resp, bceError, 1, 0, time.Second)
+
+	if requestMetrics.Name != "" || requestMetrics.StatusCode != http.StatusNotFound ||
+		requestMetrics.ErrorCode != "NoSuchKey" || requestMetrics.BytesReceived != int64(len(resp.BodyContent)) {
+
+		t.Error(util.FormatTest("newRequestMetrics error", fmt.Sprintf("%+v", requestMetrics), "NoSuchKey"))
+	}
+
+	requestMetrics = newRequestMetrics(operation, req, nil, errors.New("connection refused"), 4, 0, time.Second)
+
+	if requestMetrics.StatusCode != 0 || requestMetrics.ErrorCode != "" || requestMetrics.Attempts != 4 {
+		t.Error(util.FormatTest("newRequestMetrics no response", fmt.Sprintf("%+v", requestMetrics), "StatusCode 0"))
+	}
+}
+
+func TestSendOperationRequestMetrics(t *testing.T) {
+	var observed []*RequestMetrics
+
+	config := &Config{
+		Credentials: NewCredentials("ak", "sk"),
+		ProxyHost:   "a b",
+		Metrics:     metricsFunc(func(requestMetrics *RequestMetrics) { observed = append(observed, requestMetrics) }),
+	}
+
+	client := NewClient(config)
+	req, _ := NewRequest("GET", "http://bj.bcebos.com/", nil)
+	_, err := client.SendOperationRequest(&Operation{Service: "bos", Name: "ListBuckets"}, req, nil)
+
+	if err == nil || len(observed) != 1 || observed[0].Name != "ListBuckets" || observed[0].Err != err ||
+		observed[0].Attempts != 0 {
+
+		t.Error(util.FormatTest("SendOperationRequest", fmt.Sprintf("%v %+v", err, observed), "config error observed"))
+	}
+}
+
+type metricsFunc func(requestMetrics *RequestMetrics)
+
+func (f metricsFunc) ObserveRequest(requestMetrics *RequestMetrics) {
+	f(requestMetrics)
+}
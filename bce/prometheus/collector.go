@@ -0,0 +1,241 @@
+// Package prometheus collects the metrics of bce.Client, and exposes them in the text format of Prometheus,
+// so they can be scraped without any dependency of the Prometheus client library.
+//
+// The collector is plugged in by the Metrics of bce.Config, and served as a http handler:
+//
+//	collector := prometheus.NewCollector(nil)
+//	config.Metrics = collector
+//	http.Handle("/metrics", collector)
+//
+// The metrics are labeled by service, operation, bucket, method, status and error_code,
+// the bucket label can be disabled by prometheus.CollectorOption if there are too many buckets.
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// DEFAULT_NAMESPACE is the prefix of metric names.
+const DEFAULT_NAMESPACE = "bce"
+
+// CONTENT_TYPE is the content type of the text format of Prometheus.
+const CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds in seconds of the histogram of request duration.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// CollectorOption contains all options for prometheus.Collector.
+type CollectorOption struct {
+	Namespace string    // Namespace is the prefix of metric names, default value: DEFAULT_NAMESPACE
+	Buckets   []float64 // Buckets are the upper bounds of histogram, default value: DefaultBuckets
+
+	// DisableBucketLabel removes the bucket label of metrics, the requests of all buckets are
+	// collected together, it is recommended if the client accesses a lot of buckets.
+	DisableBucketLabel bool
+}
+
+// Collector implements bce.Metrics and http.Handler, it is safe for concurrent requests.
+type Collector struct {
+	namespace   string
+	buckets     []float64
+	bucketLabel bool
+	labelNames  []string
+
+	mutex  sync.Mutex
+	series map[string]*series
+}
+
+// series contains the metrics of requests with the same label values.
+type series struct {
+	labelValues   []string
+	requests      int64
+	attempts      int64
+	bytesSent     int64
+	bytesReceived int64
+
+	// durationCounts are the counts of requests whose duration are in each bucket, not cumulative.
+	durationCounts []int64
+	durationSum    float64
+}
+
+// NewCollector returns a prometheus.Collector, the option can be nil.
+func NewCollector(option *CollectorOption) *Collector {
+	if option == nil {
+		option = &CollectorOption{}
+	}
+
+	collector := &Collector{
+		namespace:   option.Namespace,
+		buckets:     option.Buckets,
+		bucketLabel: !option.DisableBucketLabel,
+		series:      make(map[string]*series),
+	}
+
+	if collector.namespace == "" {
+		collector.namespace = DEFAULT_NAMESPACE
+	}
+
+	if len(collector.buckets) == 0 {
+		collector.buckets = DefaultBuckets
+	}
+
+	collector.buckets = append([]float64(nil), collector.buckets...)
+	sort.Float64s(collector.buckets)
+
+	collector.labelNames = []string{"service", "operation"}
+
+	if collector.bucketLabel {
+		collector.labelNames = append(collector.labelNames, "bucket")
+	}
+
+	collector.labelNames = append(collector.labelNames, "method", "status", "error_code")
+
+	return collector
+}
+
+// ObserveRequest implements bce.Metrics.
+func (collector *Collector) ObserveRequest(requestMetrics *bce.RequestMetrics) {
+	labelValues := []string{requestMetrics.Service, requestMetrics.Name}
+
+	if collector.bucketLabel {
+		labelValues = append(labelValues, requestMetrics.Bucket)
+	}
+
+	labelValues = append(labelValues, requestMetrics.Method, strconv.Itoa(requestMetrics.StatusCode),
+		requestMetrics.ErrorCode)
+
+	key := strings.Join(labelValues, "\xff")
+	seconds := requestMetrics.Duration.Seconds()
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	s, ok := collector.series[key]
+
+	if !ok {
+		s = &series{labelValues: labelValues, durationCounts: make([]int64, len(collector.buckets)+1)}
+		collector.series[key] = s
+	}
+
+	s.requests++
+	s.attempts += int64(requestMetrics.Attempts)
+	s.bytesSent += requestMetrics.BytesSent
+	s.bytesReceived += requestMetrics.BytesReceived
+	s.durationCounts[sort.SearchFloat64s(collector.buckets, seconds)]++
+	s.durationSum += seconds
+}
+
+// Write writes all metrics in the text format of Prometheus, the series are sorted by the label values.
+func (collector *Collector) Write(w io.Writer) error {
+	collector.mutex.Lock()
+
+	keys := make([]string, 0, len(collector.series))
+
+	for key := range collector.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	// the series are copied, so the requests are not blocked by a slow writer
+	seriesList := make([]series, len(keys))
+
+	for i, key := range keys {
+		seriesList[i] = *collector.series[key]
+		seriesList[i].durationCounts = append([]int64(nil), seriesList[i].durationCounts...)
+	}
+
+	collector.mutex.Unlock()
+
+	bw := bufio.NewWriter(w)
+
+	counters := []struct {
+		name, help string
+		value      func(s *series) int64
+	}{
+		{"requests_total", "Total number of requests sent by the client.",
+			func(s *series) int64 { return s.requests }},
+		{"request_attempts_total", "Total number of http attempts of requests, including retries.",
+			func(s *series) int64 { return s.attempts }},
+		{"request_sent_bytes_total", "Total bytes of request bodies sent, including retries.",
+			func(s *series) int64 { return s.bytesSent }},
+		{"request_received_bytes_total", "Total bytes of response bodies received.",
+			func(s *series) int64 { return s.bytesReceived }},
+	}
+
+	for _, counter := range counters {
+		name := collector.namespace + "_" + counter.name
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n", name, counter.help, name)
+
+		for i := range seriesList {
+			s := &seriesList[i]
+			fmt.Fprintf(bw, "%s{%s} %d\n", name, collector.labels(s, ""), counter.value(s))
+		}
+	}
+
+	name := collector.namespace + "_request_duration_seconds"
+	fmt.Fprintf(bw, "# HELP %s Duration of requests, including the delays of retries.\n", name)
+	fmt.Fprintf(bw, "# TYPE %s histogram\n", name)
+
+	for i := range seriesList {
+		s := &seriesList[i]
+		var count int64
+
+		for j, upperBound := range collector.buckets {
+			count += s.durationCounts[j]
+			fmt.Fprintf(bw, "%s_bucket{%s} %d\n", name, collector.labels(s, formatFloat(upperBound)), count)
+		}
+
+		count += s.durationCounts[len(collector.buckets)]
+		fmt.Fprintf(bw, "%s_bucket{%s} %d\n", name, collector.labels(s, formatFloat(math.Inf(1))), count)
+		fmt.Fprintf(bw, "%s_sum{%s} %s\n", name, collector.labels(s, ""), formatFloat(s.durationSum))
+		fmt.Fprintf(bw, "%s_count{%s} %d\n", name, collector.labels(s, ""), count)
+	}
+
+	return bw.Flush()
+}
+
+// ServeHTTP implements http.Handler, it serves the metrics to the scraper of Prometheus.
+func (collector *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", CONTENT_TYPE)
+	collector.Write(w)
+}
+
+// labels formats the labels of series, the le label of histogram is appended if it is not empty.
+func (collector *Collector) labels(s *series, le string) string {
+	pairs := make([]string, 0, len(s.labelValues)+1)
+
+	for i, labelName := range collector.labelNames {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labelName, escapeLabelValue(s.labelValues[i])))
+	}
+
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf("le=\"%s\"", le))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+var labelValueReplacer = strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,106 @@
+package prometheus
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector(&CollectorOption{Buckets: []float64{1, 0.1}})
+
+	putObject := &bce.RequestMetrics{
+		Operation:  bce.Operation{Service: "bos", Name: "PutObject", Bucket: "bucket-0", Key: "a.txt"},
+		Method:     "PUT",
+		StatusCode: http.StatusOK,
+		Attempts:   2,
+		BytesSent:  20,
+		Duration:   500 * time.Millisecond,
+	}
+
+	collector.ObserveRequest(putObject)
+	collector.ObserveRequest(putObject)
+	collector.ObserveRequest(&bce.RequestMetrics{
+		Operation:     bce.Operation{Service: "bos", Name: "GetObject", Bucket: "bucket\"0\"", Key: "b.txt"},
+		Method:        "GET",
+		StatusCode:    http.StatusNotFound,
+		ErrorCode:     "NoSuchKey",
+		Attempts:      1,
+		BytesReceived: 100,
+		Duration:      2 * time.Second,
+	})
+
+	w := httptest.NewRecorder()
+	collector.ServeHTTP(w, nil)
+
+	if contentType := w.Header().Get("Content-Type"); contentType != CONTENT_TYPE {
+		t.Error(util.FormatTest("Collector Content-Type", contentType, CONTENT_TYPE))
+	}
+
+	getLabels := `service="bos",operation="GetObject",bucket="bucket\"0\"",method="GET",status="404",error_code="NoSuchKey"`
+	putLabels := `service="bos",operation="PutObject",bucket="bucket-0",method="PUT",status="200",error_code=""`
+
+	expected := []string{
+		`# TYPE bce_requests_total counter`,
+		`bce_requests_total{` + getLabels + `} 1`,
+		`bce_requests_total{` + putLabels + `} 2`,
+		`bce_request_attempts_total{` + putLabels + `} 4`,
+		`bce_request_sent_bytes_total{` + putLabels + `} 40`,
+		`bce_request_received_bytes_total{` + getLabels + `} 100`,
+		`# TYPE bce_request_duration_seconds histogram`,
+		`bce_request_duration_seconds_bucket{` + getLabels + `,le="1"} 0`,
+		`bce_request_duration_seconds_bucket{` + getLabels + `,le="+Inf"} 1`,
+		`bce_request_duration_seconds_bucket{` + putLabels + `,le="0.1"} 0`,
+		`bce_request_duration_seconds_bucket{` + putLabels + `,le="1"} 2`,
+		`bce_request_duration_seconds_sum{` + putLabels + `} 1`,
+		`bce_request_duration_seconds_count{` + putLabels + `} 2`,
+	}
+
+	body := w.Body.String()
+
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Error(util.FormatTest("Collector", body, line))
+		}
+	}
+
+	// the series are sorted by label values, GetObject is before PutObject
+	if strings.Index(body, getLabels) > strings.Index(body, putLabels) {
+		t.Error(util.FormatTest("Collector sort", body, "sorted series"))
+	}
+}
+
+func TestCollectorOption(t *testing.T) {
+	collector := NewCollector(&CollectorOption{Namespace: "app_bce", DisableBucketLabel: true})
+
+	for _, bucketName := range []string{"bucket-0", "bucket-1"} {
+		collector.ObserveRequest(&bce.RequestMetrics{
+			Operation:  bce.Operation{Service: "bos", Name: "ListObjects", Bucket: bucketName},
+			Method:     "GET",
+			StatusCode: http.StatusOK,
+			Attempts:   1,
+		})
+	}
+
+	var buf bytes.Buffer
+
+	if err := collector.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `app_bce_requests_total{service="bos",operation="ListObjects",method="GET",status="200",error_code=""} 2`
+
+	if !strings.Contains(buf.String(), expected+"\n") || strings.Contains(buf.String(), "bucket=") {
+		t.Error(util.FormatTest("CollectorOption", buf.String(), expected))
+	}
+
+	if strings.Count(buf.String(), `le="`) != len(DefaultBuckets)+1 {
+		t.Error(util.FormatTest("CollectorOption buckets", buf.String(), "DefaultBuckets"))
+	}
+}
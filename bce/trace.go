@@ -0,0 +1,217 @@
+package bce
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// TRACE_ATTRIBUTE is the key of attributes set to the spans by bce.Client.
+const TRACE_ATTRIBUTE_SERVICE = "bce.service"
+const TRACE_ATTRIBUTE_OPERATION = "bce.operation"
+const TRACE_ATTRIBUTE_BUCKET = "bos.bucket"
+const TRACE_ATTRIBUTE_KEY = "bos.key"
+const TRACE_ATTRIBUTE_METHOD = "http.method"
+const TRACE_ATTRIBUTE_HOST = "net.peer.name"
+const TRACE_ATTRIBUTE_STATUS_CODE = "http.status_code"
+const TRACE_ATTRIBUTE_REQUEST_ID = "bce.request_id"
+const TRACE_ATTRIBUTE_ERROR_CODE = "bce.error_code"
+const TRACE_ATTRIBUTE_ATTEMPT = "bce.attempt"
+const TRACE_ATTRIBUTE_ATTEMPTS = "bce.attempts"
+const TRACE_ATTRIBUTE_RETRY_DELAY = "bce.retry_delay"
+
+// TRACE_EVENT is the name of events added to the spans by bce.Client.
+const TRACE_EVENT_SIGNED = "signed"
+const TRACE_EVENT_RETRY = "retry"
+
+// Span defined an interface for a span of tracing, it can be implemented by an adapter of tracing library,
+// such as OpenTelemetry and OpenTracing.
+type Span interface {
+	// SetAttribute sets an attribute of the span, the value is a string, an int or a time.Duration.
+	SetAttribute(key string, value interface{})
+
+	// AddEvent adds an event happened now to the span, the attributes can be nil.
+	AddEvent(name string, attributes map[string]interface{})
+
+	// End ends the span, err is the error of the operation or the attempt, nil if it succeeds.
+	End(err error)
+}
+
+// Tracer defined an interface for tracing of bce.Client, a span is started for each operation sent by
+// SendOperationRequest, and a child span is started for each http attempt of it, including retries.
+//
+// Package bce/tracing implements it with the W3C Trace Context.
+type Tracer interface {
+	// StartOperation starts the span of an operation, the header contains the headers of bce.SignOption,
+	// so the trace context of caller, such as "traceparent", can be passed in by SignOption.AddHeader.
+	StartOperation(operation *Operation, header http.Header) Span
+
+	// StartAttempt starts the child span of a http attempt, attempt starts from 1. The trace context
+	// is propagated to the service by setting it to the header, only the headers which are not signed,
+	// such as "traceparent", are sent, because the request has been signed.
+	StartAttempt(operationSpan Span, attempt int, header http.Header) Span
+}
+
+// requestTrace is the tracing of a request sent by SendOperationRequest, its methods do nothing if it is nil.
+type requestTrace struct {
+	tracer      Tracer
+	span        Span
+	attemptSpan Span
+
+	// attemptHeader is the header set by Tracer.StartAttempt, it is sent with the request after signing.
+	attemptHeader http.Header
+}
+
+func (c *Client) startTrace(operation *Operation, req *Request, option *SignOption) *requestTrace {
+	if c.Tracer == nil {
+		return nil
+	}
+
+	if operation == nil {
+		operation = &Operation{}
+	}
+
+	header := make(http.Header, len(option.Headers))
+
+	for key, value := range option.Headers {
+		header.Set(key, value)
+	}
+
+	span := c.Tracer.StartOperation(operation, header)
+	span.SetAttribute(TRACE_ATTRIBUTE_SERVICE, operation.Service)
+	span.SetAttribute(TRACE_ATTRIBUTE_OPERATION, operation.Name)
+	span.SetAttribute(TRACE_ATTRIBUTE_METHOD, req.Method)
+
+	if operation.Bucket != "" {
+		span.SetAttribute(TRACE_ATTRIBUTE_BUCKET, operation.Bucket)
+	}
+
+	if operation.Key != "" {
+		key := operation.Key
+
+		if c.HashTraceKey {
+			key = hashTraceKey(key)
+		}
+
+		span.SetAttribute(TRACE_ATTRIBUTE_KEY, key)
+	}
+
+	return &requestTrace{tracer: c.Tracer, span: span}
+}
+
+func (trace *requestTrace) startAttempt(attempt int, req *Request) {
+	if trace == nil {
+		return
+	}
+
+	trace.attemptHeader = make(http.Header)
+	trace.attemptSpan = trace.tracer.StartAttempt(trace.span, attempt, trace.attemptHeader)
+	trace.attemptSpan.SetAttribute(TRACE_ATTRIBUTE_ATTEMPT, attempt)
+	trace.attemptSpan.SetAttribute(TRACE_ATTRIBUTE_METHOD, req.Method)
+	trace.attemptSpan.SetAttribute(TRACE_ATTRIBUTE_HOST, req.URL.Host)
+}
+
+// signed sets the trace context of the attempt to the signed request, the headers which would be
+// signed are dropped, otherwise the signature of the request is broken.
+func (trace *requestTrace) signed(req *Request, option *SignOption) {
+	if trace == nil {
+		return
+	}
+
+	trace.attemptSpan.AddEvent(TRACE_EVENT_SIGNED, nil)
+
+	for key, values := range trace.attemptHeader {
+		if isSignedHeader(key, option) {
+			continue
+		}
+
+		req.Header.Del(key)
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
+
+// endAttempt ends the span of an attempt, retryDelay is the delay before the next attempt,
+// it is not positive if the request is not retried.
+func (trace *requestTrace) endAttempt(resp *http.Response, err error, retryDelay time.Duration) {
+	if trace == nil {
+		return
+	}
+
+	setResponseAttributes(trace.attemptSpan, resp, err)
+
+	if retryDelay > 0 {
+		trace.attemptSpan.SetAttribute(TRACE_ATTRIBUTE_RETRY_DELAY, retryDelay)
+	}
+
+	trace.attemptSpan.End(err)
+	trace.attemptSpan = nil
+
+	if retryDelay > 0 {
+		trace.span.AddEvent(TRACE_EVENT_RETRY, map[string]interface{}{TRACE_ATTRIBUTE_RETRY_DELAY: retryDelay})
+	}
+}
+
+func (trace *requestTrace) end(bceResponse *Response, err error, attempts int) {
+	if trace == nil {
+		return
+	}
+
+	var resp *http.Response
+
+	if bceResponse != nil {
+		resp = bceResponse.Response
+	}
+
+	setResponseAttributes(trace.span, resp, err)
+	trace.span.SetAttribute(TRACE_ATTRIBUTE_ATTEMPTS, attempts)
+	trace.span.End(err)
+}
+
+func setResponseAttributes(span Span, resp *http.Response, err error) {
+	requestId := ""
+
+	if resp != nil {
+		span.SetAttribute(TRACE_ATTRIBUTE_STATUS_CODE, resp.StatusCode)
+		requestId = resp.Header.Get("x-bce-request-id")
+	}
+
+	if bceError, ok := err.(*Error); ok {
+		span.SetAttribute(TRACE_ATTRIBUTE_ERROR_CODE, bceError.Code)
+
+		if requestId == "" {
+			requestId = bceError.RequestID
+		}
+	}
+
+	if requestId != "" {
+		span.SetAttribute(TRACE_ATTRIBUTE_REQUEST_ID, requestId)
+	}
+}
+
+// isSignedHeader returns true if the header is signed by the option, or it is set by bce.Client.
+func isSignedHeader(key string, option *SignOption) bool {
+	switch strings.ToLower(key) {
+	case "authorization", "host", "user-agent":
+		return true
+	}
+
+	if option.headersToSignSpecified {
+		return util.Contains(option.HeadersToSign, key, true)
+	}
+
+	return isCanonicalHeader(key)
+}
+
+// hashTraceKey hashes the object key by SHA-256, so the private data in keys is not sent to the tracing backend.
+func hashTraceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+package bce
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestIsSignedHeader(t *testing.T) {
+	option := &SignOption{}
+	option.init()
+
+	for _, key := range []string{"Authorization", "Host", "x-bce-date", "X-Bce-Trace", "Content-Type"} {
+		if !isSignedHeader(key, option) {
+			t.Error(util.FormatTest("isSignedHeader "+key, "false", "true"))
+		}
+	}
+
+	for _, key := range []string{"traceparent", "tracestate", "X-B3-TraceId"} {
+		if isSignedHeader(key, option) {
+			t.Error(util.FormatTest("isSignedHeader "+key, "true", "false"))
+		}
+	}
+
+	option = &SignOption{HeadersToSign: []string{"host", "traceparent"}}
+	option.init()
+
+	if !isSignedHeader("traceparent", option) || isSignedHeader("x-bce-trace", option) {
+		t.Error(util.FormatTest("isSignedHeader HeadersToSign", "unexpected", "traceparent signed"))
+	}
+}
+
+func TestHashTraceKey(t *testing.T) {
+	hashed := hashTraceKey("private/a.txt")
+
+	if !strings.HasPrefix(hashed, "sha256:") || len(hashed) != len("sha256:")+64 ||
+		hashed != hashTraceKey("private/a.txt") || hashed == hashTraceKey("private/b.txt") {
+
+		t.Error(util.FormatTest("hashTraceKey", hashed, "sha256 hex"))
+	}
+}
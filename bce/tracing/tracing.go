@@ -0,0 +1,255 @@
+// Package tracing implements bce.Tracer with the W3C Trace Context, the spans of operations and http attempts
+// are recorded in memory or exported by a function, such as logging them or sending them to a tracing backend.
+//
+// The tracer is plugged in by the Tracer of bce.Config:
+//
+//	tracer := tracing.NewTracer(func(span *tracing.Span) {
+//		log.Println(span)
+//	})
+//	config.Tracer = tracer
+//
+// The trace context of caller is passed in by the "traceparent" header of bce.SignOption, and the trace context
+// of each attempt is sent to the service by the "traceparent" header, which is not signed:
+//
+//	option := bce.CheckSignOption(nil)
+//	option.AddHeader(tracing.TRACEPARENT, traceparent)
+//	bosClient.PutObject(bucketName, objectKey, data, nil, option)
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// TRACEPARENT is the header of the W3C Trace Context.
+const TRACEPARENT = "traceparent"
+
+// TRACE_VERSION is the version of the W3C Trace Context supported.
+const TRACE_VERSION = "00"
+
+// TRACE_FLAG_SAMPLED is the trace flag of a sampled trace.
+const TRACE_FLAG_SAMPLED = "01"
+
+// Event is an event happened in a span, such as a retry.
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{}
+}
+
+// Span is a span started by tracing.Tracer, its fields should not be modified before it is ended.
+type Span struct {
+	TraceID      string // TraceID is the 32 hex digits id of the trace.
+	SpanID       string // SpanID is the 16 hex digits id of the span.
+	ParentSpanID string // ParentSpanID is empty if the span has no parent.
+	TraceFlags   string // TraceFlags is the 2 hex digits flags inherited from the parent, "01" for a root span.
+	Name         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	Events       []Event
+	Err          error
+
+	tracer *Tracer
+}
+
+// SetAttribute implements bce.Span.
+func (span *Span) SetAttribute(key string, value interface{}) {
+	span.Attributes[key] = value
+}
+
+// AddEvent implements bce.Span.
+func (span *Span) AddEvent(name string, attributes map[string]interface{}) {
+	span.Events = append(span.Events, Event{Name: name, Time: time.Now(), Attributes: attributes})
+}
+
+// End implements bce.Span, the span is exported by the tracer.
+func (span *Span) End(err error) {
+	span.EndTime = time.Now()
+	span.Err = err
+	span.tracer.export(span)
+}
+
+// Duration returns the duration of an ended span.
+func (span *Span) Duration() time.Duration {
+	return span.EndTime.Sub(span.StartTime)
+}
+
+// TraceParent returns the value of "traceparent" header of the span.
+func (span *Span) TraceParent() string {
+	return strings.Join([]string{TRACE_VERSION, span.TraceID, span.SpanID, span.TraceFlags}, "-")
+}
+
+// String formats the span as one line, the attributes are sorted by the keys.
+func (span *Span) String() string {
+	keys := make([]string, 0, len(span.Attributes))
+
+	for key := range span.Attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	fields := []string{fmt.Sprintf("%s trace=%s span=%s parent=%s duration=%s",
+		span.Name, span.TraceID, span.SpanID, span.ParentSpanID, span.Duration())}
+
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, span.Attributes[key]))
+	}
+
+	for _, event := range span.Events {
+		fields = append(fields, fmt.Sprintf("event=%s@%s", event.Name, event.Time.Sub(span.StartTime)))
+	}
+
+	if span.Err != nil {
+		fields = append(fields, fmt.Sprintf("error=%q", span.Err.Error()))
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// Tracer implements bce.Tracer, it is safe for concurrent requests.
+type Tracer struct {
+	exportFunc func(span *Span)
+
+	mutex sync.Mutex
+	spans []*Span
+}
+
+// NewTracer returns a tracing.Tracer, the ended spans are passed to exportFunc,
+// or recorded in memory and returned by Spans if exportFunc is nil.
+func NewTracer(exportFunc func(span *Span)) *Tracer {
+	return &Tracer{exportFunc: exportFunc}
+}
+
+// StartOperation implements bce.Tracer, the span is a child of the "traceparent" in header if it is valid,
+// and keeps its trace flags, such as an unsampled trace of caller, or the sampled root span of a new trace.
+func (tracer *Tracer) StartOperation(operation *bce.Operation, header http.Header) bce.Span {
+	name := "SendRequest"
+
+	if operation.Name != "" {
+		name = operation.Name
+	}
+
+	if operation.Service != "" {
+		name = operation.Service + "." + name
+	}
+
+	span := tracer.newSpan(name)
+
+	if traceId, parentSpanId, traceFlags, ok := ParseTraceParent(header.Get(TRACEPARENT)); ok {
+		span.TraceID, span.ParentSpanID, span.TraceFlags = traceId, parentSpanId, traceFlags
+	} else {
+		span.TraceID, span.TraceFlags = newId(16), TRACE_FLAG_SAMPLED
+	}
+
+	return span
+}
+
+// StartAttempt implements bce.Tracer, the "traceparent" of the attempt span is set to the header.
+func (tracer *Tracer) StartAttempt(operationSpan bce.Span, attempt int, header http.Header) bce.Span {
+	parent := operationSpan.(*Span)
+	span := tracer.newSpan(fmt.Sprintf("%s attempt %d", parent.Name, attempt))
+	span.TraceID, span.ParentSpanID, span.TraceFlags = parent.TraceID, parent.SpanID, parent.TraceFlags
+
+	header.Set(TRACEPARENT, span.TraceParent())
+
+	return span
+}
+
+// Spans returns the ended spans recorded if no export function is specified, in the order of ending,
+// so the attempt spans of an operation are before the operation span.
+func (tracer *Tracer) Spans() []*Span {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	return append([]*Span(nil), tracer.spans...)
+}
+
+// Reset clears the spans recorded.
+func (tracer *Tracer) Reset() {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	tracer.spans = nil
+}
+
+func (tracer *Tracer) newSpan(name string) *Span {
+	return &Span{
+		SpanID:     newId(8),
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]interface{}),
+		tracer:     tracer,
+	}
+}
+
+func (tracer *Tracer) export(span *Span) {
+	if tracer.exportFunc != nil {
+		tracer.exportFunc(span)
+		return
+	}
+
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	tracer.spans = append(tracer.spans, span)
+}
+
+// ParseTraceParent parses the value of "traceparent" header, ok is false if it is invalid.
+func ParseTraceParent(traceParent string) (traceId, spanId, traceFlags string, ok bool) {
+	fields := strings.Split(strings.TrimSpace(traceParent), "-")
+
+	if len(fields) < 4 || len(fields[0]) != 2 || fields[0] == "ff" {
+		return "", "", "", false
+	}
+
+	traceId, spanId, traceFlags = strings.ToLower(fields[1]), strings.ToLower(fields[2]), strings.ToLower(fields[3])
+
+	if !isHexId(traceId, 16) || !isHexId(spanId, 8) || len(traceFlags) != 2 {
+		return "", "", "", false
+	}
+
+	if _, err := hex.DecodeString(traceFlags); err != nil {
+		return "", "", "", false
+	}
+
+	return traceId, spanId, traceFlags, true
+}
+
+// isHexId returns true if the id is the hex of size bytes and not all zero.
+func isHexId(id string, size int) bool {
+	if len(id) != size*2 || strings.Trim(id, "0") == "" {
+		return false
+	}
+
+	_, err := hex.DecodeString(id)
+
+	return err == nil
+}
+
+func newId(size int) string {
+	byteArray := make([]byte, size)
+
+	for {
+		// math/rand is the fallback if the random source of system is not available
+		if _, err := rand.Read(byteArray); err != nil {
+			for i := range byteArray {
+				byteArray[i] = byte(mathrand.Intn(256))
+			}
+		}
+
+		if id := hex.EncodeToString(byteArray); strings.Trim(id, "0") != "" {
+			return id
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestTracer(t *testing.T) {
+	server := bostest.NewServer()
+	defer server.Close()
+
+	tracer := NewTracer(nil)
+	config := server.NewConfig()
+	config.Tracer = tracer
+	config.HashTraceKey = true
+	config.RetryPolicy = bce.NewDefaultRetryPolicy(3, time.Millisecond)
+	client := bos.NewClient(bos.NewConfig(config))
+
+	bucketName, objectKey := "tracing-bucket", "private/a.txt"
+
+	if err := client.CreateBucket(bucketName, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.PutObject(bucketName, objectKey, "tracing", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "GET",
+		Path:       "/" + bucketName + "/" + objectKey,
+		Times:      1,
+		StatusCode: http.StatusServiceUnavailable,
+	})
+
+	tracer.Reset()
+	server.ClearRequests()
+
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	option := bce.CheckSignOption(nil)
+	option.AddHeader(TRACEPARENT, traceParent)
+
+	object, err := client.GetObject(bucketName, objectKey, option)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object.ObjectContent.Close()
+
+	spans := tracer.Spans()
+
+	if len(spans) != 3 {
+		t.Fatal(util.FormatTest("Tracer spans", strconv.Itoa(len(spans)), "3"))
+	}
+
+	operationSpan := spans[2]
+
+	if operationSpan.Name != "bos.GetObject" || operationSpan.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		operationSpan.ParentSpanID != "00f067aa0ba902b7" || operationSpan.Err != nil {
+
+		t.Error(util.FormatTest("Tracer operation span", operationSpan.String(), "child of traceparent"))
+	}
+
+	key, _ := operationSpan.Attributes[bce.TRACE_ATTRIBUTE_KEY].(string)
+
+	if operationSpan.Attributes[bce.TRACE_ATTRIBUTE_BUCKET] != bucketName || !strings.HasPrefix(key, "sha256:") ||
+		strings.Contains(key, "private") || operationSpan.Attributes[bce.TRACE_ATTRIBUTE_ATTEMPTS] != 2 ||
+		operationSpan.Attributes[bce.TRACE_ATTRIBUTE_STATUS_CODE] != http.StatusOK {
+
+		t.Error(util.FormatTest("Tracer operation attributes", operationSpan.String(), "hashed key and 2 attempts"))
+	}
+
+	if len(operationSpan.Events) != 1 || operationSpan.Events[0].Name != bce.TRACE_EVENT_RETRY {
+		t.Error(util.FormatTest("Tracer operation events", operationSpan.String(), "retry event"))
+	}
+
+	requests := server.Requests()
+
+	if len(requests) != 2 {
+		t.Fatal(util.FormatTest("Tracer requests", strconv.Itoa(len(requests)), "2"))
+	}
+
+	for i, span := range spans[:2] {
+		expectedStatus := http.StatusOK
+
+		if i == 0 {
+			expectedStatus = http.StatusServiceUnavailable
+
+			if span.Attributes[bce.TRACE_ATTRIBUTE_RETRY_DELAY] != time.Millisecond ||
+				span.Attributes[bce.TRACE_ATTRIBUTE_ERROR_CODE] != "ServiceUnavailable" || span.Err == nil {
+
+				t.Error(util.FormatTest("Tracer retried attempt", span.String(), "retry delay"))
+			}
+		}
+
+		if span.ParentSpanID != operationSpan.SpanID || span.TraceID != operationSpan.TraceID ||
+			span.Attributes[bce.TRACE_ATTRIBUTE_ATTEMPT] != i+1 ||
+			span.Attributes[bce.TRACE_ATTRIBUTE_STATUS_CODE] != expectedStatus {
+
+			t.Error(util.FormatTest("Tracer attempt span", span.String(), fmt.Sprintf("attempt %d", i+1)))
+		}
+
+		// the trace context is sent without breaking the signature, and the request id is returned
+		if requests[i].Header.Get(TRACEPARENT) != span.TraceParent() ||
+			span.Attributes[bce.TRACE_ATTRIBUTE_REQUEST_ID] != requests[i].RequestId {
+
+			t.Error(util.FormatTest("Tracer propagation", span.String(), requests[i].Header.Get(TRACEPARENT)))
+		}
+
+		signed := len(span.Events) == 1 && span.Events[0].Name == bce.TRACE_EVENT_SIGNED
+
+		if !signed {
+			t.Error(util.FormatTest("Tracer signed event", span.String(), bce.TRACE_EVENT_SIGNED))
+		}
+	}
+}
+
+func TestTracerExport(t *testing.T) {
+	var exported []*Span
+
+	tracer := NewTracer(func(span *Span) { exported = append(exported, span) })
+	span := tracer.StartOperation(&bce.Operation{}, make(http.Header))
+	span.End(nil)
+
+	if len(exported) != 1 || exported[0].Name != "SendRequest" || exported[0].ParentSpanID != "" ||
+		!isHexId(exported[0].TraceID, 16) || len(tracer.Spans()) != 0 {
+
+		t.Error(util.FormatTest("TracerExport", fmt.Sprintf("%v", exported), "root span exported"))
+	}
+}
+
+func TestTracerTraceFlags(t *testing.T) {
+	tracer := NewTracer(nil)
+	cases := []struct {
+		traceParent, expected string
+	}{
+		{"", TRACE_FLAG_SAMPLED},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", "00"},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "01"},
+	}
+
+	for _, c := range cases {
+		header := make(http.Header)
+		header.Set(TRACEPARENT, c.traceParent)
+		operationSpan := tracer.StartOperation(&bce.Operation{}, header)
+
+		attemptHeader := make(http.Header)
+		tracer.StartAttempt(operationSpan, 1, attemptHeader)
+
+		// the unsampled trace of caller should not be turned into a sampled one
+		if traceParent := attemptHeader.Get(TRACEPARENT); !strings.HasSuffix(traceParent, "-"+c.expected) {
+			t.Error(util.FormatTest("Tracer trace flags "+c.traceParent, traceParent, c.expected))
+		}
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	traceId, spanId, traceFlags, ok := ParseTraceParent("00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-0A")
+
+	if !ok || traceId != "4bf92f3577b34da6a3ce929d0e0e4736" || spanId != "00f067aa0ba902b7" || traceFlags != "0a" {
+		t.Error(util.FormatTest("ParseTraceParent", traceId+" "+spanId+" "+traceFlags, "lower case ids and flags"))
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	}
+
+	for _, traceParent := range invalid {
+		if _, _, _, ok := ParseTraceParent(traceParent); ok {
+			t.Error(util.FormatTest("ParseTraceParent "+traceParent, "ok", "invalid"))
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package bostest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// session is the temporary credentials issued by the fake STS（Security Token Service）.
+type session struct {
+	credentials  *bce.Credentials
+	sessionToken string
+	expiration   time.Time
+}
+
+// authenticate verifies the bce-auth-v1 signature of the request, which is sent in Authorization header,
+// or in authorization param of a presigned URL.
+//
+// A request without signature is anonymous, it is checked by the ACL of BOS Bucket and BOS Object later.
+func (s *Server) authenticate(req *request) *serverError {
+	authorization := req.Header.Get("Authorization")
+	rawQuery := req.URL.RawQuery
+
+	if authorization == "" && req.has("authorization") {
+		authorization = req.query.Get("authorization")
+		rawQuery = removeQueryParam(rawQuery, "authorization")
+	}
+
+	if authorization == "" {
+		req.anonymous = true
+		return nil
+	}
+
+	parts := strings.Split(authorization, "/")
+
+	if len(parts) != 6 || parts[0] != "bce-auth-v1" {
+		return errAccessDenied(fmt.Sprintf("Invalid authorization %s.", authorization))
+	}
+
+	accessKeyId, timestamp, signedHeaders, signature := parts[1], parts[2], parts[4], parts[5]
+	expirationPeriodInSeconds, err := strconv.Atoi(parts[3])
+
+	if err != nil {
+		return errAccessDenied(fmt.Sprintf("Invalid authorization %s.", authorization))
+	}
+
+	credentials, err2 := s.lookupCredentials(req, accessKeyId)
+
+	if err2 != nil {
+		return err2
+	}
+
+	signTime, err := time.Parse(time.RFC3339, timestamp)
+
+	if err != nil {
+		return errAccessDenied(fmt.Sprintf("Invalid timestamp %s.", timestamp))
+	}
+
+	if time.Now().After(signTime.Add(time.Duration(expirationPeriodInSeconds) * time.Second)) {
+		return newError(http.StatusForbidden, "RequestExpired", "The request has expired.")
+	}
+
+	headers := make(map[string]string)
+
+	for _, key := range strings.Split(signedHeaders, ";") {
+		if key == "" {
+			continue
+		}
+
+		value := req.Header.Get(key)
+
+		if key == "host" {
+			value = req.Host
+		} else if key == "content-length" && value == "" {
+			value = strconv.FormatInt(req.ContentLength, 10)
+		}
+
+		headers[key] = value
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		util.URIEncodeExceptSlash(req.URL.Path),
+		rawQuery,
+		util.ToCanonicalHeaderString(headers),
+	}, "\n")
+
+	authStringPrefix := strings.Join(parts[:4], "/")
+	signingKey := util.HmacSha256Hex(credentials.SecretAccessKey, authStringPrefix)
+
+	if util.HmacSha256Hex(signingKey, canonicalRequest) != signature {
+		return newError(http.StatusForbidden, "SignatureDoesNotMatch",
+			"The request signature we calculated does not match the signature you provided.")
+	}
+
+	return nil
+}
+
+// lookupCredentials finds the secret access key of the access key id, which is the one of the server,
+// or a temporary one issued by STS with the same security token.
+func (s *Server) lookupCredentials(req *request, accessKeyId string) (*bce.Credentials, *serverError) {
+	if accessKeyId == s.Credentials.AccessKeyID {
+		return s.Credentials, nil
+	}
+
+	session, ok := s.sessions[accessKeyId]
+
+	if !ok {
+		return nil, newError(http.StatusForbidden, "InvalidAccessKeyId",
+			fmt.Sprintf("The access key id %s does not exist.", accessKeyId))
+	}
+
+	if req.Header.Get("x-bce-security-token") != session.sessionToken {
+		return nil, newError(http.StatusForbidden, "InvalidSessionToken", "The security token is invalid.")
+	}
+
+	if time.Now().After(session.expiration) {
+		return nil, newError(http.StatusForbidden, "SessionTokenExpired", "The security token has expired.")
+	}
+
+	return session.credentials, nil
+}
+
+// getSessionToken issues temporary credentials, the access control list of the request is not checked.
+func (s *Server) getSessionToken(req *request) *serverError {
+	if req.Method != "POST" || strings.TrimSuffix(req.URL.Path, "/") != "/v1/sessionToken" {
+		return errMethodNotAllowed()
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	if len(req.body) > 0 {
+		var sessionTokenRequest bce.SessionTokenRequest
+
+		if err := json.Unmarshal(req.body, &sessionTokenRequest); err != nil {
+			return errMalformedJSON(err)
+		}
+	}
+
+	durationSeconds := 43200
+
+	if value := req.query.Get("durationSeconds"); value != "" {
+		seconds, err := strconv.Atoi(value)
+
+		if err != nil || seconds <= 0 {
+			return errInvalidArgument(fmt.Sprintf("Invalid durationSeconds %s.", value))
+		}
+
+		durationSeconds = seconds
+	}
+
+	now := time.Now().UTC()
+	id := s.nextId()
+	session := &session{
+		credentials:  bce.NewCredentials("sts-ak-"+id, "sts-sk-"+id),
+		sessionToken: "sts-token-" + id,
+		expiration:   now.Add(time.Duration(durationSeconds) * time.Second),
+	}
+
+	s.sessions[session.credentials.AccessKeyID] = session
+
+	s.writeJSON(req, http.StatusOK, bce.SessionTokenResponse{
+		AccessKeyId:     session.credentials.AccessKeyID,
+		SecretAccessKey: session.credentials.SecretAccessKey,
+		SessionToken:    session.sessionToken,
+		CreateTime:      formatTime(now),
+		Expiration:      formatTime(session.expiration),
+		UserId:          OWNER_ID,
+	})
+
+	return nil
+}
+
+// removeQueryParam removes a param from the raw query, the order of other params is kept.
+func removeQueryParam(rawQuery, param string) string {
+	pairs := strings.Split(rawQuery, "&")
+	result := make([]string, 0, len(pairs))
+
+	for _, pair := range pairs {
+		if pair == param || strings.HasPrefix(pair, param+"=") || pair == "" {
+			continue
+		}
+
+		result = append(result, pair)
+	}
+
+	return strings.Join(result, "&")
+}
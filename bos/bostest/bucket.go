@@ -0,0 +1,728 @@
+package bostest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+var bucketNameRegexp = regexp.MustCompile("^[a-z0-9][a-z0-9-]{1,61}[a-z0-9]$")
+
+// bucketSubResources are the configurations of BOS Bucket stored as JSON,
+// the value is the error code returned if the configuration does not exist.
+var bucketSubResources = map[string]string{
+	"cors":        "NoSuchCORSConfiguration",
+	"lifecycle":   "NoSuchLifecycleConfiguration",
+	"logging":     "",
+	"encryption":  "NoSuchEncryptionConfiguration",
+	"referer":     "NoSuchRefererConfiguration",
+	"website":     "NoSuchWebsiteConfiguration",
+	"replication": "NoSuchReplicationConfiguration",
+}
+
+// grant is an item of the access control list of BOS Bucket and BOS Object.
+type grant struct {
+	Grantee []struct {
+		Id string `json:"id"`
+	} `json:"grantee"`
+	Permission []string `json:"permission"`
+}
+
+type bucket struct {
+	name         string
+	location     string
+	creationDate time.Time
+	acl          []grant
+	versioning   string
+	subResources map[string][]byte
+
+	// objects are the versions of each BOS Object, the latest version is the first one.
+	objects map[string][]*object
+	uploads map[string]*upload
+}
+
+func (b *bucket) sortedKeys() []string {
+	keys := make([]string, 0, len(b.objects))
+
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// cannedAcl converts the value of x-bce-acl header to the access control list.
+func cannedAcl(acl string) ([]grant, *serverError) {
+	var publicPermission []string
+
+	switch acl {
+	case "private":
+	case "public-read":
+		publicPermission = []string{"READ"}
+	case "public-read-write":
+		publicPermission = []string{"READ", "WRITE"}
+	default:
+		return nil, errInvalidArgument(fmt.Sprintf("Invalid canned acl %s.", acl))
+	}
+
+	grants := []grant{newGrant(OWNER_ID, "FULL_CONTROL")}
+
+	if publicPermission != nil {
+		grants = append(grants, newGrant("*", publicPermission...))
+	}
+
+	return grants, nil
+}
+
+func newGrant(id string, permission ...string) grant {
+	g := grant{Permission: permission}
+	g.Grantee = append(g.Grantee, struct {
+		Id string `json:"id"`
+	}{id})
+
+	return g
+}
+
+// parseAcl gets the access control list from x-bce-acl header or the JSON body.
+func parseAcl(req *request) ([]grant, *serverError) {
+	if acl := req.Header.Get("x-bce-acl"); acl != "" {
+		return cannedAcl(acl)
+	}
+
+	var body struct {
+		AccessControlList []grant `json:"accessControlList"`
+	}
+
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		return nil, errMalformedJSON(err)
+	}
+
+	for _, g := range body.AccessControlList {
+		if len(g.Grantee) == 0 || len(g.Permission) == 0 {
+			return nil, errInvalidArgument("Grantee and permission should not be empty.")
+		}
+	}
+
+	return body.AccessControlList, nil
+}
+
+// checkAccess allows the anonymous request only if the access control list grants the permission to everyone.
+func checkAccess(req *request, grants []grant, permission string) *serverError {
+	if !req.anonymous {
+		return nil
+	}
+
+	for _, g := range grants {
+		for _, grantee := range g.Grantee {
+			if grantee.Id != "*" {
+				continue
+			}
+
+			for _, p := range g.Permission {
+				if p == permission || p == "FULL_CONTROL" {
+					return nil
+				}
+			}
+		}
+	}
+
+	return errAccessDenied("Anonymous access is forbidden.")
+}
+
+func (s *Server) getBucket(req *request) (*bucket, *serverError) {
+	b, ok := s.buckets[req.bucketName]
+
+	if !ok {
+		return nil, errNoSuchBucket(req.bucketName)
+	}
+
+	return b, nil
+}
+
+func (s *Server) serveBucket(req *request) *serverError {
+	if req.Method == "PUT" && !req.has("acl") && !hasBucketSubResource(req) && !req.has("versioning") {
+		return s.createBucket(req)
+	}
+
+	b, err := s.getBucket(req)
+
+	if err != nil {
+		return err
+	}
+
+	permission := "FULL_CONTROL"
+
+	if req.Method == "HEAD" || (req.Method == "GET" && len(req.query) == 0) {
+		permission = "READ"
+	}
+
+	if err := checkAccess(req, b.acl, permission); err != nil {
+		return err
+	}
+
+	switch req.Method {
+	case "HEAD":
+		s.writeEmpty(req, http.StatusOK)
+		return nil
+	case "GET":
+		switch {
+		case req.has("acl"):
+			s.writeJSON(req, http.StatusOK, map[string]interface{}{
+				"owner":             map[string]string{"id": OWNER_ID},
+				"accessControlList": b.acl,
+			})
+			return nil
+		case req.has("location"):
+			s.writeJSON(req, http.StatusOK, map[string]string{"locationConstraint": b.location})
+			return nil
+		case req.has("uploads"):
+			return s.listMultipartUploads(req, b)
+		case req.has("versions"):
+			return s.listObjectVersions(req, b)
+		case req.has("versioning"):
+			s.writeJSON(req, http.StatusOK, map[string]string{"status": b.versioning})
+			return nil
+		case req.has("replicationProgress"):
+			return s.getBucketReplicationProgress(req, b)
+		case hasBucketSubResource(req):
+			return s.getBucketSubResource(req, b)
+		}
+
+		return s.listObjects(req, b)
+	case "PUT":
+		switch {
+		case req.has("acl"):
+			grants, err := parseAcl(req)
+
+			if err != nil {
+				return err
+			}
+
+			b.acl = grants
+			s.writeEmpty(req, http.StatusOK)
+			return nil
+		case req.has("versioning"):
+			return s.putBucketVersioning(req, b)
+		}
+
+		return s.putBucketSubResource(req, b)
+	case "DELETE":
+		if hasBucketSubResource(req) {
+			delete(b.subResources, bucketSubResource(req))
+			s.writeEmpty(req, http.StatusOK)
+			return nil
+		}
+
+		if len(req.query) > 0 {
+			return errMethodNotAllowed()
+		}
+
+		return s.deleteBucket(req, b)
+	case "POST":
+		if req.has("delete") {
+			return s.deleteMultipleObjects(req, b)
+		}
+	}
+
+	return errMethodNotAllowed()
+}
+
+func hasBucketSubResource(req *request) bool {
+	return bucketSubResource(req) != ""
+}
+
+func bucketSubResource(req *request) string {
+	for subResource := range bucketSubResources {
+		if req.has(subResource) {
+			return subResource
+		}
+	}
+
+	return ""
+}
+
+func (s *Server) listBuckets(req *request) *serverError {
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	names := make([]string, 0, len(s.buckets))
+
+	for name := range s.buckets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	buckets := make([]map[string]string, 0, len(names))
+
+	for _, name := range names {
+		b := s.buckets[name]
+		buckets = append(buckets, map[string]string{
+			"name":         b.name,
+			"location":     b.location,
+			"creationDate": formatTime(b.creationDate),
+		})
+	}
+
+	s.writeJSON(req, http.StatusOK, map[string]interface{}{"owner": owner(), "buckets": buckets})
+
+	return nil
+}
+
+func (s *Server) createBucket(req *request) *serverError {
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	if !bucketNameRegexp.MatchString(req.bucketName) {
+		return newError(http.StatusBadRequest, "InvalidBucketName",
+			fmt.Sprintf("The bucket name %s is invalid.", req.bucketName))
+	}
+
+	if _, ok := s.buckets[req.bucketName]; ok {
+		return newError(http.StatusConflict, "BucketAlreadyExists",
+			fmt.Sprintf("The bucket %s already exists.", req.bucketName))
+	}
+
+	acl := req.Header.Get("x-bce-acl")
+
+	if acl == "" {
+		acl = "private"
+	}
+
+	grants, err := cannedAcl(acl)
+
+	if err != nil {
+		return err
+	}
+
+	s.buckets[req.bucketName] = &bucket{
+		name:         req.bucketName,
+		location:     req.region,
+		creationDate: time.Now(),
+		acl:          grants,
+		subResources: make(map[string][]byte),
+		objects:      make(map[string][]*object),
+		uploads:      make(map[string]*upload),
+	}
+
+	req.w.Header().Set("Location", "/"+req.bucketName)
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) deleteBucket(req *request, b *bucket) *serverError {
+	if len(b.objects) > 0 || len(b.uploads) > 0 {
+		return newError(http.StatusConflict, "BucketNotEmpty", fmt.Sprintf("The bucket %s is not empty.", b.name))
+	}
+
+	delete(s.buckets, b.name)
+	s.writeEmpty(req, http.StatusNoContent)
+
+	return nil
+}
+
+func (s *Server) putBucketVersioning(req *request, b *bucket) *serverError {
+	var bucketVersioning struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.Unmarshal(req.body, &bucketVersioning); err != nil {
+		return errMalformedJSON(err)
+	}
+
+	if bucketVersioning.Status != "enabled" && bucketVersioning.Status != "suspended" {
+		return errInvalidArgument(fmt.Sprintf("Invalid versioning status %s.", bucketVersioning.Status))
+	}
+
+	b.versioning = bucketVersioning.Status
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) putBucketSubResource(req *request, b *bucket) *serverError {
+	subResource := bucketSubResource(req)
+	var configuration map[string]interface{}
+
+	if err := json.Unmarshal(req.body, &configuration); err != nil {
+		return errMalformedJSON(err)
+	}
+
+	body := req.body
+
+	switch subResource {
+	case "logging":
+		targetBucket, _ := configuration["targetBucket"].(string)
+
+		if _, ok := s.buckets[targetBucket]; !ok {
+			return errInvalidArgument(fmt.Sprintf("The target bucket %s does not exist.", targetBucket))
+		}
+
+		configuration["status"] = "enabled"
+		body, _ = json.Marshal(configuration)
+	case "lifecycle":
+		if rules, _ := configuration["rule"].([]interface{}); len(rules) == 0 {
+			return errInvalidArgument("The lifecycle rule should not be empty.")
+		}
+	case "encryption":
+		if algorithm := configuration["encryptionAlgorithm"]; algorithm != "AES256" && algorithm != "SM4" {
+			return errInvalidArgument(fmt.Sprintf("Invalid encryption algorithm %v.", algorithm))
+		}
+	case "replication":
+		destination, _ := configuration["destination"].(map[string]interface{})
+		destBucketName, _ := destination["bucket"].(string)
+
+		if _, ok := s.buckets[destBucketName]; !ok {
+			return errInvalidArgument(fmt.Sprintf("The destination bucket %s does not exist.", destBucketName))
+		}
+	}
+
+	b.subResources[subResource] = body
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) getBucketSubResource(req *request, b *bucket) *serverError {
+	subResource := bucketSubResource(req)
+	body, ok := b.subResources[subResource]
+
+	if !ok {
+		if subResource == "logging" {
+			s.writeJSON(req, http.StatusOK, map[string]string{"status": "disabled"})
+			return nil
+		}
+
+		return newError(http.StatusNotFound, bucketSubResources[subResource],
+			fmt.Sprintf("The %s configuration of bucket %s does not exist.", subResource, b.name))
+	}
+
+	s.writeJSON(req, http.StatusOK, rawJSON(body))
+
+	return nil
+}
+
+func (s *Server) getBucketReplicationProgress(req *request, b *bucket) *serverError {
+	if _, ok := b.subResources["replication"]; !ok {
+		return newError(http.StatusNotFound, bucketSubResources["replication"],
+			fmt.Sprintf("The replication configuration of bucket %s does not exist.", b.name))
+	}
+
+	s.writeJSON(req, http.StatusOK, map[string]interface{}{
+		"status":                    "enabled",
+		"historyReplicationPercent": 100,
+		"latestReplicationTime":     formatTime(time.Now()),
+	})
+
+	return nil
+}
+
+// intParam gets an int param of the query, defaultValue is returned if the param is absent.
+func intParam(req *request, param string, defaultValue, maxValue int) (int, *serverError) {
+	value := req.query.Get(param)
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(value)
+
+	if err != nil || i < 0 {
+		return 0, errInvalidArgument(fmt.Sprintf("Invalid %s %s.", param, value))
+	}
+
+	if i > maxValue {
+		i = maxValue
+	}
+
+	return i, nil
+}
+
+// rollUp returns the common prefix of key if it contains delimiter after prefix.
+func rollUp(key, prefix, delimiter string) (string, bool) {
+	if delimiter == "" {
+		return key, false
+	}
+
+	if index := strings.Index(key[len(prefix):], delimiter); index >= 0 {
+		return key[:len(prefix)+index+len(delimiter)], true
+	}
+
+	return key, false
+}
+
+func (s *Server) listObjects(req *request, b *bucket) *serverError {
+	prefix, delimiter, marker := req.query.Get("prefix"), req.query.Get("delimiter"), req.query.Get("marker")
+	maxKeys, err := intParam(req, "maxKeys", 1000, 1000)
+
+	if err != nil {
+		return err
+	}
+
+	contents := make([]map[string]interface{}, 0)
+	commonPrefixes := make([]map[string]string, 0)
+	var nextMarker string
+	var isTruncated bool
+
+	for _, key := range b.sortedKeys() {
+		o := b.objects[key][0]
+
+		if o.deleteMarker || !strings.HasPrefix(key, prefix) || key <= marker {
+			continue
+		}
+
+		item, isPrefix := rollUp(key, prefix, delimiter)
+
+		if isPrefix && (item <= marker || item == nextMarker) {
+			continue
+		}
+
+		if len(contents)+len(commonPrefixes) >= maxKeys {
+			isTruncated = true
+			break
+		}
+
+		if isPrefix {
+			commonPrefixes = append(commonPrefixes, map[string]string{"prefix": item})
+		} else {
+			contents = append(contents, o.summary())
+		}
+
+		nextMarker = item
+	}
+
+	result := map[string]interface{}{
+		"name":           b.name,
+		"prefix":         prefix,
+		"delimiter":      delimiter,
+		"marker":         marker,
+		"maxKeys":        maxKeys,
+		"isTruncated":    isTruncated,
+		"contents":       contents,
+		"commonPrefixes": commonPrefixes,
+	}
+
+	if isTruncated {
+		result["nextMarker"] = nextMarker
+	}
+
+	s.writeJSON(req, http.StatusOK, result)
+
+	return nil
+}
+
+func (s *Server) listObjectVersions(req *request, b *bucket) *serverError {
+	prefix, delimiter := req.query.Get("prefix"), req.query.Get("delimiter")
+	keyMarker, versionIdMarker := req.query.Get("keyMarker"), req.query.Get("versionIdMarker")
+	maxKeys, err := intParam(req, "maxKeys", 1000, 1000)
+
+	if err != nil {
+		return err
+	}
+
+	versions := make([]map[string]interface{}, 0)
+	commonPrefixes := make([]map[string]string, 0)
+	var nextKeyMarker, nextVersionIdMarker string
+	var isTruncated bool
+
+	for _, key := range b.sortedKeys() {
+		if !strings.HasPrefix(key, prefix) || key < keyMarker || (key == keyMarker && versionIdMarker == "") {
+			continue
+		}
+
+		item, isPrefix := rollUp(key, prefix, delimiter)
+
+		if isPrefix {
+			if item <= keyMarker || item == nextKeyMarker {
+				continue
+			}
+
+			if len(versions)+len(commonPrefixes) >= maxKeys {
+				isTruncated = true
+				break
+			}
+
+			commonPrefixes = append(commonPrefixes, map[string]string{"prefix": item})
+			nextKeyMarker, nextVersionIdMarker = item, ""
+			continue
+		}
+
+		skip := key == keyMarker
+
+		for index, o := range b.objects[key] {
+			if skip {
+				skip = o.versionId != versionIdMarker
+				continue
+			}
+
+			if len(versions)+len(commonPrefixes) >= maxKeys {
+				isTruncated = true
+				break
+			}
+
+			summary := o.summary()
+			summary["versionId"] = o.versionId
+			summary["isLatest"] = index == 0
+			summary["isDeleteMarker"] = o.deleteMarker
+			versions = append(versions, summary)
+			nextKeyMarker, nextVersionIdMarker = key, o.versionId
+		}
+
+		if isTruncated {
+			break
+		}
+	}
+
+	result := map[string]interface{}{
+		"name":            b.name,
+		"prefix":          prefix,
+		"delimiter":       delimiter,
+		"keyMarker":       keyMarker,
+		"versionIdMarker": versionIdMarker,
+		"maxKeys":         maxKeys,
+		"isTruncated":     isTruncated,
+		"versions":        versions,
+		"commonPrefixes":  commonPrefixes,
+	}
+
+	if isTruncated {
+		result["nextKeyMarker"] = nextKeyMarker
+		result["nextVersionIdMarker"] = nextVersionIdMarker
+	}
+
+	s.writeJSON(req, http.StatusOK, result)
+
+	return nil
+}
+
+func (s *Server) deleteMultipleObjects(req *request, b *bucket) *serverError {
+	var body struct {
+		Objects []struct {
+			Key string `json:"key"`
+		} `json:"objects"`
+	}
+
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		return errMalformedJSON(err)
+	}
+
+	if len(body.Objects) == 0 || len(body.Objects) > 1000 {
+		return errInvalidArgument("The count of objects should be from 1 to 1000.")
+	}
+
+	errors := make([]map[string]string, 0)
+
+	for _, item := range body.Objects {
+		if _, err := s.removeObject(b, item.Key, ""); err != nil {
+			errors = append(errors, map[string]string{"key": item.Key, "code": err.Code, "message": err.Message})
+		}
+	}
+
+	if len(errors) == 0 {
+		s.writeEmpty(req, http.StatusOK)
+	} else {
+		s.writeJSON(req, http.StatusOK, map[string]interface{}{"errors": errors})
+	}
+
+	return nil
+}
+
+// matchPattern matches a string with a pattern which contains at most one wildcard "*".
+func matchPattern(pattern, s string) bool {
+	index := strings.Index(pattern, "*")
+
+	if index < 0 {
+		return strings.EqualFold(pattern, s)
+	}
+
+	prefix, suffix := pattern[:index], pattern[index+1:]
+
+	return len(s) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix)) &&
+		strings.HasSuffix(strings.ToLower(s), strings.ToLower(suffix))
+}
+
+func matchAnyPattern(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// optionsObject responds to the CORS preflight request by the first matched CORS rule of BOS Bucket.
+func (s *Server) optionsObject(req *request, b *bucket) *serverError {
+	var bucketCors struct {
+		CorsConfiguration []struct {
+			AllowedOrigins       []string `json:"allowedOrigins"`
+			AllowedMethods       []string `json:"allowedMethods"`
+			AllowedHeaders       []string `json:"allowedHeaders"`
+			AllowedExposeHeaders []string `json:"allowedExposeHeaders"`
+			MaxAgeSeconds        int      `json:"maxAgeSeconds"`
+		} `json:"corsConfiguration"`
+	}
+
+	forbidden := newError(http.StatusForbidden, "AccessForbidden", "The CORS request is not allowed.")
+
+	if body, ok := b.subResources["cors"]; !ok || json.Unmarshal(body, &bucketCors) != nil {
+		return forbidden
+	}
+
+	origin := req.Header.Get("Origin")
+	method := req.Header.Get("Access-Control-Request-Method")
+	var requestHeaders []string
+
+	for _, header := range strings.Split(req.Header.Get("Access-Control-Request-Headers"), ",") {
+		if header = strings.TrimSpace(header); header != "" {
+			requestHeaders = append(requestHeaders, header)
+		}
+	}
+
+	for _, rule := range bucketCors.CorsConfiguration {
+		if !matchAnyPattern(rule.AllowedOrigins, origin) || !util.Contains(rule.AllowedMethods, method, false) {
+			continue
+		}
+
+		allowed := true
+
+		for _, header := range requestHeaders {
+			allowed = allowed && matchAnyPattern(rule.AllowedHeaders, header)
+		}
+
+		if !allowed {
+			continue
+		}
+
+		h := req.w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethods, ","))
+		h.Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+
+		if len(requestHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(requestHeaders, ","))
+		}
+
+		if len(rule.AllowedExposeHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(rule.AllowedExposeHeaders, ","))
+		}
+
+		s.writeEmpty(req, http.StatusOK)
+
+		return nil
+	}
+
+	return forbidden
+}
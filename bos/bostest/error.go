@@ -0,0 +1,71 @@
+package bostest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// serverError is an error response of the fake server, it is written as the JSON of bce.Error.
+type serverError struct {
+	StatusCode    int
+	Code, Message string
+
+	// header is written to the response with the error, such as the ETag of a 304 response.
+	header map[string]string
+}
+
+func newError(statusCode int, code, message string) *serverError {
+	return &serverError{StatusCode: statusCode, Code: code, Message: message}
+}
+
+func (err *serverError) Error() string {
+	return fmt.Sprintf("%d %s: %s", err.StatusCode, err.Code, err.Message)
+}
+
+func (err *serverError) withHeader(key, value string) *serverError {
+	if err.header == nil {
+		err.header = make(map[string]string)
+	}
+
+	err.header[key] = value
+
+	return err
+}
+
+func errAccessDenied(message string) *serverError {
+	return newError(http.StatusForbidden, "AccessDenied", message)
+}
+
+func errInvalidArgument(message string) *serverError {
+	return newError(http.StatusBadRequest, "InvalidArgument", message)
+}
+
+func errMalformedJSON(err error) *serverError {
+	return newError(http.StatusBadRequest, "MalformedJSON", fmt.Sprintf("The JSON is not well-formed: %v.", err))
+}
+
+func errMethodNotAllowed() *serverError {
+	return newError(http.StatusMethodNotAllowed, "MethodNotAllowed",
+		"The specified method is not allowed against this resource.")
+}
+
+func errNoSuchBucket(bucketName string) *serverError {
+	return newError(http.StatusNotFound, "NoSuchBucket", fmt.Sprintf("The bucket %s does not exist.", bucketName))
+}
+
+func errNoSuchKey(objectKey string) *serverError {
+	return newError(http.StatusNotFound, "NoSuchKey", fmt.Sprintf("The object %s does not exist.", objectKey))
+}
+
+func errNoSuchUpload(uploadId string) *serverError {
+	return newError(http.StatusNotFound, "NoSuchUpload", fmt.Sprintf("The upload %s does not exist.", uploadId))
+}
+
+func errPreconditionFailed() *serverError {
+	return newError(http.StatusPreconditionFailed, "PreconditionFailed",
+		"At least one of the preconditions you specified did not hold.")
+}
+
+func errNotModified() *serverError {
+	return newError(http.StatusNotModified, "NotModified", "Not Modified")
+}
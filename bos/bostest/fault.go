@@ -0,0 +1,285 @@
+package bostest
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultErrorCodes is the error code returned by a Rule with the status code if no Code is specified.
+var defaultErrorCodes = map[int]string{
+	http.StatusInternalServerError: "InternalError",
+	http.StatusServiceUnavailable:  "ServiceUnavailable",
+	http.StatusTooManyRequests:     "RequestRateLimitExceeded",
+}
+
+// Rule defined a struct for the fault injected into the responses of the fake server,
+// the rules are added by Server.AddRule and matched in order, the first matched rule is applied:
+//
+//	// the first two requests to put "a.txt" fail with 503
+//	server.AddRule(&bostest.Rule{
+//		Method:     "PUT",
+//		Path:       "/my-bucket/a.txt",
+//		Times:      2,
+//		StatusCode: http.StatusServiceUnavailable,
+//	})
+//
+// A rule should not be modified after it is added.
+type Rule struct {
+	// Method is the http method of the requests to match, empty matches any method.
+	Method string
+
+	// Path is the path-style path of the requests to match, such as "/<bucket>/<key>", "/<bucket>" and "/",
+	// a path ending with "*" matches the prefix, empty matches any path.
+	Path string
+
+	// Param is the query parameter the requests to match should have, such as "partNumber".
+	Param string
+
+	// Nth is the index of the first matched request to inject the fault into, starting from 1,
+	// 0 is the same as 1.
+	Nth int
+
+	// Times is how many times the fault is injected, 0 means no limit.
+	Times int
+
+	// StatusCode is the status code of the error returned instead of handling the request,
+	// such as 500, 503 and 429. Code and Message are the error code and message of the error.
+	StatusCode int
+	Code       string
+	Message    string
+
+	// ExpireSignature makes the request fail with 403 RequestExpired as if the signature has expired.
+	ExpireSignature bool
+
+	// Delay is the time to wait before the response is written.
+	Delay time.Duration
+
+	// DropConnection closes the connection after the headers and half of the body are written.
+	DropConnection bool
+
+	// CorruptBody flips a byte in the middle of the body, the headers such as ETag are not changed.
+	CorruptBody bool
+
+	server   *Server
+	matched  int
+	injected int
+}
+
+// Injected returns how many times the fault of the rule is injected.
+func (rule *Rule) Injected() int {
+	rule.server.mutex.Lock()
+	defer rule.server.mutex.Unlock()
+
+	return rule.injected
+}
+
+func (rule *Rule) match(req *request) bool {
+	if rule.Method != "" && !strings.EqualFold(rule.Method, req.Method) {
+		return false
+	}
+
+	if strings.HasSuffix(rule.Path, "*") {
+		if !strings.HasPrefix(req.path(), strings.TrimSuffix(rule.Path, "*")) {
+			return false
+		}
+	} else if rule.Path != "" && rule.Path != req.path() {
+		return false
+	}
+
+	if rule.Param != "" && !req.has(rule.Param) {
+		return false
+	}
+
+	rule.matched++
+
+	if rule.matched < rule.Nth || (rule.Times > 0 && rule.injected >= rule.Times) {
+		return false
+	}
+
+	rule.injected++
+
+	return true
+}
+
+// failure returns the error to return instead of handling the request, nil if the request is handled.
+func (rule *Rule) failure() *serverError {
+	if rule.ExpireSignature {
+		return newError(http.StatusForbidden, "RequestExpired", "The request has expired.")
+	}
+
+	if rule.StatusCode == 0 {
+		return nil
+	}
+
+	code, message := rule.Code, rule.Message
+
+	if code == "" {
+		code = defaultErrorCodes[rule.StatusCode]
+	}
+
+	if code == "" {
+		code = "InternalError"
+	}
+
+	if message == "" {
+		message = "The fault is injected by bostest."
+	}
+
+	return newError(rule.StatusCode, code, message)
+}
+
+// AddRule adds a rule to inject faults into the responses of the fake server, and returns the rule.
+func (s *Server) AddRule(rule *Rule) *Rule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rule.server = s
+	s.rules = append(s.rules, rule)
+
+	return rule
+}
+
+// ClearRules removes all rules added to the fake server.
+func (s *Server) ClearRules() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rules = nil
+}
+
+func (s *Server) matchRule(req *request) *Rule {
+	for _, rule := range s.rules {
+		if rule.match(req) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// RecordedRequest defined a struct for a request received by the fake server.
+type RecordedRequest struct {
+	Method string
+	Host   string
+
+	// Path is the path-style path of the request, such as "/<bucket>/<key>".
+	Path       string
+	BucketName string
+	ObjectKey  string
+	Query      url.Values
+	Header     http.Header
+	Body       []byte
+
+	// StatusCode is the status code of the response, and RequestId is the value of "x-bce-request-id".
+	StatusCode int
+	RequestId  string
+
+	// Fault is the rule applied to the request, nil if no fault is injected.
+	Fault *Rule
+	Time  time.Time
+}
+
+// Requests returns all requests received by the fake server in order.
+func (s *Server) Requests() []*RecordedRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	requests := make([]*RecordedRequest, len(s.requests))
+	copy(requests, s.requests)
+
+	return requests
+}
+
+// ClearRequests removes all recorded requests of the fake server.
+func (s *Server) ClearRequests() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = nil
+}
+
+func (s *Server) record(req *request, resp *responseBuffer, rule *Rule) {
+	s.requests = append(s.requests, &RecordedRequest{
+		Method:     req.Method,
+		Host:       req.Host,
+		Path:       req.path(),
+		BucketName: req.bucketName,
+		ObjectKey:  req.objectKey,
+		Query:      req.query,
+		Header:     req.Header,
+		Body:       req.body,
+		StatusCode: resp.statusCode,
+		RequestId:  req.requestId,
+		Fault:      rule,
+		Time:       time.Now(),
+	})
+}
+
+// responseBuffer keeps the response written by handlers, so faults can be injected before it is sent.
+type responseBuffer struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (resp *responseBuffer) Header() http.Header {
+	return resp.header
+}
+
+func (resp *responseBuffer) WriteHeader(statusCode int) {
+	resp.statusCode = statusCode
+}
+
+func (resp *responseBuffer) Write(byteArray []byte) (int, error) {
+	return resp.body.Write(byteArray)
+}
+
+// writeTo sends the buffered response to w with the fault of rule.
+func (resp *responseBuffer) writeTo(w http.ResponseWriter, rule *Rule) {
+	for key, values := range resp.header {
+		w.Header()[key] = values
+	}
+
+	body := resp.body.Bytes()
+
+	if rule == nil {
+		w.WriteHeader(resp.statusCode)
+		w.Write(body)
+		return
+	}
+
+	if rule.Delay > 0 {
+		time.Sleep(rule.Delay)
+	}
+
+	if rule.CorruptBody && len(body) > 0 {
+		body = append([]byte(nil), body...)
+		body[len(body)/2] ^= 0xff
+	}
+
+	if !rule.DropConnection {
+		w.WriteHeader(resp.statusCode)
+		w.Write(body)
+		return
+	}
+
+	w.WriteHeader(resp.statusCode)
+	w.Write(body[:len(body)/2])
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	if hijacker, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hijacker.Hijack(); err == nil {
+			conn.Close()
+		}
+	}
+}
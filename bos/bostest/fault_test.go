@@ -0,0 +1,149 @@
+package bostest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func newFaultTestClient(server *Server, maxErrorRetry int) *bos.Client {
+	config := server.NewConfig()
+	config.RetryPolicy = bce.NewDefaultRetryPolicy(maxErrorRetry, time.Second)
+	bosClient := bos.NewClient(bos.NewConfig(config))
+
+	if err := bosClient.CreateBucket("bostest-fault", nil); err != nil {
+		panic(err)
+	}
+
+	if _, err := bosClient.PutObject("bostest-fault", "fault.txt", "fault injection", nil, nil); err != nil {
+		panic(err)
+	}
+
+	server.ClearRequests()
+
+	return bosClient
+}
+
+func TestRuleStatusCode(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	bosClient := newFaultTestClient(server, 1)
+	rule := server.AddRule(&Rule{Method: "GET", Path: "/bostest-fault/*", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := bosClient.GetObjectMetadata("bostest-fault", "fault.txt", nil); err != nil {
+		t.Error(util.FormatTest("RuleStatusCode", err.Error(), "nil"))
+	}
+
+	if _, err := bosClient.GetObject("bostest-fault", "fault.txt", nil); err != nil {
+		t.Error(util.FormatTest("RuleStatusCode", err.Error(), "nil"))
+	}
+
+	var statusCodes []string
+
+	for _, req := range server.Requests() {
+		statusCodes = append(statusCodes, fmt.Sprintf("%s %s %d %v", req.Method, req.Path, req.StatusCode, req.Fault == rule))
+	}
+
+	got := strings.Join(statusCodes, ", ")
+	expected := "HEAD /bostest-fault/fault.txt 200 false, GET /bostest-fault/fault.txt 503 true, " +
+		"GET /bostest-fault/fault.txt 200 false"
+
+	if got != expected {
+		t.Error(util.FormatTest("RuleStatusCode", got, expected))
+	}
+
+	if rule.Injected() != 1 {
+		t.Error(util.FormatTest("RuleStatusCode", strconv.Itoa(rule.Injected()), "1"))
+	}
+}
+
+func TestRuleNth(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	bosClient := newFaultTestClient(server, 0)
+	server.AddRule(&Rule{Path: "/bostest-fault/fault.txt", Nth: 2, Times: 1, StatusCode: http.StatusTooManyRequests})
+	server.AddRule(&Rule{Method: "PUT", ExpireSignature: true})
+
+	var codes []string
+
+	for i := 0; i < 3; i++ {
+		_, err := bosClient.GetObjectMetadata("bostest-fault", "fault.txt", nil)
+		codes = append(codes, fmt.Sprintf("%v", err == nil))
+	}
+
+	_, err := bosClient.PutObject("bostest-fault", "fault.txt", "expired", nil, nil)
+
+	if bceError, ok := err.(*bce.Error); ok {
+		codes = append(codes, bceError.Code)
+	}
+
+	got := strings.Join(codes, ", ")
+	expected := "true, false, true, RequestExpired"
+
+	if got != expected {
+		t.Error(util.FormatTest("RuleNth", got, expected))
+	}
+
+	server.ClearRules()
+
+	if _, err := bosClient.PutObject("bostest-fault", "fault.txt", "not expired", nil, nil); err != nil {
+		t.Error(util.FormatTest("RuleNth", err.Error(), "nil"))
+	}
+}
+
+func TestRuleBody(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	bosClient := newFaultTestClient(server, 0)
+
+	cases := []struct {
+		rule     *Rule
+		expected string
+	}{
+		{&Rule{CorruptBody: true}, "*bos.IntegrityError"},
+		{&Rule{DropConnection: true}, "unexpected EOF"},
+		{&Rule{Delay: 100 * time.Millisecond}, "fault injection"},
+	}
+
+	for _, c := range cases {
+		server.ClearRules()
+		server.AddRule(c.rule)
+
+		start := time.Now()
+		object, err := bosClient.GetObject("bostest-fault", "fault.txt", nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("RuleBody", err.Error(), "nil"))
+			continue
+		}
+
+		byteArray, err := ioutil.ReadAll(object.ObjectContent)
+		object.ObjectContent.Close()
+		got := string(byteArray)
+
+		if _, ok := err.(*bos.IntegrityError); ok {
+			got = fmt.Sprintf("%T", err)
+		} else if err != nil {
+			got = err.Error()
+		}
+
+		if got != c.expected {
+			t.Error(util.FormatTest("RuleBody", got, c.expected))
+		}
+
+		if time.Since(start) < c.rule.Delay {
+			t.Error(util.FormatTest("RuleBody", time.Since(start).String(), c.rule.Delay.String()))
+		}
+	}
+}
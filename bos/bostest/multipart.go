@@ -0,0 +1,419 @@
+package bostest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// MAX_PART_NUMBER is the max part number of multipart upload.
+const MAX_PART_NUMBER = 10000
+
+type upload struct {
+	uploadId  string
+	initiated time.Time
+
+	// object is the BOS Object to create by completing the multipart upload, it has no data yet.
+	object *object
+	parts  map[int]*part
+}
+
+type part struct {
+	data         []byte
+	etag         string
+	lastModified time.Time
+}
+
+func (s *Server) getUpload(req *request, b *bucket) (*upload, *serverError) {
+	uploadId := req.query.Get("uploadId")
+	u, ok := b.uploads[uploadId]
+
+	if !ok || u.object.key != req.objectKey {
+		return nil, errNoSuchUpload(uploadId)
+	}
+
+	if req.anonymous {
+		return nil, errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	return u, nil
+}
+
+func (s *Server) initiateMultipartUpload(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	o, err := s.newObject(req, b, nil, OBJECT_TYPE_MULTIPART)
+
+	if err != nil {
+		return err
+	}
+
+	u := &upload{
+		uploadId:  s.nextId(),
+		initiated: time.Now(),
+		object:    o,
+		parts:     make(map[int]*part),
+	}
+
+	b.uploads[u.uploadId] = u
+	s.writeJSON(req, http.StatusOK, map[string]string{
+		"bucket":   b.name,
+		"key":      o.key,
+		"uploadId": u.uploadId,
+	})
+
+	return nil
+}
+
+func parsePartNumber(req *request) (int, *serverError) {
+	partNumber, err := strconv.Atoi(req.query.Get("partNumber"))
+
+	if err != nil || partNumber < 1 || partNumber > MAX_PART_NUMBER {
+		return 0, errInvalidArgument(fmt.Sprintf("Invalid part number %s.", req.query.Get("partNumber")))
+	}
+
+	return partNumber, nil
+}
+
+func (s *Server) uploadPart(req *request, b *bucket) *serverError {
+	u, err := s.getUpload(req, b)
+
+	if err != nil {
+		return err
+	}
+
+	partNumber, err := parsePartNumber(req)
+
+	if err != nil {
+		return err
+	}
+
+	if err := checkCustomerKey(req, u.object, "x-bce-"); err != nil {
+		return err
+	}
+
+	if err := checkDigest(req); err != nil {
+		return err
+	}
+
+	p := &part{data: req.body, etag: md5Hex(req.body), lastModified: time.Now()}
+	u.parts[partNumber] = p
+
+	req.w.Header().Set("ETag", quote(p.etag))
+	req.w.Header().Set("x-bce-content-crc64ecma", strconv.FormatUint(util.GetCRC64(p.data), 10))
+	setEncryptionHeader(req.w.Header(), u.object.encryption)
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) uploadPartCopy(req *request, b *bucket) *serverError {
+	u, err := s.getUpload(req, b)
+
+	if err != nil {
+		return err
+	}
+
+	partNumber, err := parsePartNumber(req)
+
+	if err != nil {
+		return err
+	}
+
+	if err := checkCustomerKey(req, u.object, "x-bce-"); err != nil {
+		return err
+	}
+
+	src, err := s.parseCopySource(req)
+
+	if err != nil {
+		return err
+	}
+
+	data := src.data
+
+	if value := req.Header.Get("x-bce-copy-source-range"); value != "" {
+		start, end, ok, err := parseRange(value, int64(len(src.data)))
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errInvalidArgument(fmt.Sprintf("Invalid copy source range %s.", value))
+		}
+
+		data = src.data[start : end+1]
+	}
+
+	p := &part{data: data, etag: md5Hex(data), lastModified: time.Now()}
+	u.parts[partNumber] = p
+
+	req.w.Header().Set("ETag", quote(p.etag))
+	s.writeJSON(req, http.StatusOK, map[string]string{
+		"eTag":         p.etag,
+		"lastModified": formatTime(p.lastModified),
+	})
+
+	return nil
+}
+
+func (s *Server) completeMultipartUpload(req *request, b *bucket) *serverError {
+	u, err := s.getUpload(req, b)
+
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Parts []struct {
+			PartNumber int    `json:"partNumber"`
+			ETag       string `json:"eTag"`
+		} `json:"parts"`
+	}
+
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		return errMalformedJSON(err)
+	}
+
+	if len(body.Parts) == 0 {
+		return errInvalidArgument("The parts should not be empty.")
+	}
+
+	var data, md5Array []byte
+
+	for index, item := range body.Parts {
+		if index > 0 && item.PartNumber <= body.Parts[index-1].PartNumber {
+			return newError(http.StatusBadRequest, "InvalidPartOrder", "The parts should be in ascending order.")
+		}
+
+		p, ok := u.parts[item.PartNumber]
+
+		if !ok || unquote(item.ETag) != p.etag {
+			return newError(http.StatusBadRequest, "InvalidPart",
+				fmt.Sprintf("The part %d does not exist or the ETag does not match.", item.PartNumber))
+		}
+
+		byteArray, _ := hex.DecodeString(p.etag)
+		md5Array = append(md5Array, byteArray...)
+		data = append(data, p.data...)
+	}
+
+	o := *u.object
+	o.data = data
+	o.etag = md5Hex(md5Array)
+	o.lastModified = time.Now()
+
+	delete(b.uploads, u.uploadId)
+	s.storeObject(b, &o)
+	writeUploadHeader(req, &o)
+	s.writeJSON(req, http.StatusOK, map[string]string{
+		"location": fmt.Sprintf("http://%s/%s", req.Host, o.key),
+		"bucket":   b.name,
+		"key":      o.key,
+		"eTag":     o.etag,
+	})
+
+	return nil
+}
+
+func (s *Server) abortMultipartUpload(req *request, b *bucket) *serverError {
+	u, err := s.getUpload(req, b)
+
+	if err != nil {
+		return err
+	}
+
+	delete(b.uploads, u.uploadId)
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) listParts(req *request, b *bucket) *serverError {
+	u, err := s.getUpload(req, b)
+
+	if err != nil {
+		return err
+	}
+
+	partNumberMarker, err := intParam(req, "partNumberMarker", 0, MAX_PART_NUMBER)
+
+	if err != nil {
+		return err
+	}
+
+	maxParts, err := intParam(req, "maxParts", 1000, 1000)
+
+	if err != nil {
+		return err
+	}
+
+	partNumbers := make([]int, 0, len(u.parts))
+
+	for partNumber := range u.parts {
+		if partNumber > partNumberMarker {
+			partNumbers = append(partNumbers, partNumber)
+		}
+	}
+
+	sort.Ints(partNumbers)
+
+	isTruncated := len(partNumbers) > maxParts
+
+	if isTruncated {
+		partNumbers = partNumbers[:maxParts]
+	}
+
+	parts := make([]map[string]interface{}, 0, len(partNumbers))
+	nextPartNumberMarker := partNumberMarker
+
+	for _, partNumber := range partNumbers {
+		p := u.parts[partNumber]
+		parts = append(parts, map[string]interface{}{
+			"partNumber":   partNumber,
+			"eTag":         p.etag,
+			"lastModified": formatTime(p.lastModified),
+			"size":         len(p.data),
+		})
+		nextPartNumberMarker = partNumber
+	}
+
+	s.writeJSON(req, http.StatusOK, map[string]interface{}{
+		"bucket":               b.name,
+		"key":                  u.object.key,
+		"uploadId":             u.uploadId,
+		"initiated":            formatTime(u.initiated),
+		"owner":                owner(),
+		"storageClass":         u.object.storageClass,
+		"partNumberMarker":     partNumberMarker,
+		"nextPartNumberMarker": nextPartNumberMarker,
+		"maxParts":             maxParts,
+		"isTruncated":          isTruncated,
+		"parts":                parts,
+	})
+
+	return nil
+}
+
+// uploadSlice sorts multipart uploads by key and initiated time.
+type uploadSlice []*upload
+
+func (uploads uploadSlice) Len() int {
+	return len(uploads)
+}
+
+func (uploads uploadSlice) Swap(i, j int) {
+	uploads[i], uploads[j] = uploads[j], uploads[i]
+}
+
+func (uploads uploadSlice) Less(i, j int) bool {
+	if uploads[i].object.key != uploads[j].object.key {
+		return uploads[i].object.key < uploads[j].object.key
+	}
+
+	return uploads[i].initiated.Before(uploads[j].initiated)
+}
+
+func (s *Server) listMultipartUploads(req *request, b *bucket) *serverError {
+	prefix, delimiter, keyMarker := req.query.Get("prefix"), req.query.Get("delimiter"), req.query.Get("keyMarker")
+	uploadIdMarker := req.query.Get("uploadIdMarker")
+	maxUploads, err := intParam(req, "maxUploads", 1000, 1000)
+
+	if err != nil {
+		return err
+	}
+
+	sortedUploads := make(uploadSlice, 0, len(b.uploads))
+
+	for _, u := range b.uploads {
+		sortedUploads = append(sortedUploads, u)
+	}
+
+	sort.Sort(sortedUploads)
+
+	uploads := make([]map[string]interface{}, 0)
+	commonPrefixes := make([]map[string]string, 0)
+	var nextKeyMarker, nextUploadIdMarker string
+	var isTruncated bool
+
+	// the uploads of keyMarker after uploadIdMarker are listed if uploadIdMarker is specified
+	afterUploadIdMarker := false
+
+	for _, u := range sortedUploads {
+		key := u.object.key
+
+		if key == keyMarker && uploadIdMarker != "" {
+			if !afterUploadIdMarker {
+				afterUploadIdMarker = u.uploadId == uploadIdMarker
+				continue
+			}
+		} else if !strings.HasPrefix(key, prefix) || key <= keyMarker {
+			continue
+		}
+
+		item, isPrefix := rollUp(key, prefix, delimiter)
+
+		if isPrefix && (item <= keyMarker || item == nextKeyMarker) {
+			continue
+		}
+
+		if len(uploads)+len(commonPrefixes) >= maxUploads {
+			isTruncated = true
+			break
+		}
+
+		nextUploadIdMarker = ""
+
+		if isPrefix {
+			commonPrefixes = append(commonPrefixes, map[string]string{"prefix": item})
+		} else {
+			nextUploadIdMarker = u.uploadId
+			uploads = append(uploads, map[string]interface{}{
+				"key":          key,
+				"uploadId":     u.uploadId,
+				"initiated":    formatTime(u.initiated),
+				"owner":        owner(),
+				"storageClass": u.object.storageClass,
+			})
+		}
+
+		nextKeyMarker = item
+	}
+
+	result := map[string]interface{}{
+		"bucket":         b.name,
+		"prefix":         prefix,
+		"delimiter":      delimiter,
+		"keyMarker":      keyMarker,
+		"maxUploads":     maxUploads,
+		"isTruncated":    isTruncated,
+		"uploads":        uploads,
+		"commonPrefixes": commonPrefixes,
+	}
+
+	if uploadIdMarker != "" {
+		result["uploadIdMarker"] = uploadIdMarker
+	}
+
+	if isTruncated {
+		result["nextKeyMarker"] = nextKeyMarker
+
+		if nextUploadIdMarker != "" {
+			result["nextUploadIdMarker"] = nextUploadIdMarker
+		}
+	}
+
+	s.writeJSON(req, http.StatusOK, result)
+
+	return nil
+}
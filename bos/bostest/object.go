@@ -0,0 +1,981 @@
+package bostest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+const OBJECT_TYPE_NORMAL = "Normal"
+const OBJECT_TYPE_APPENDABLE = "Appendable"
+const OBJECT_TYPE_MULTIPART = "MultipartUpload"
+
+// MAX_OBJECT_KEY_LENGTH is the max length of the key of BOS Object in bytes.
+const MAX_OBJECT_KEY_LENGTH = 1024
+
+// objectHeaders are the standard http headers stored with BOS Object, the user metadata is stored too.
+var objectHeaders = []string{"Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Type", "Expires"}
+
+// responseHeaderParams are the params to override the headers of GetObject response.
+var responseHeaderParams = map[string]string{
+	"responseCacheControl":       "Cache-Control",
+	"responseContentDisposition": "Content-Disposition",
+	"responseContentEncoding":    "Content-Encoding",
+	"responseContentLanguage":    "Content-Language",
+	"responseContentType":        "Content-Type",
+	"responseExpires":            "Expires",
+}
+
+var storageClasses = []string{"STANDARD", "STANDARD_IA", "COLD", "ARCHIVE"}
+
+// object is a version of BOS Object, or a delete marker.
+type object struct {
+	key          string
+	versionId    string
+	deleteMarker bool
+	data         []byte
+	etag         string
+	contentMD5   string
+	lastModified time.Time
+	objectType   string
+	header       http.Header
+	storageClass string
+	acl          []grant
+	tagging      []byte
+	encryption   *encryption
+	restore      string
+}
+
+// encryption is the server side encryption of BOS Object, the content is not really encrypted.
+type encryption struct {
+	algorithm      string
+	customerKeyMD5 string
+}
+
+func (o *object) summary() map[string]interface{} {
+	return map[string]interface{}{
+		"key":          o.key,
+		"lastModified": formatTime(o.lastModified),
+		"eTag":         o.etag,
+		"size":         len(o.data),
+		"storageClass": o.storageClass,
+		"owner":        owner(),
+	}
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func md5Base64(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func quote(etag string) string {
+	return "\"" + etag + "\""
+}
+
+func unquote(etag string) string {
+	return strings.Trim(strings.TrimSpace(etag), "\"")
+}
+
+// setObjectHeader writes the metadata of BOS Object to the response header.
+func setObjectHeader(h http.Header, o *object) {
+	for key, values := range o.header {
+		h[key] = values
+	}
+
+	h.Set("ETag", quote(o.etag))
+	h.Set("Last-Modified", o.lastModified.UTC().Format(http.TimeFormat))
+	h.Set("Accept-Ranges", "bytes")
+	h.Set("x-bce-storage-class", o.storageClass)
+	h.Set("x-bce-object-type", o.objectType)
+	h.Set("x-bce-content-crc64ecma", strconv.FormatUint(util.GetCRC64(o.data), 10))
+
+	if o.contentMD5 != "" {
+		h.Set("Content-MD5", o.contentMD5)
+	}
+
+	if o.versionId != "" {
+		h.Set("x-bce-version-id", o.versionId)
+	}
+
+	if o.restore != "" {
+		h.Set("x-bce-restore", o.restore)
+	}
+
+	setEncryptionHeader(h, o.encryption)
+}
+
+func setEncryptionHeader(h http.Header, e *encryption) {
+	if e == nil {
+		return
+	}
+
+	if e.customerKeyMD5 != "" {
+		h.Set("x-bce-server-side-encryption-customer-algorithm", e.algorithm)
+		h.Set("x-bce-server-side-encryption-customer-key-md5", e.customerKeyMD5)
+	} else {
+		h.Set("x-bce-server-side-encryption", e.algorithm)
+	}
+}
+
+// parseObjectHeader gets the standard headers and user metadata to store with BOS Object.
+func parseObjectHeader(req *request) http.Header {
+	h := make(http.Header)
+
+	for _, key := range objectHeaders {
+		if value := req.Header.Get(key); value != "" {
+			h.Set(key, value)
+		}
+	}
+
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "application/octet-stream")
+	}
+
+	for key, values := range req.Header {
+		if strings.HasPrefix(strings.ToLower(key), "x-bce-meta-") {
+			h[key] = values
+		}
+	}
+
+	return h
+}
+
+func parseStorageClass(req *request) (string, *serverError) {
+	storageClass := req.Header.Get("x-bce-storage-class")
+
+	if storageClass == "" {
+		return "STANDARD", nil
+	}
+
+	if !util.Contains(storageClasses, storageClass, false) {
+		return "", errInvalidArgument(fmt.Sprintf("Invalid storage class %s.", storageClass))
+	}
+
+	return storageClass, nil
+}
+
+// parseEncryption gets the server side encryption from headers with the prefix "x-bce-",
+// or "x-bce-copy-source-" for the source of copy.
+func parseEncryption(req *request, prefix string) (*encryption, *serverError) {
+	if algorithm := req.Header.Get(prefix + "server-side-encryption"); algorithm != "" {
+		if algorithm != "AES256" && algorithm != "SM4" {
+			return nil, errInvalidArgument(fmt.Sprintf("Invalid server side encryption %s.", algorithm))
+		}
+
+		return &encryption{algorithm: algorithm}, nil
+	}
+
+	algorithm := req.Header.Get(prefix + "server-side-encryption-customer-algorithm")
+	key := req.Header.Get(prefix + "server-side-encryption-customer-key")
+	keyMD5 := req.Header.Get(prefix + "server-side-encryption-customer-key-md5")
+
+	if algorithm == "" && key == "" && keyMD5 == "" {
+		return nil, nil
+	}
+
+	if algorithm != "AES256" {
+		return nil, errInvalidArgument(fmt.Sprintf("Invalid customer algorithm %s.", algorithm))
+	}
+
+	byteArray, err := base64.StdEncoding.DecodeString(key)
+
+	if err != nil || len(byteArray) != 32 {
+		return nil, errInvalidArgument("The customer key should be 32 bytes encoded by base64.")
+	}
+
+	if md5Base64(byteArray) != keyMD5 {
+		return nil, errInvalidArgument("The MD5 of customer key does not match.")
+	}
+
+	return &encryption{algorithm: algorithm, customerKeyMD5: keyMD5}, nil
+}
+
+// checkCustomerKey requires the same customer key to read a BOS Object encrypted with a customer key.
+func checkCustomerKey(req *request, o *object, prefix string) *serverError {
+	if o.encryption == nil || o.encryption.customerKeyMD5 == "" {
+		return nil
+	}
+
+	e, err := parseEncryption(req, prefix)
+
+	if err != nil {
+		return err
+	}
+
+	if e == nil || e.customerKeyMD5 != o.encryption.customerKeyMD5 {
+		return newError(http.StatusBadRequest, "InvalidArgument",
+			fmt.Sprintf("The object %s is encrypted with another customer key.", o.key))
+	}
+
+	return nil
+}
+
+// checkDigest verifies the body by Content-MD5 and x-bce-content-sha256 headers.
+func checkDigest(req *request) *serverError {
+	if contentMD5 := req.Header.Get("Content-MD5"); contentMD5 != "" && contentMD5 != md5Base64(req.body) {
+		return newError(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match.")
+	}
+
+	if sha256Value := req.Header.Get("x-bce-content-sha256"); sha256Value != "" {
+		sum := sha256.Sum256(req.body)
+
+		if !strings.EqualFold(sha256Value, hex.EncodeToString(sum[:])) {
+			return newError(http.StatusBadRequest, "BadDigest",
+				"The x-bce-content-sha256 you specified did not match.")
+		}
+	}
+
+	return nil
+}
+
+// matchETag checks whether the value of If-Match or If-None-Match header matches the etag.
+func matchETag(value, etag string) bool {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "*" || unquote(item) == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkConditions checks the preconditions in headers with prefix, o is nil if the BOS Object does not exist.
+// A 304 error is returned for GET and HEAD requests if the BOS Object is not modified.
+func checkConditions(req *request, o *object, prefix string, read bool) *serverError {
+	header := func(key string) string {
+		return req.Header.Get(prefix + key)
+	}
+
+	notModified := func() *serverError {
+		return errNotModified().withHeader("ETag", quote(o.etag)).
+			withHeader("Last-Modified", o.lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if value := header("if-match"); value != "" && (o == nil || !matchETag(value, o.etag)) {
+		return errPreconditionFailed()
+	}
+
+	if value := header("if-none-match"); value != "" && o != nil && matchETag(value, o.etag) {
+		if read {
+			return notModified()
+		}
+
+		return errPreconditionFailed()
+	}
+
+	lastModified := o.lastModifiedSecond()
+
+	if t, err := http.ParseTime(header("if-unmodified-since")); err == nil && o != nil && lastModified.After(t) {
+		return errPreconditionFailed()
+	}
+
+	if t, err := http.ParseTime(header("if-modified-since")); err == nil && o != nil && !lastModified.After(t) {
+		if read {
+			return notModified()
+		}
+
+		return errPreconditionFailed()
+	}
+
+	return nil
+}
+
+func (o *object) lastModifiedSecond() time.Time {
+	if o == nil {
+		return time.Time{}
+	}
+
+	return o.lastModified.Truncate(time.Second)
+}
+
+// parseRange parses the value of Range header, ok is false if the value is absent or malformed.
+func parseRange(value string, size int64) (start, end int64, ok bool, err *serverError) {
+	if !strings.HasPrefix(value, "bytes=") || strings.Contains(value, ",") {
+		return 0, 0, false, nil
+	}
+
+	parts := strings.SplitN(strings.TrimSpace(value[len("bytes="):]), "-", 2)
+
+	if len(parts) != 2 || (parts[0] == "" && parts[1] == "") {
+		return 0, 0, false, nil
+	}
+
+	invalidRange := newError(http.StatusRequestedRangeNotSatisfiable, "InvalidRange",
+		"The requested range cannot be satisfied.").withHeader("Content-Range", fmt.Sprintf("bytes */%d", size))
+
+	if parts[0] == "" {
+		length, parseError := strconv.ParseInt(parts[1], 10, 64)
+
+		if parseError != nil {
+			return 0, 0, false, nil
+		}
+
+		if length <= 0 || size == 0 {
+			return 0, 0, false, invalidRange
+		}
+
+		if length > size {
+			length = size
+		}
+
+		return size - length, size - 1, true, nil
+	}
+
+	start, parseError := strconv.ParseInt(parts[0], 10, 64)
+
+	if parseError != nil {
+		return 0, 0, false, nil
+	}
+
+	end = size - 1
+
+	if parts[1] != "" {
+		if end, parseError = strconv.ParseInt(parts[1], 10, 64); parseError != nil || end < start {
+			return 0, 0, false, nil
+		}
+	}
+
+	if start >= size {
+		return 0, 0, false, invalidRange
+	}
+
+	if end >= size {
+		end = size - 1
+	}
+
+	return start, end, true, nil
+}
+
+// findObject gets the latest version of BOS Object, or the version specified by versionId.
+func findObject(b *bucket, key, versionId string) (*object, *serverError) {
+	versions := b.objects[key]
+
+	if versionId == "" {
+		if len(versions) == 0 || versions[0].deleteMarker {
+			return nil, errNoSuchKey(key)
+		}
+
+		return versions[0], nil
+	}
+
+	for _, o := range versions {
+		if o.versionId == versionId {
+			if o.deleteMarker {
+				return nil, errNoSuchKey(key)
+			}
+
+			return o, nil
+		}
+	}
+
+	return nil, newError(http.StatusNotFound, "NoSuchVersion", fmt.Sprintf("The version %s does not exist.", versionId))
+}
+
+// storeObject adds a new version of BOS Object by the versioning status of BOS Bucket.
+func (s *Server) storeObject(b *bucket, o *object) {
+	switch b.versioning {
+	case "enabled":
+		o.versionId = s.nextId()
+		b.objects[o.key] = append([]*object{o}, b.objects[o.key]...)
+	case "suspended":
+		o.versionId = "null"
+		versions := []*object{o}
+
+		for _, version := range b.objects[o.key] {
+			if version.versionId != "null" {
+				versions = append(versions, version)
+			}
+		}
+
+		b.objects[o.key] = versions
+	default:
+		b.objects[o.key] = []*object{o}
+	}
+}
+
+// removeObject deletes a version of BOS Object, or the BOS Object if versionId is empty.
+// A delete marker is added instead if versioning of BOS Bucket is enabled or suspended.
+func (s *Server) removeObject(b *bucket, key, versionId string) (*object, *serverError) {
+	versions := b.objects[key]
+
+	if versionId != "" {
+		for index, o := range versions {
+			if o.versionId == versionId {
+				versions = append(versions[:index:index], versions[index+1:]...)
+
+				if len(versions) == 0 {
+					delete(b.objects, key)
+				} else {
+					b.objects[key] = versions
+				}
+
+				return o, nil
+			}
+		}
+
+		return nil, newError(http.StatusNotFound, "NoSuchVersion",
+			fmt.Sprintf("The version %s does not exist.", versionId))
+	}
+
+	if len(versions) == 0 || versions[0].deleteMarker {
+		return nil, errNoSuchKey(key)
+	}
+
+	if b.versioning == "" {
+		delete(b.objects, key)
+		return versions[0], nil
+	}
+
+	marker := &object{key: key, deleteMarker: true, lastModified: time.Now(), storageClass: "STANDARD"}
+	s.storeObject(b, marker)
+
+	return marker, nil
+}
+
+func (s *Server) serveObject(req *request) *serverError {
+	b, err := s.getBucket(req)
+
+	if err != nil {
+		return err
+	}
+
+	if len(req.objectKey) > MAX_OBJECT_KEY_LENGTH {
+		return newError(http.StatusBadRequest, "InvalidObjectName",
+			fmt.Sprintf("The object key should not be longer than %d bytes.", MAX_OBJECT_KEY_LENGTH))
+	}
+
+	if req.Method == "OPTIONS" {
+		return s.optionsObject(req, b)
+	}
+
+	switch req.Method {
+	case "GET", "HEAD":
+		switch {
+		case req.has("acl"):
+			return s.getObjectAcl(req, b)
+		case req.has("tagging"):
+			return s.getObjectTagging(req, b)
+		case req.has("uploadId"):
+			return s.listParts(req, b)
+		}
+
+		return s.getObject(req, b)
+	case "PUT":
+		switch {
+		case req.has("acl"):
+			return s.putObjectAcl(req, b)
+		case req.has("tagging"):
+			return s.putObjectTagging(req, b)
+		case req.has("uploadId") && req.Header.Get("x-bce-copy-source") != "":
+			return s.uploadPartCopy(req, b)
+		case req.has("uploadId"):
+			return s.uploadPart(req, b)
+		case req.Header.Get("x-bce-copy-source") != "":
+			return s.copyObject(req, b)
+		}
+
+		return s.putObject(req, b)
+	case "POST":
+		switch {
+		case req.has("append"):
+			return s.appendObject(req, b)
+		case req.has("uploads"):
+			return s.initiateMultipartUpload(req, b)
+		case req.has("uploadId"):
+			return s.completeMultipartUpload(req, b)
+		case req.has("restore"):
+			return s.restoreObject(req, b)
+		}
+	case "DELETE":
+		switch {
+		case req.has("acl"):
+			return s.deleteObjectAcl(req, b)
+		case req.has("tagging"):
+			return s.deleteObjectTagging(req, b)
+		case req.has("uploadId"):
+			return s.abortMultipartUpload(req, b)
+		}
+
+		return s.deleteObject(req, b)
+	}
+
+	return errMethodNotAllowed()
+}
+
+// newObject creates a BOS Object from the headers of request, the encryption of BOS Bucket is the default one.
+func (s *Server) newObject(req *request, b *bucket, data []byte, objectType string) (*object, *serverError) {
+	storageClass, err := parseStorageClass(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := parseEncryption(req, "x-bce-")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := b.subResources["encryption"]; ok && e == nil {
+		e = &encryption{algorithm: "AES256"}
+	}
+
+	o := &object{
+		key:          req.objectKey,
+		data:         data,
+		etag:         md5Hex(data),
+		lastModified: time.Now(),
+		objectType:   objectType,
+		header:       parseObjectHeader(req),
+		storageClass: storageClass,
+		encryption:   e,
+	}
+
+	if acl := req.Header.Get("x-bce-acl"); acl != "" {
+		if o.acl, err = cannedAcl(acl); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+func writeUploadHeader(req *request, o *object) {
+	h := req.w.Header()
+	h.Set("ETag", quote(o.etag))
+	h.Set("x-bce-content-crc64ecma", strconv.FormatUint(util.GetCRC64(o.data), 10))
+
+	if o.versionId != "" {
+		h.Set("x-bce-version-id", o.versionId)
+	}
+
+	setEncryptionHeader(h, o.encryption)
+}
+
+func (s *Server) putObject(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	current, _ := findObject(b, req.objectKey, "")
+
+	if err := checkConditions(req, current, "", false); err != nil {
+		return err
+	}
+
+	if err := checkDigest(req); err != nil {
+		return err
+	}
+
+	o, err := s.newObject(req, b, req.body, OBJECT_TYPE_NORMAL)
+
+	if err != nil {
+		return err
+	}
+
+	o.contentMD5 = req.Header.Get("Content-MD5")
+	s.storeObject(b, o)
+	writeUploadHeader(req, o)
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) appendObject(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	offset, err := intParam(req, "offset", 0, int(^uint(0)>>1))
+
+	if err != nil {
+		return err
+	}
+
+	if err := checkDigest(req); err != nil {
+		return err
+	}
+
+	o, _ := findObject(b, req.objectKey, "")
+
+	if o != nil && o.objectType != OBJECT_TYPE_APPENDABLE {
+		return newError(http.StatusConflict, "ObjectUnappendable",
+			fmt.Sprintf("The object %s is not appendable.", req.objectKey))
+	}
+
+	size := 0
+
+	if o != nil {
+		size = len(o.data)
+	}
+
+	if offset != size {
+		return newError(http.StatusConflict, "OffsetIncorrect",
+			fmt.Sprintf("The offset %d is not the same as the size %d of object.", offset, size))
+	}
+
+	if o == nil {
+		if o, err = s.newObject(req, b, req.body, OBJECT_TYPE_APPENDABLE); err != nil {
+			return err
+		}
+
+		s.storeObject(b, o)
+	} else {
+		o.data = append(o.data[:len(o.data):len(o.data)], req.body...)
+		o.etag = md5Hex(o.data)
+		o.lastModified = time.Now()
+	}
+
+	writeUploadHeader(req, o)
+	req.w.Header().Set("Content-MD5", md5Base64(req.body))
+	req.w.Header().Set("x-bce-next-append-offset", strconv.Itoa(len(o.data)))
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) getObject(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	grants := o.acl
+
+	if grants == nil {
+		grants = b.acl
+	}
+
+	if err := checkAccess(req, grants, "READ"); err != nil {
+		return err
+	}
+
+	if err := checkCustomerKey(req, o, "x-bce-"); err != nil {
+		return err
+	}
+
+	if err := checkConditions(req, o, "", true); err != nil {
+		return err
+	}
+
+	if req.Method == "GET" && o.storageClass == "ARCHIVE" && !strings.Contains(o.restore, "ongoing-request=\"false\"") {
+		return newError(http.StatusForbidden, "InvalidObjectState",
+			fmt.Sprintf("The object %s is archived, please restore it first.", o.key))
+	}
+
+	h := req.w.Header()
+	setObjectHeader(h, o)
+
+	for param, key := range responseHeaderParams {
+		if value := req.query.Get(param); value != "" {
+			h.Set(key, value)
+		}
+	}
+
+	size := int64(len(o.data))
+	start, end, ok, err := parseRange(req.Header.Get("Range"), size)
+
+	if err != nil {
+		return err
+	}
+
+	statusCode := http.StatusOK
+	data := o.data
+
+	if ok {
+		statusCode = http.StatusPartialContent
+		data = o.data[start : end+1]
+		h.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	}
+
+	h.Set("Content-Length", strconv.Itoa(len(data)))
+	req.w.WriteHeader(statusCode)
+
+	if req.Method == "GET" {
+		req.w.Write(data)
+	}
+
+	return nil
+}
+
+func (s *Server) deleteObject(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	o, err := s.removeObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if o.versionId != "" {
+		req.w.Header().Set("x-bce-version-id", o.versionId)
+	}
+
+	if o.deleteMarker {
+		req.w.Header().Set("x-bce-delete-marker", "true")
+	}
+
+	s.writeEmpty(req, http.StatusNoContent)
+
+	return nil
+}
+
+// parseCopySource gets the source BOS Object from x-bce-copy-source header, such as "/bucket/key?versionId=1".
+func (s *Server) parseCopySource(req *request) (*object, *serverError) {
+	source := req.Header.Get("x-bce-copy-source")
+	var versionId string
+
+	if index := strings.Index(source, "?"); index >= 0 {
+		query, _ := url.ParseQuery(source[index+1:])
+		versionId = query.Get("versionId")
+		source = source[:index]
+	}
+
+	source, unescapeError := url.QueryUnescape(source)
+	parts := strings.SplitN(strings.TrimPrefix(source, "/"), "/", 2)
+
+	if unescapeError != nil || len(parts) != 2 || parts[1] == "" {
+		return nil, errInvalidArgument(fmt.Sprintf("Invalid copy source %s.", req.Header.Get("x-bce-copy-source")))
+	}
+
+	srcBucket, ok := s.buckets[parts[0]]
+
+	if !ok {
+		return nil, errNoSuchBucket(parts[0])
+	}
+
+	o, err := findObject(srcBucket, parts[1], versionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccess(req, srcBucket.acl, "READ"); err != nil {
+		return nil, err
+	}
+
+	if err := checkCustomerKey(req, o, "x-bce-copy-source-"); err != nil {
+		return nil, err
+	}
+
+	if err := checkConditions(req, o, "x-bce-copy-source-", false); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (s *Server) copyObject(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	src, err := s.parseCopySource(req)
+
+	if err != nil {
+		return err
+	}
+
+	o, err := s.newObject(req, b, src.data, src.objectType)
+
+	if err != nil {
+		return err
+	}
+
+	o.etag = src.etag
+	o.contentMD5 = src.contentMD5
+
+	switch directive := req.Header.Get("x-bce-metadata-directive"); directive {
+	case "", "copy":
+		o.header = make(http.Header)
+
+		for key, values := range src.header {
+			o.header[key] = values
+		}
+
+		if req.Header.Get("x-bce-storage-class") == "" {
+			o.storageClass = src.storageClass
+		}
+	case "replace":
+	default:
+		return errInvalidArgument(fmt.Sprintf("Invalid metadata directive %s.", directive))
+	}
+
+	s.storeObject(b, o)
+	writeUploadHeader(req, o)
+	s.writeJSON(req, http.StatusOK, map[string]string{
+		"eTag":         o.etag,
+		"lastModified": formatTime(o.lastModified),
+	})
+
+	return nil
+}
+
+func (s *Server) restoreObject(req *request, b *bucket) *serverError {
+	if err := checkAccess(req, b.acl, "WRITE"); err != nil {
+		return err
+	}
+
+	o, err := findObject(b, req.objectKey, "")
+
+	if err != nil {
+		return err
+	}
+
+	if o.storageClass != "ARCHIVE" {
+		return newError(http.StatusBadRequest, "InvalidObjectState",
+			fmt.Sprintf("The object %s is not archived.", o.key))
+	}
+
+	days := 7
+
+	if value := req.Header.Get("x-bce-restore-days"); value != "" {
+		var parseError error
+
+		if days, parseError = strconv.Atoi(value); parseError != nil || days < 1 || days > 30 {
+			return errInvalidArgument(fmt.Sprintf("Invalid restore days %s.", value))
+		}
+	}
+
+	expiryDate := time.Now().UTC().Add(time.Duration(days) * 24 * time.Hour).Format(http.TimeFormat)
+	o.restore = fmt.Sprintf("ongoing-request=\"false\", expiry-date=\"%s\"", expiryDate)
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) getObjectAcl(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	if o.acl == nil {
+		return newError(http.StatusNotFound, "NoSuchObjectAcl",
+			fmt.Sprintf("The acl of object %s does not exist.", o.key))
+	}
+
+	s.writeJSON(req, http.StatusOK, map[string]interface{}{"accessControlList": o.acl})
+
+	return nil
+}
+
+func (s *Server) putObjectAcl(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	grants, err := parseAcl(req)
+
+	if err != nil {
+		return err
+	}
+
+	o.acl = grants
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) deleteObjectAcl(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	o.acl = nil
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) getObjectTagging(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	if o.tagging == nil {
+		return newError(http.StatusNotFound, "NoSuchTagSet", fmt.Sprintf("The object %s has no tags.", o.key))
+	}
+
+	s.writeJSON(req, http.StatusOK, rawJSON(o.tagging))
+
+	return nil
+}
+
+func (s *Server) putObjectTagging(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	if err := validateJSON(req.body); err != nil {
+		return err
+	}
+
+	o.tagging = req.body
+	s.writeEmpty(req, http.StatusOK)
+
+	return nil
+}
+
+func (s *Server) deleteObjectTagging(req *request, b *bucket) *serverError {
+	o, err := findObject(b, req.objectKey, req.query.Get("versionId"))
+
+	if err != nil {
+		return err
+	}
+
+	if req.anonymous {
+		return errAccessDenied("Anonymous access is forbidden.")
+	}
+
+	o.tagging = nil
+	s.writeEmpty(req, http.StatusNoContent)
+
+	return nil
+}
@@ -0,0 +1,350 @@
+// Package bostest provides an in-memory fake BOS server, so the code using bos.Client can be tested
+// without network and real credentials.
+//
+// The fake server works as a http proxy, the requests of bce.Client are sent to it by the ProxyHost and
+// ProxyPort of bce.Config, so the URLs of requests are the same as real BOS:
+//
+//	server := bostest.NewServer()
+//	defer server.Close()
+//
+//	bosClient := bos.NewClient(bos.NewConfig(server.NewConfig()))
+//
+// The signature of each request is verified with the credentials of the server,
+// and the errors are returned in the same JSON format as BOS.
+//
+// Faults such as errors, slow responses, dropped connections and corrupted bodies can be injected by
+// Server.AddRule to test the retry and resume logic, and all requests are recorded for assertions:
+//
+//	server.AddRule(&bostest.Rule{Method: "GET", Path: "/my-bucket/*", StatusCode: http.StatusServiceUnavailable, Times: 1})
+//
+//	// ...
+//
+//	for _, req := range server.Requests() {
+//		fmt.Println(req.Method, req.Path, req.StatusCode)
+//	}
+package bostest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// DEFAULT_ENDPOINT_SUFFIX is the suffix of hosts accepted by the fake server if no Endpoint is specified,
+// the host is "<region>.bcebos.com" or "<bucket>.<region>.bcebos.com".
+const DEFAULT_ENDPOINT_SUFFIX = ".bcebos.com"
+
+// OWNER_ID is the id of the owner of all BOS Buckets in the fake server.
+const OWNER_ID = "bostest-owner"
+const OWNER_DISPLAY_NAME = "bostest"
+
+// DefaultCredentials is the credentials of a new bostest.Server.
+var DefaultCredentials = bce.NewCredentials("bostest-access-key-id", "bostest-secret-access-key")
+
+// Server is an in-memory fake BOS server based on httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// Credentials is used to verify the signature of requests.
+	Credentials *bce.Credentials
+
+	// Endpoint is the host of the fake BOS service, such as "bos.example.com".
+	// If it is empty, all hosts of BOS regions ending with DEFAULT_ENDPOINT_SUFFIX are accepted.
+	Endpoint string
+
+	// Region is the location of BOS Buckets created by a custom Endpoint.
+	Region string
+
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	sessions  map[string]*session
+	sequence  int64
+	startTime time.Time
+	rules     []*Rule
+	requests  []*RecordedRequest
+}
+
+// NewServer starts and returns a new bostest.Server, the caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		Credentials: DefaultCredentials,
+		Region:      bce.Region["bj"],
+		buckets:     make(map[string]*bucket),
+		sessions:    make(map[string]*session),
+		startTime:   time.Now(),
+	}
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewConfig returns a bce.Config whose requests are sent to the fake server.
+func (s *Server) NewConfig() *bce.Config {
+	host, port := s.proxyAddress()
+
+	return &bce.Config{
+		Credentials: s.Credentials,
+		Region:      s.Region,
+		Endpoint:    s.Endpoint,
+		ProxyHost:   host,
+		ProxyPort:   port,
+	}
+}
+
+// HTTPClient returns a http.Client whose requests are sent to the fake server,
+// it is used to send requests without bce.Client, such as a presigned URL.
+func (s *Server) HTTPClient() *http.Client {
+	proxyUrl, _ := url.Parse(s.URL)
+
+	return &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyUrl)}}
+}
+
+func (s *Server) proxyAddress() (string, int) {
+	host, portString, _ := net.SplitHostPort(s.Listener.Addr().String())
+	port, _ := strconv.Atoi(portString)
+
+	return host, port
+}
+
+// request is a parsed http request sent to the fake server.
+type request struct {
+	*http.Request
+	w          http.ResponseWriter
+	requestId  string
+	bucketName string
+	objectKey  string
+	region     string
+	query      url.Values
+	body       []byte
+	anonymous  bool
+
+	// sts is true if the request is sent to the STS service.
+	sts bool
+}
+
+func (req *request) has(param string) bool {
+	_, ok := req.query[param]
+	return ok
+}
+
+// path returns the path-style path of the request, such as "/<bucket>/<key>".
+func (req *request) path() string {
+	if req.bucketName == "" {
+		return "/" + req.objectKey
+	}
+
+	if req.objectKey == "" {
+		return "/" + req.bucketName
+	}
+
+	return "/" + req.bucketName + "/" + req.objectKey
+}
+
+// ServeHTTP dispatches the request to the handler of the BOS API, and injects the fault of the matched rule.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, readError := ioutil.ReadAll(r.Body)
+	resp := newResponseBuffer()
+
+	s.mutex.Lock()
+
+	s.sequence++
+	req := &request{
+		Request:   r,
+		w:         resp,
+		requestId: fmt.Sprintf("bostest-%s-%08d", s.startTime.Format("20060102150405"), s.sequence),
+		query:     r.URL.Query(),
+		body:      body,
+	}
+
+	resp.Header().Set("Server", "BceBos")
+	resp.Header().Set("x-bce-request-id", req.requestId)
+	resp.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+
+	hostError := s.parseHost(req)
+	rule := s.matchRule(req)
+	var err *serverError
+
+	if readError != nil {
+		err = newError(http.StatusBadRequest, "InvalidRequest", readError.Error())
+	} else if rule != nil && rule.failure() != nil {
+		err = rule.failure()
+	} else if hostError != nil {
+		err = hostError
+	} else {
+		err = s.serve(req)
+	}
+
+	if err != nil {
+		s.writeError(req, err)
+	}
+
+	s.record(req, resp, rule)
+	s.mutex.Unlock()
+
+	resp.writeTo(w, rule)
+}
+
+func (s *Server) serve(req *request) *serverError {
+	if req.sts {
+		if err := s.authenticate(req); err != nil {
+			return err
+		}
+
+		return s.getSessionToken(req)
+	}
+
+	if req.Method != "OPTIONS" {
+		if err := s.authenticate(req); err != nil {
+			return err
+		}
+	}
+
+	if req.bucketName == "" {
+		if req.Method == "GET" && req.objectKey == "" {
+			return s.listBuckets(req)
+		}
+
+		return errMethodNotAllowed()
+	}
+
+	if req.objectKey == "" {
+		return s.serveBucket(req)
+	}
+
+	return s.serveObject(req)
+}
+
+// parseHost gets the bucket name and region from the host, and the object key from the path.
+func (s *Server) parseHost(req *request) *serverError {
+	var prefix string
+	host := req.Host
+
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	req.objectKey = strings.TrimPrefix(req.URL.Path, "/")
+
+	if strings.HasPrefix(host, "sts.") {
+		req.sts = true
+		return nil
+	}
+
+	if s.Endpoint != "" {
+		endpoint := s.Endpoint
+
+		if h, _, err := net.SplitHostPort(endpoint); err == nil {
+			endpoint = h
+		}
+
+		if host != endpoint && !strings.HasSuffix(host, "."+endpoint) {
+			return newError(http.StatusBadRequest, "InvalidURI", fmt.Sprintf("Unknown host %s.", host))
+		}
+
+		prefix = strings.TrimSuffix(strings.TrimSuffix(host, endpoint), ".")
+		req.region = s.Region
+	} else {
+		if !strings.HasSuffix(host, DEFAULT_ENDPOINT_SUFFIX) {
+			return newError(http.StatusBadRequest, "InvalidURI", fmt.Sprintf("Unknown host %s.", host))
+		}
+
+		labels := strings.Split(strings.TrimSuffix(host, DEFAULT_ENDPOINT_SUFFIX), ".")
+
+		if len(labels) > 2 {
+			return newError(http.StatusBadRequest, "InvalidURI", fmt.Sprintf("Unknown host %s.", host))
+		}
+
+		req.region = labels[len(labels)-1]
+
+		if len(labels) == 2 {
+			prefix = labels[0]
+		}
+	}
+
+	req.bucketName = prefix
+
+	return nil
+}
+
+// nextId returns an unique id for version ids, upload ids and so on.
+func (s *Server) nextId() string {
+	s.sequence++
+	return fmt.Sprintf("%x%08x", s.startTime.UnixNano(), s.sequence)
+}
+
+func (s *Server) writeJSON(req *request, statusCode int, v interface{}) {
+	byteArray, err := json.Marshal(v)
+
+	if err != nil {
+		s.writeError(req, newError(http.StatusInternalServerError, "InternalError", err.Error()))
+		return
+	}
+
+	req.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	req.w.Header().Set("Content-Length", strconv.Itoa(len(byteArray)))
+	req.w.WriteHeader(statusCode)
+	req.w.Write(byteArray)
+}
+
+func (s *Server) writeEmpty(req *request, statusCode int) {
+	req.w.Header().Set("Content-Length", "0")
+	req.w.WriteHeader(statusCode)
+}
+
+func (s *Server) writeError(req *request, err *serverError) {
+	for key, value := range err.header {
+		req.w.Header().Set(key, value)
+	}
+
+	if req.Method == "HEAD" || err.StatusCode == http.StatusNotModified {
+		req.w.WriteHeader(err.StatusCode)
+		return
+	}
+
+	byteArray, _ := json.Marshal(map[string]string{
+		"code":      err.Code,
+		"message":   err.Message,
+		"requestId": req.requestId,
+	})
+
+	req.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	req.w.Header().Set("Content-Length", strconv.Itoa(len(byteArray)))
+	req.w.WriteHeader(err.StatusCode)
+	req.w.Write(byteArray)
+}
+
+// rawJSON is the stored JSON written to response as is.
+type rawJSON []byte
+
+func (r rawJSON) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+func validateJSON(body []byte) *serverError {
+	var v interface{}
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return errMalformedJSON(err)
+	}
+
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func owner() map[string]string {
+	return map[string]string{"id": OWNER_ID, "displayName": OWNER_DISPLAY_NAME}
+}
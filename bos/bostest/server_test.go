@@ -0,0 +1,139 @@
+package bostest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestAuthenticate(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	listBuckets := func(credentials *bce.Credentials) error {
+		config := server.NewConfig()
+		config.Credentials = credentials
+		config.RetryPolicy = bce.NewDefaultRetryPolicy(0, 0)
+		client := bce.NewClient(config)
+
+		req, err := bce.NewRequest("GET", client.GetURL(config.GetRegion()+".bcebos.com", "", nil), nil)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = client.SendRequest(req, nil)
+
+		return err
+	}
+
+	if err := listBuckets(server.Credentials); err != nil {
+		t.Error(util.FormatTest("authenticate", err.Error(), "nil"))
+	}
+
+	cases := []struct {
+		credentials *bce.Credentials
+		code        string
+	}{
+		{bce.NewCredentials(server.Credentials.AccessKeyID, "wrong-secret-access-key"), "SignatureDoesNotMatch"},
+		{bce.NewCredentials("unknown-access-key-id", server.Credentials.SecretAccessKey), "InvalidAccessKeyId"},
+	}
+
+	for _, c := range cases {
+		err := listBuckets(c.credentials)
+		bceError, ok := err.(*bce.Error)
+
+		if !ok {
+			t.Error(util.FormatTest("authenticate", fmt.Sprintf("%v", err), c.code))
+		} else if bceError.StatusCode != http.StatusForbidden || bceError.Code != c.code || bceError.RequestID == "" {
+			t.Error(util.FormatTest("authenticate", bceError.Error(), c.code))
+		}
+	}
+
+	resp, err := server.HTTPClient().Get("http://bj.bcebos.com/")
+
+	if err != nil {
+		t.Error(util.FormatTest("authenticate", err.Error(), "nil"))
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusForbidden {
+		t.Error(util.FormatTest("authenticate", strconv.Itoa(resp.StatusCode), "403"))
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	cases := []struct {
+		value      string
+		start, end int64
+		ok         bool
+		statusCode int
+	}{
+		{"bytes=0-9", 0, 9, true, 0},
+		{"bytes=5-", 5, 99, true, 0},
+		{"bytes=90-200", 90, 99, true, 0},
+		{"bytes=-10", 90, 99, true, 0},
+		{"bytes=-200", 0, 99, true, 0},
+		{"bytes=100-", 0, 0, false, http.StatusRequestedRangeNotSatisfiable},
+		{"bytes=9-5", 0, 0, false, 0},
+		{"bytes=a-b", 0, 0, false, 0},
+		{"items=0-9", 0, 0, false, 0},
+		{"", 0, 0, false, 0},
+	}
+
+	for _, c := range cases {
+		start, end, ok, err := parseRange(c.value, 100)
+		statusCode := 0
+
+		if err != nil {
+			statusCode = err.StatusCode
+		}
+
+		got := fmt.Sprintf("%d-%d %v %d", start, end, ok, statusCode)
+		expected := fmt.Sprintf("%d-%d %v %d", c.start, c.end, c.ok, c.statusCode)
+
+		if got != expected {
+			t.Error(util.FormatTest("parseRange "+c.value, got, expected))
+		}
+	}
+}
+
+func TestRollUp(t *testing.T) {
+	cases := []struct {
+		key, prefix, delimiter, expected string
+		isPrefix                         bool
+	}{
+		{"a/b/c.txt", "", "/", "a/", true},
+		{"a/b/c.txt", "a/", "/", "a/b/", true},
+		{"a/b/c.txt", "a/b/", "/", "a/b/c.txt", false},
+		{"a/b/c.txt", "", "", "a/b/c.txt", false},
+	}
+
+	for _, c := range cases {
+		item, isPrefix := rollUp(c.key, c.prefix, c.delimiter)
+
+		if item != c.expected || isPrefix != c.isPrefix {
+			t.Error(util.FormatTest("rollUp "+c.key, item, c.expected))
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		expected   bool
+	}{
+		{"http://*", "http://www.example.com", true},
+		{"https://*", "http://www.example.com", false},
+		{"*.example.com", "www.example.com", true},
+		{"x-bce-test", "X-Bce-Test", true},
+		{"*", "anything", true},
+	}
+
+	for _, c := range cases {
+		if got := matchPattern(c.pattern, c.s); got != c.expected {
+			t.Error(util.FormatTest("matchPattern "+c.pattern, strconv.FormatBool(got), strconv.FormatBool(c.expected)))
+		}
+	}
+}
@@ -30,6 +30,28 @@ import (
 	"github.com/guoyao/baidubce-sdk-go/util"
 )
 
+// MIN_PART_SIZE is the min part size for multipart upload and multipart copy except the last part.
+const MIN_PART_SIZE int64 = 1024 * 1024 * 5
+
+// MAX_PART_SIZE is the max part size for multipart upload and multipart copy.
+const MAX_PART_SIZE int64 = 1024 * 1024 * 1024 * 5
+
+// MAX_DELETE_MULTIPLE_OBJECTS is the max count of keys in one bos.DeleteMultipleObjects request.
+const MAX_DELETE_MULTIPLE_OBJECTS int = 1000
+
+// DELETE_PREFIX_MAX_RETRY is the max retry count of the keys failed with retryable errors in bos.DeletePrefix.
+const DELETE_PREFIX_MAX_RETRY int = 3
+
+// DELETE_PREFIX_RETRY_DELAY is the delay before the first retry of bos.DeletePrefix, it is doubled for each retry.
+const DELETE_PREFIX_RETRY_DELAY time.Duration = 100 * time.Millisecond
+
+// MIN_BUCKET_NAME_LENGTH and MAX_BUCKET_NAME_LENGTH are the length range of BOS Bucket name.
+const MIN_BUCKET_NAME_LENGTH int = 3
+const MAX_BUCKET_NAME_LENGTH int = 63
+
+// MAX_OBJECT_KEY_LENGTH is the max length of BOS Object key in bytes.
+const MAX_OBJECT_KEY_LENGTH int = 1024
+
 // MIN_PART_NUMBER is the min part number for multipart upload.
 const MIN_PART_NUMBER int = 1
 
@@ -42,6 +64,34 @@ const MAX_PART_NUMBER int = 10000
 const STORAGE_CLASS_STANDARD = "STANDARD"
 const STORAGE_CLASS_STANDARD_IA = "STANDARD_IA"
 const STORAGE_CLASS_COLD = "COLD"
+const STORAGE_CLASS_ARCHIVE = "ARCHIVE"
+
+// RESTORE_TIER is the speed of restoring an archived BOS object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#RestoreObject
+const RESTORE_TIER_EXPEDITED = "Expedited"
+const RESTORE_TIER_STANDARD = "Standard"
+const RESTORE_TIER_LOWCOST = "LowCost"
+
+// MIN_RESTORE_DAYS and MAX_RESTORE_DAYS are the valid range of days an archived BOS object keeps restored.
+const MIN_RESTORE_DAYS int = 1
+const MAX_RESTORE_DAYS int = 30
+
+// SERVER_SIDE_ENCRYPTION is the algorithm of BOS server side encryption.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketEncryption
+const SERVER_SIDE_ENCRYPTION_AES256 = "AES256"
+const SERVER_SIDE_ENCRYPTION_SM4 = "SM4"
+
+// VERSIONING is the versioning status of BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketVersioning
+const VERSIONING_ENABLED = "enabled"
+const VERSIONING_SUSPENDED = "suspended"
+
+// STATUS is the status of a rule of BOS Bucket configuration, such as lifecycle and replication.
+const STATUS_ENABLED = "enabled"
+const STATUS_DISABLED = "disabled"
 
 // UserDefinedMetadataPrefix is the prefix of custom metadata.
 //
@@ -86,7 +136,7 @@ type BucketAcl struct {
 	AccessControlList []Grant     `json:"accessControlList"`
 }
 
-// BucketAcl defined a struct for grantee and permission info.
+// Grant defined a struct for grantee and permission info.
 type Grant struct {
 	Grantee    []BucketGrantee `json:"grantee"`
 	Permission []string        `json:"permission"`
@@ -111,6 +161,76 @@ type ObjectMetadata struct {
 	ETag         string
 	UserMetadata map[string]string
 	StorageClass string
+	VersionId    string
+
+	// ContentCrc64 is the CRC64 (ECMA) of the content, and ObjectType is the type of BOS Object,
+	// such as OBJECT_TYPE_NORMAL. Both of them are returned by BOS and ignored when creating a BOS Object.
+	ContentCrc64 string
+	ObjectType   string
+
+	// Acl is the canned ACL of BOS Object, it is only used when creating a BOS Object.
+	Acl string
+
+	// Restore is the restore status of an archived BOS Object, it is nil if the BOS Object has never been restored.
+	Restore *ObjectRestoreStatus
+
+	// ServerSideEncryption encrypts the BOS Object at rest when creating it, and it is
+	// parsed from the response, the CustomerKey is never returned by BOS.
+	ServerSideEncryption *ServerSideEncryption
+}
+
+// ObjectRestoreStatus defined a struct for the restore status of an archived BOS Object.
+type ObjectRestoreStatus struct {
+	OngoingRequest bool
+	ExpiryDate     string
+}
+
+// IsRestored checks whether an archived BOS Object is restored and can be read now.
+func (metadata *ObjectMetadata) IsRestored() bool {
+	return metadata.Restore != nil && !metadata.Restore.OngoingRequest
+}
+
+// parseObjectRestoreStatus parses the value of x-bce-restore header,
+// e.g. ongoing-request="false", expiry-date="Wed, 07 Nov 2019 00:00:00 GMT".
+func parseObjectRestoreStatus(value string) *ObjectRestoreStatus {
+	status := &ObjectRestoreStatus{}
+
+	for value != "" {
+		index := strings.Index(value, "=")
+
+		if index == -1 {
+			break
+		}
+
+		name := strings.ToLower(strings.TrimSpace(value[:index]))
+		value = strings.TrimSpace(value[index+1:])
+
+		var field string
+
+		if strings.HasPrefix(value, "\"") {
+			end := strings.Index(value[1:], "\"")
+
+			if end == -1 {
+				field, value = value[1:], ""
+			} else {
+				field, value = value[1:end+1], value[end+2:]
+			}
+		} else if end := strings.Index(value, ","); end == -1 {
+			field, value = value, ""
+		} else {
+			field, value = value[:end], value[end:]
+		}
+
+		value = strings.TrimLeft(value, ", ")
+
+		if name == "ongoing-request" {
+			status.OngoingRequest = strings.ToLower(field) == "true"
+		} else if name == "expiry-date" {
+			status.ExpiryDate = field
+		}
+	}
+
+	return status
 }
 
 // NewObjectMetadataFromHeader generates a bos.ObjectMetadata instance from a http.Header instance.
@@ -132,6 +252,8 @@ func NewObjectMetadataFromHeader(h http.Header) *ObjectMetadata {
 				if err == nil {
 					objectMetadata.ContentLength = length
 				}
+			} else if lowerKey == "content-md5" {
+				objectMetadata.ContentMD5 = value
 			} else if lowerKey == "content-range" {
 				objectMetadata.ContentRange = value
 			} else if lowerKey == "content-type" {
@@ -147,13 +269,98 @@ func NewObjectMetadataFromHeader(h http.Header) *ObjectMetadata {
 				objectMetadata.UserMetadata[key] = h[key][0]
 			} else if lowerKey == "x-bce-storage-class" {
 				objectMetadata.StorageClass = value
+			} else if lowerKey == "x-bce-content-crc64ecma" {
+				objectMetadata.ContentCrc64 = value
+			} else if lowerKey == "x-bce-object-type" {
+				objectMetadata.ObjectType = value
+			} else if lowerKey == "x-bce-version-id" {
+				objectMetadata.VersionId = value
+			} else if lowerKey == "x-bce-restore" {
+				objectMetadata.Restore = parseObjectRestoreStatus(value)
 			}
 		}
 	}
 
+	objectMetadata.ServerSideEncryption = parseServerSideEncryption(h)
+
 	return objectMetadata
 }
 
+// ContentRange defined a struct for the parsed Content-Range header, all positions are inclusive.
+//
+// Start and End are -1 for an unsatisfied range, and Total is -1 if the total size is unknown.
+type ContentRange struct {
+	Start, End, Total int64
+}
+
+// GetContentRange parses the ContentRange field of bos.ObjectMetadata, such as "bytes 0-1023/4096".
+// It returns nil if the BOS Object is not got with a range.
+func (metadata *ObjectMetadata) GetContentRange() (*ContentRange, error) {
+	if metadata.ContentRange == "" {
+		return nil, nil
+	}
+
+	invalidError := fmt.Errorf("Invalid content range %s.", metadata.ContentRange)
+	value := strings.TrimSpace(metadata.ContentRange)
+
+	if !strings.HasPrefix(value, "bytes ") {
+		return nil, invalidError
+	}
+
+	parts := strings.Split(strings.TrimSpace(value[len("bytes "):]), "/")
+
+	if len(parts) != 2 {
+		return nil, invalidError
+	}
+
+	contentRange := &ContentRange{Start: -1, End: -1, Total: -1}
+
+	if parts[1] != "*" {
+		total, err := strconv.ParseInt(parts[1], 10, 64)
+
+		if err != nil {
+			return nil, invalidError
+		}
+
+		contentRange.Total = total
+	}
+
+	if parts[0] == "*" {
+		return contentRange, nil
+	}
+
+	positions := strings.Split(parts[0], "-")
+
+	if len(positions) != 2 {
+		return nil, invalidError
+	}
+
+	start, err := strconv.ParseInt(positions[0], 10, 64)
+
+	if err != nil {
+		return nil, invalidError
+	}
+
+	end, err := strconv.ParseInt(positions[1], 10, 64)
+
+	if err != nil || end < start {
+		return nil, invalidError
+	}
+
+	contentRange.Start, contentRange.End = start, end
+
+	return contentRange, nil
+}
+
+// Length returns the count of bytes in the range.
+func (contentRange *ContentRange) Length() int64 {
+	if contentRange.Start < 0 {
+		return 0
+	}
+
+	return contentRange.End - contentRange.Start + 1
+}
+
 // AddUserMetadata adds a custom metadata to bos.ObjectMetadata.
 func (metadata *ObjectMetadata) AddUserMetadata(key, value string) {
 	if metadata.UserMetadata == nil {
@@ -163,6 +370,29 @@ func (metadata *ObjectMetadata) AddUserMetadata(key, value string) {
 	metadata.UserMetadata[key] = value
 }
 
+// copyable returns a new bos.ObjectMetadata instance which only contains the fields
+// that can be specified when creating a BOS Object.
+func (metadata *ObjectMetadata) copyable() *ObjectMetadata {
+	copied := &ObjectMetadata{
+		CacheControl:       metadata.CacheControl,
+		ContentDisposition: metadata.ContentDisposition,
+		ContentType:        metadata.ContentType,
+		Expires:            metadata.Expires,
+		StorageClass:       metadata.StorageClass,
+	}
+
+	for key, value := range metadata.UserMetadata {
+		copied.AddUserMetadata(strings.ToLower(key), value)
+	}
+
+	// the key provided by customer is never returned, so only SSE-BOS can be kept
+	if sse := metadata.ServerSideEncryption; sse != nil && !sse.IsCustomerKey() {
+		copied.ServerSideEncryption = NewServerSideEncryption(sse.Algorithm)
+	}
+
+	return copied
+}
+
 func (metadata *ObjectMetadata) mergeToSignOption(option *bce.SignOption) {
 	if metadata.CacheControl != "" {
 		option.AddHeader("Cache-Control", metadata.CacheControl)
@@ -192,15 +422,19 @@ func (metadata *ObjectMetadata) mergeToSignOption(option *bce.SignOption) {
 		option.AddHeader("x-bce-content-sha256", metadata.ContentSha256)
 	}
 
-	if metadata.StorageClass == STORAGE_CLASS_STANDARD ||
-		metadata.StorageClass == STORAGE_CLASS_STANDARD_IA ||
-		metadata.StorageClass == STORAGE_CLASS_COLD {
+	if isStorageClass(metadata.StorageClass) {
 		option.AddHeader("x-bce-storage-class", metadata.StorageClass)
 	}
 
+	if metadata.Acl != "" {
+		option.AddHeader("x-bce-acl", metadata.Acl)
+	}
+
 	for key, value := range metadata.UserMetadata {
 		option.AddHeader(ToUserDefinedMetadata(key), value)
 	}
+
+	metadata.ServerSideEncryption.mergeToSignOption(option, false)
 }
 
 type PutObjectResponse http.Header
@@ -219,6 +453,11 @@ func (res PutObjectResponse) GetETag() string {
 	return strings.Replace(res.Get("Etag"), "\"", "", -1)
 }
 
+// GetVersionId gets the version id of the BOS Object, it is empty if versioning is not enabled for the BOS Bucket.
+func (res PutObjectResponse) GetVersionId() string {
+	return res.Get("x-bce-version-id")
+}
+
 type AppendObjectResponse http.Header
 
 func NewAppendObjectResponse(h http.Header) AppendObjectResponse {
@@ -251,6 +490,7 @@ type ObjectSummary struct {
 	LastModified string
 	ETag         string
 	Size         int64
+	StorageClass string
 	Owner        BucketOwner
 }
 
@@ -294,13 +534,20 @@ type CopyObjectResponse struct {
 type CopyObjectRequest struct {
 	SrcBucketName         string          `json:"-"`
 	SrcKey                string          `json:"-"`
+	SrcVersionId          string          `json:"-"`
 	DestBucketName        string          `json:"-"`
 	DestKey               string          `json:"-"`
 	ObjectMetadata        *ObjectMetadata `json:"-"`
+	Acl                   string          `json:"x-bce-acl,omitempty"`
 	SourceMatch           string          `json:"x-bce-copy-source-if-match,omitempty"`
 	SourceNoneMatch       string          `json:"x-bce-copy-source-if-none-match,omitempty"`
 	SourceModifiedSince   string          `json:"x-bce-copy-source-if-modified-since,omitempty"`
 	SourceUnmodifiedSince string          `json:"x-bce-copy-source-if-unmodified-since,omitempty"`
+
+	// ServerSideEncryption encrypts the destination BOS Object, it works with both metadata directives,
+	// and SourceServerSideEncryption is the key provided by customer to read the source BOS Object.
+	ServerSideEncryption       *ServerSideEncryption `json:"-"`
+	SourceServerSideEncryption *ServerSideEncryption `json:"-"`
 }
 
 func (copyObjectRequest CopyObjectRequest) mergeToSignOption(option *bce.SignOption) {
@@ -326,6 +573,9 @@ func (copyObjectRequest CopyObjectRequest) mergeToSignOption(option *bce.SignOpt
 	} else {
 		option.AddHeader("x-bce-metadata-directive", "copy")
 	}
+
+	copyObjectRequest.ServerSideEncryption.mergeToSignOption(option, false)
+	copyObjectRequest.SourceServerSideEncryption.mergeToSignOption(option, true)
 }
 
 // Object defined a struct for BOS Object.
@@ -339,6 +589,103 @@ type GetObjectRequest struct {
 	BucketName string
 	ObjectKey  string
 	Range      string
+	VersionId  string
+	ObjectConditions
+
+	// Response* fields override the headers of response, such as making a browser download the BOS Object.
+	ResponseCacheControl       string
+	ResponseContentDisposition string
+	ResponseContentEncoding    string
+	ResponseContentLanguage    string
+	ResponseContentType        string
+	ResponseExpires            string
+
+	// ServerSideEncryption is the key provided by customer to encrypt the BOS Object, if any.
+	ServerSideEncryption *ServerSideEncryption
+}
+
+func (getObjectRequest *GetObjectRequest) params() map[string]string {
+	params := versionIdParams(getObjectRequest.VersionId)
+
+	overrides := map[string]string{
+		"responseCacheControl":       getObjectRequest.ResponseCacheControl,
+		"responseContentDisposition": getObjectRequest.ResponseContentDisposition,
+		"responseContentEncoding":    getObjectRequest.ResponseContentEncoding,
+		"responseContentLanguage":    getObjectRequest.ResponseContentLanguage,
+		"responseContentType":        getObjectRequest.ResponseContentType,
+		"responseExpires":            getObjectRequest.ResponseExpires,
+	}
+
+	for key, value := range overrides {
+		if value != "" {
+			if params == nil {
+				params = make(map[string]string)
+			}
+
+			params[key] = value
+		}
+	}
+
+	return params
+}
+
+// GetObjectMetadataRequest contains all options for bos.GetObjectMetadataFromRequest method.
+type GetObjectMetadataRequest struct {
+	BucketName string
+	ObjectKey  string
+	VersionId  string
+	ObjectConditions
+
+	// ServerSideEncryption is the key provided by customer to encrypt the BOS Object, if any.
+	ServerSideEncryption *ServerSideEncryption
+}
+
+// UpdateObjectMetadataRequest contains all options for bos.UpdateObjectMetadataFromRequest method.
+type UpdateObjectMetadataRequest struct {
+	BucketName string
+	ObjectKey  string
+
+	// Update updates the current metadata, see bos.UpdateObjectMetadata for details.
+	Update func(*ObjectMetadata)
+
+	// ServerSideEncryption is the key provided by customer to encrypt the BOS Object, if any.
+	// The BOS Object is read and encrypted again with the same key.
+	ServerSideEncryption *ServerSideEncryption
+}
+
+// PutObjectRequest contains all options for bos.PutObjectFromRequest method.
+type PutObjectRequest struct {
+	BucketName     string
+	ObjectKey      string
+	Data           interface{}
+	ObjectMetadata *ObjectMetadata
+	ObjectConditions
+}
+
+// DeleteObjectRequest contains all options for bos.DeleteObjectFromRequest method.
+type DeleteObjectRequest struct {
+	BucketName string
+	ObjectKey  string
+	VersionId  string
+}
+
+func versionIdParams(versionId string) map[string]string {
+	if versionId == "" {
+		return nil
+	}
+
+	return map[string]string{"versionId": versionId}
+}
+
+// copySource returns the value of x-bce-copy-source header.
+func copySource(bucketName, objectKey, versionId string) string {
+	source := util.URIEncodeExceptSlash(fmt.Sprintf("/%s/%s", bucketName, objectKey))
+
+	if versionId != "" {
+		source += "?versionId=" + util.URLEncode(versionId)
+	}
+
+	return source
 }
 
 // MergeToSignOption merges bos.GetObjectRequest fields to bce.SignOption.
@@ -346,6 +693,9 @@ func (getObjectRequest *GetObjectRequest) MergeToSignOption(option *bce.SignOpti
 	if getObjectRequest.Range != "" {
 		option.AddHeader("Range", "bytes="+getObjectRequest.Range)
 	}
+
+	getObjectRequest.ObjectConditions.mergeToSignOption(option)
+	getObjectRequest.ServerSideEncryption.mergeToSignOption(option, false)
 }
 
 // SetRange sets the range field of bos.GetObjectRequest.
@@ -353,6 +703,16 @@ func (getObjectRequest *GetObjectRequest) SetRange(start uint, end uint) {
 	getObjectRequest.Range = fmt.Sprintf("%v-%v", start, end)
 }
 
+// SetRangeFrom sets the range field of bos.GetObjectRequest to get the BOS Object from start to the end.
+func (getObjectRequest *GetObjectRequest) SetRangeFrom(start int64) {
+	getObjectRequest.Range = fmt.Sprintf("%d-", start)
+}
+
+// SetSuffixRange sets the range field of bos.GetObjectRequest to get the last length bytes of the BOS Object.
+func (getObjectRequest *GetObjectRequest) SetSuffixRange(length int64) {
+	getObjectRequest.Range = fmt.Sprintf("-%d", length)
+}
+
 // DeleteMultipleObjectsError defined a struct for bos.DeleteMultipleObjects method's response.
 type DeleteMultipleObjectsResponse struct {
 	Errors []DeleteMultipleObjectsError
@@ -372,6 +732,52 @@ func (deleteMultipleObjectsError *DeleteMultipleObjectsError) Error() string {
 	return deleteMultipleObjectsError.Code
 }
 
+// DeletePrefixOptions contains all options for bos.DeletePrefix method.
+type DeletePrefixOptions struct {
+	// BatchSize is the count of keys in each bos.DeleteMultipleObjects request,
+	// it is capped by MAX_DELETE_MULTIPLE_OBJECTS.
+	BatchSize int
+
+	// Parallel is the count of concurrent bos.DeleteMultipleObjects requests.
+	Parallel int
+
+	// DryRun only lists the keys which would be deleted.
+	DryRun bool
+
+	// AbortMultipartUploads aborts all in-progress multipart uploads under the prefix as well.
+	AbortMultipartUploads bool
+
+	// AllowEmptyPrefix allows the empty prefix, which deletes all BOS Objects of the BOS Bucket,
+	// bos.DeletePrefix returns bce.ValidationError for the empty prefix without it.
+	AllowEmptyPrefix bool
+}
+
+// DeletePrefixResult defined a struct for bos.DeletePrefix method's response.
+type DeletePrefixResult struct {
+	// Deleted is the count of deleted keys, or keys would be deleted in dry-run mode.
+	Deleted int
+
+	// Keys contains the keys would be deleted, only available in dry-run mode.
+	Keys []string
+
+	// AbortedUploads is the count of aborted multipart uploads.
+	AbortedUploads int
+
+	// Errors contains the failed keys of all batches. If a whole batch failed,
+	// each key of it is reported with the message of the request error.
+	Errors []DeleteMultipleObjectsError
+}
+
+// Err returns an error which describes the failed keys, or nil if all keys are deleted.
+func (deletePrefixResult *DeletePrefixResult) Err() error {
+	if len(deletePrefixResult.Errors) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d objects failed to delete, the first is %s: %s", len(deletePrefixResult.Errors),
+		deletePrefixResult.Errors[0].Key, deletePrefixResult.Errors[0].Error())
+}
+
 // InitiateMultipartUploadRequest contains all options for bos.InitiateMultipartUpload method.
 type InitiateMultipartUploadRequest struct {
 	BucketName, ObjectKey string
@@ -389,6 +795,9 @@ type UploadPartRequest struct {
 	PartSize                        int64
 	PartNumber                      int
 	PartData                        io.Reader
+
+	// ServerSideEncryption is the key provided by customer to initiate the multipart upload, if any.
+	ServerSideEncryption *ServerSideEncryption
 }
 
 type UploadPartResponse http.Header
@@ -407,6 +816,63 @@ func (res UploadPartResponse) GetETag() string {
 	return strings.Replace(res.Get("Etag"), "\"", "", -1)
 }
 
+// UploadPartCopyRequest contains all options for bos.UploadPartCopy method.
+type UploadPartCopyRequest struct {
+	SrcBucketName         string `json:"-"`
+	SrcKey                string `json:"-"`
+	SrcVersionId          string `json:"-"`
+	DestBucketName        string `json:"-"`
+	DestKey               string `json:"-"`
+	UploadId              string `json:"-"`
+	PartNumber            int    `json:"-"`
+	SourceRange           string `json:"-"`
+	SourceMatch           string `json:"x-bce-copy-source-if-match,omitempty"`
+	SourceNoneMatch       string `json:"x-bce-copy-source-if-none-match,omitempty"`
+	SourceModifiedSince   string `json:"x-bce-copy-source-if-modified-since,omitempty"`
+	SourceUnmodifiedSince string `json:"x-bce-copy-source-if-unmodified-since,omitempty"`
+
+	// ServerSideEncryption is the key provided by customer to initiate the multipart upload,
+	// and SourceServerSideEncryption is the key provided by customer to read the source BOS Object.
+	ServerSideEncryption       *ServerSideEncryption `json:"-"`
+	SourceServerSideEncryption *ServerSideEncryption `json:"-"`
+}
+
+// SetSourceRange sets the source range field of bos.UploadPartCopyRequest, both start and end are inclusive.
+func (uploadPartCopyRequest *UploadPartCopyRequest) SetSourceRange(start, end int64) {
+	uploadPartCopyRequest.SourceRange = fmt.Sprintf("%v-%v", start, end)
+}
+
+func (uploadPartCopyRequest UploadPartCopyRequest) mergeToSignOption(option *bce.SignOption) {
+	m, err := util.ToMap(uploadPartCopyRequest)
+
+	if err != nil {
+		return
+	}
+
+	headerMap := make(map[string]string)
+
+	for key, value := range m {
+		if str, ok := value.(string); ok {
+			headerMap[key] = str
+		}
+	}
+
+	option.AddHeaders(headerMap)
+
+	if uploadPartCopyRequest.SourceRange != "" {
+		option.AddHeader("x-bce-copy-source-range", "bytes="+uploadPartCopyRequest.SourceRange)
+	}
+
+	uploadPartCopyRequest.ServerSideEncryption.mergeToSignOption(option, false)
+	uploadPartCopyRequest.SourceServerSideEncryption.mergeToSignOption(option, true)
+}
+
+// UploadPartCopyResponse defined a struct for bos.UploadPartCopy method's response.
+type UploadPartCopyResponse struct {
+	ETag         string
+	LastModified time.Time
+}
+
 // PartSummarySlice defined a slice for bos.PartSummary.
 //
 // PartSummarySlice implements the interface of sort.Interface,
@@ -443,6 +909,9 @@ func (completeMultipartUploadRequest *CompleteMultipartUploadRequest) sort() {
 // CompleteMultipartUploadResponse defined a struct for bos.CompleteMultipartUpload method's response.
 type CompleteMultipartUploadResponse struct {
 	Location, Bucket, Key, ETag string
+
+	// ContentCrc64 is the CRC64 (ECMA) of the whole BOS Object returned in response header.
+	ContentCrc64 string `json:"-"`
 }
 
 // AbortMultipartUploadRequest contains all options for bos.AbortMultipartUpload method.
@@ -454,6 +923,9 @@ type AbortMultipartUploadRequest struct {
 type ListMultipartUploadsRequest struct {
 	BucketName, Delimiter, KeyMarker, Prefix string
 	MaxUploads                               int
+
+	// UploadIdMarker is used with KeyMarker to continue listing from the middle of the uploads of a key.
+	UploadIdMarker string
 }
 
 // MultipartUploadSummary defined a struct for summary of each multipart upload item.
@@ -466,15 +938,17 @@ type MultipartUploadSummary struct {
 
 // ListMultipartUploadsResponse defined a struct for bos.ListMultipartUploads method's response.
 type ListMultipartUploadsResponse struct {
-	Bucket         string
-	Prefix         string
-	Delimiter      string
-	KeyMarker      string
-	NextKeyMarker  string
-	MaxUploads     int
-	IsTruncated    bool
-	Uploads        []MultipartUploadSummary
-	CommonPrefixes []map[string]string
+	Bucket             string
+	Prefix             string
+	Delimiter          string
+	KeyMarker          string
+	NextKeyMarker      string
+	UploadIdMarker     string
+	NextUploadIdMarker string
+	MaxUploads         int
+	IsTruncated        bool
+	Uploads            []MultipartUploadSummary
+	CommonPrefixes     []map[string]string
 }
 
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#ListMultipartUploads.E6.8E.A5.E5.8F.A3
@@ -543,6 +1017,125 @@ type BucketLogging struct {
 	TargetPrefix string `json:"targetPrefix"`
 }
 
+// BucketEncryption defined a struct for default server side encryption configuration of BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketEncryption
+type BucketEncryption struct {
+	EncryptionAlgorithm string `json:"encryptionAlgorithm"`
+}
+
+// BucketReferer defined a struct for referer (anti-leech) configuration of BOS Bucket.
+//
+// If WhiteList is not empty, only the requests with matched referer are allowed,
+// the requests with referer matched BlackList are always denied.
+// Wildcard '*' and '?' can be used in referer patterns.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketReferer
+type BucketReferer struct {
+	AllowEmptyReferer bool     `json:"allowEmptyReferer"`
+	WhiteList         []string `json:"whiteList,omitempty"`
+	BlackList         []string `json:"blackList,omitempty"`
+}
+
+// BucketStaticWebsite defined a struct for static website hosting configuration of BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketStaticWebsite
+type BucketStaticWebsite struct {
+	Index    string `json:"index"`
+	NotFound string `json:"notFound,omitempty"`
+}
+
+// BucketReplication defined a struct for cross region replication configuration of BOS Bucket.
+//
+// Resource contains the source prefixes, each one is in the form of "bucketName/prefix*".
+// ReplicateHistory replicates the existing objects as well if specified, its bucket
+// must be the same as the destination bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketReplication
+type BucketReplication struct {
+	Id               string                        `json:"id"`
+	Status           string                        `json:"status"`
+	Resource         []string                      `json:"resource"`
+	Destination      BucketReplicationDestination  `json:"destination"`
+	ReplicateHistory *BucketReplicationDestination `json:"replicateHistory,omitempty"`
+	ReplicateDeletes string                        `json:"replicateDeletes"`
+}
+
+// BucketReplicationDestination defined a struct for destination of cross region replication.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketReplication
+type BucketReplicationDestination struct {
+	Bucket       string `json:"bucket"`
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+// Validate checks the replication configuration of source BOS Bucket before sending request.
+func (replication *BucketReplication) Validate(bucketName string) error {
+	if replication.Id == "" {
+		return bce.NewValidationError("id", "The replication id should not be empty.")
+	}
+
+	if replication.Status != STATUS_ENABLED && replication.Status != STATUS_DISABLED {
+		return bce.NewValidationError("status", fmt.Sprintf("The replication status %s is not supported.",
+			replication.Status))
+	}
+
+	if replication.ReplicateDeletes != STATUS_ENABLED && replication.ReplicateDeletes != STATUS_DISABLED {
+		return bce.NewValidationError("replicateDeletes", fmt.Sprintf("The status %s is not supported.",
+			replication.ReplicateDeletes))
+	}
+
+	if len(replication.Resource) == 0 {
+		return bce.NewValidationError("resource", "The replication resource should not be empty.")
+	}
+
+	for _, resource := range replication.Resource {
+		if !strings.HasPrefix(resource, bucketName+"/") {
+			return bce.NewValidationError("resource", fmt.Sprintf("The replication resource %s should start with %s/.",
+				resource, bucketName))
+		}
+	}
+
+	destination := replication.Destination
+
+	if destination.Bucket == "" {
+		return bce.NewValidationError("destination", "The replication destination bucket should not be empty.")
+	}
+
+	if destination.Bucket == bucketName {
+		return bce.NewValidationError("destination",
+			"The replication destination bucket should not be the source bucket.")
+	}
+
+	if destination.StorageClass != "" && !isStorageClass(destination.StorageClass) {
+		return bce.NewValidationError("destination", fmt.Sprintf("The storage class %s is not supported.",
+			destination.StorageClass))
+	}
+
+	if history := replication.ReplicateHistory; history != nil {
+		if history.Bucket != destination.Bucket {
+			return bce.NewValidationError("replicateHistory", fmt.Sprintf(
+				"The bucket %s should be the same as destination bucket %s.", history.Bucket, destination.Bucket))
+		}
+
+		if history.StorageClass != "" && !isStorageClass(history.StorageClass) {
+			return bce.NewValidationError("replicateHistory", fmt.Sprintf("The storage class %s is not supported.",
+				history.StorageClass))
+		}
+	}
+
+	return nil
+}
+
+// BucketReplicationProgress defined a struct for bos.GetBucketReplicationProgress method's response.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketReplicationProgress
+type BucketReplicationProgress struct {
+	Status                    string  `json:"status"`
+	HistoryReplicationPercent float64 `json:"historyReplicationPercent"`
+	LatestReplicationTime     string  `json:"latestReplicationTime"`
+}
+
 type BucketLifecycle struct {
 	Rule []BucketLifecycleItem `json:"rule"`
 }
@@ -574,6 +1167,149 @@ type BucketLifecycleItemAction struct {
 	StorageClass string `json:"storageClass,omitempty"`
 }
 
+// BucketVersioning defined a struct for versioning configuration of BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketVersioning
+type BucketVersioning struct {
+	Status string `json:"status"`
+}
+
+// ListObjectVersionsRequest contains all options for bos.ListObjectVersionsFromRequest method.
+type ListObjectVersionsRequest struct {
+	BucketName, Delimiter, Prefix, KeyMarker, VersionIdMarker string
+	MaxKeys                                                   int
+}
+
+// ObjectVersionSummary defined a struct for a version of BOS Object, or a delete marker.
+type ObjectVersionSummary struct {
+	Key            string
+	VersionId      string
+	IsLatest       bool
+	IsDeleteMarker bool
+	LastModified   string
+	ETag           string
+	Size           int64
+	StorageClass   string
+	Owner          BucketOwner
+}
+
+// ListObjectVersionsResponse defined a struct for bos.ListObjectVersions method's response.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#ListObjectVersions
+type ListObjectVersionsResponse struct {
+	Name                string
+	Prefix              string
+	Delimiter           string
+	KeyMarker           string
+	VersionIdMarker     string
+	NextKeyMarker       string
+	NextVersionIdMarker string
+	MaxKeys             uint
+	IsTruncated         bool
+	Versions            []ObjectVersionSummary
+	CommonPrefixes      []map[string]string
+}
+
+// MAX_OBJECT_TAG_COUNT is the max count of tags of a BOS Object.
+const MAX_OBJECT_TAG_COUNT int = 10
+
+// MAX_OBJECT_TAG_KEY_LENGTH and MAX_OBJECT_TAG_VALUE_LENGTH are the max length of tag key and tag value.
+const MAX_OBJECT_TAG_KEY_LENGTH int = 128
+const MAX_OBJECT_TAG_VALUE_LENGTH int = 256
+
+// ObjectTagging defined a struct for the tags of BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutObjectTagging
+type ObjectTagging struct {
+	TagSet []ObjectTagSet `json:"tagSet"`
+}
+
+type ObjectTagSet struct {
+	TagInfo []ObjectTag `json:"tagInfo"`
+}
+
+type ObjectTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewObjectTagging generates a bos.ObjectTagging instance from a map of tags.
+func NewObjectTagging(tags map[string]string) ObjectTagging {
+	tagSet := ObjectTagSet{TagInfo: make([]ObjectTag, 0, len(tags))}
+
+	for key, value := range tags {
+		tagSet.TagInfo = append(tagSet.TagInfo, ObjectTag{Key: key, Value: value})
+	}
+
+	return ObjectTagging{TagSet: []ObjectTagSet{tagSet}}
+}
+
+// Tags returns all tags of bos.ObjectTagging as a map.
+func (tagging *ObjectTagging) Tags() map[string]string {
+	tags := make(map[string]string)
+
+	for _, tagSet := range tagging.TagSet {
+		for _, tag := range tagSet.TagInfo {
+			tags[tag.Key] = tag.Value
+		}
+	}
+
+	return tags
+}
+
+func (tagging *ObjectTagging) validate() error {
+	keys := make(map[string]bool)
+
+	for _, tagSet := range tagging.TagSet {
+		for _, tag := range tagSet.TagInfo {
+			if tag.Key == "" || len(tag.Key) > MAX_OBJECT_TAG_KEY_LENGTH {
+				return bce.NewValidationError("tagKey", fmt.Sprintf("The length of tag key %s should be from 1 to %d.",
+					tag.Key, MAX_OBJECT_TAG_KEY_LENGTH))
+			}
+
+			if len(tag.Value) > MAX_OBJECT_TAG_VALUE_LENGTH {
+				return bce.NewValidationError("tagValue", fmt.Sprintf(
+					"The length of value of tag %s should not be more than %d.", tag.Key, MAX_OBJECT_TAG_VALUE_LENGTH))
+			}
+
+			if keys[tag.Key] {
+				return bce.NewValidationError("tagKey", fmt.Sprintf("The tag key %s is duplicate.", tag.Key))
+			}
+
+			keys[tag.Key] = true
+		}
+	}
+
+	if len(keys) > MAX_OBJECT_TAG_COUNT {
+		return bce.NewValidationError("tagging", fmt.Sprintf("The count of tags %d should not be more than %d.",
+			len(keys), MAX_OBJECT_TAG_COUNT))
+	}
+
+	return nil
+}
+
+func isStorageClass(storageClass string) bool {
+	return storageClass == STORAGE_CLASS_STANDARD ||
+		storageClass == STORAGE_CLASS_STANDARD_IA ||
+		storageClass == STORAGE_CLASS_COLD ||
+		storageClass == STORAGE_CLASS_ARCHIVE
+}
+
+// storageClassRank returns the order of storage class from warm to cold,
+// an empty storage class is regarded as STORAGE_CLASS_STANDARD.
+func storageClassRank(storageClass string) int {
+	switch storageClass {
+	case STORAGE_CLASS_STANDARD_IA:
+		return 1
+	case STORAGE_CLASS_COLD:
+		return 2
+	case STORAGE_CLASS_ARCHIVE:
+		return 3
+	}
+
+	return 0
+}
+
 // IsUserDefinedMetadata checks the specified metadata if it is custom metadata.
 func IsUserDefinedMetadata(metadata string) bool {
 	return strings.Index(metadata, UserDefinedMetadataPrefix) == 0
@@ -1,6 +1,7 @@
 package bos
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"testing"
@@ -55,6 +56,37 @@ func TestNewObjectMetadataFromHeader(t *testing.T) {
 	}
 }
 
+func TestParseObjectRestoreStatus(t *testing.T) {
+	header := http.Header{
+		"X-Bce-Storage-Class": []string{STORAGE_CLASS_ARCHIVE},
+		"X-Bce-Restore":       []string{`ongoing-request="false", expiry-date="Wed, 07 Nov 2019 00:00:00 GMT"`},
+	}
+	metadata := NewObjectMetadataFromHeader(header)
+
+	if metadata.StorageClass != STORAGE_CLASS_ARCHIVE {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", metadata.StorageClass, STORAGE_CLASS_ARCHIVE))
+	}
+
+	if !metadata.IsRestored() {
+		t.Error(util.FormatTest("ObjectMetadata: IsRestored", "false", "true"))
+	}
+
+	if metadata.Restore.ExpiryDate != "Wed, 07 Nov 2019 00:00:00 GMT" {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", metadata.Restore.ExpiryDate,
+			"Wed, 07 Nov 2019 00:00:00 GMT"))
+	}
+
+	status := parseObjectRestoreStatus(`ongoing-request="true"`)
+
+	if !status.OngoingRequest || status.ExpiryDate != "" {
+		t.Error(util.FormatTest("parseObjectRestoreStatus", strconv.FormatBool(status.OngoingRequest), "true"))
+	}
+
+	if metadata := (&ObjectMetadata{StorageClass: STORAGE_CLASS_ARCHIVE}); metadata.IsRestored() {
+		t.Error(util.FormatTest("ObjectMetadata: IsRestored", "true", "false"))
+	}
+}
+
 func TestAddUserMetadata(t *testing.T) {
 	metadata := &ObjectMetadata{}
 	metadata.AddUserMetadata("x-bce-meta-name", "hello")
@@ -79,6 +111,7 @@ func TestMergeToSignOption(t *testing.T) {
 	metadata := NewObjectMetadataFromHeader(header)
 	metadata.ContentMD5 = "md5-value"
 	metadata.ContentSha256 = "sha256-value"
+	metadata.Acl = CannedAccessControlList["PublicRead"]
 	metadata.AddUserMetadata("server", "nginx")
 	metadata.mergeToSignOption(option)
 
@@ -105,6 +138,10 @@ func TestMergeToSignOption(t *testing.T) {
 	if option.Headers["x-bce-content-sha256"] != "sha256-value" {
 		t.Error(util.FormatTest("mergeToSignOption", option.Headers["x-bce-content-sha256"], "sha256-value"))
 	}
+
+	if option.Headers["x-bce-acl"] != "public-read" {
+		t.Error(util.FormatTest("mergeToSignOption", option.Headers["x-bce-acl"], "public-read"))
+	}
 }
 
 func TestGetETag(t *testing.T) {
@@ -166,6 +203,61 @@ func TestMergeToSignOptionForCopyObjectRequest(t *testing.T) {
 	}
 }
 
+func TestMergeToSignOptionForUploadPartCopyRequest(t *testing.T) {
+	option := &bce.SignOption{}
+	request := UploadPartCopyRequest{
+		SrcBucketName:  "source-bucket",
+		SrcKey:         "source-bucket-key",
+		DestBucketName: "dest-bucket",
+		DestKey:        "dest-bucket-key",
+		UploadId:       "upload-id",
+		PartNumber:     1,
+		SourceMatch:    "xxx",
+	}
+	request.SetSourceRange(0, 1023)
+
+	request.mergeToSignOption(option)
+
+	if len(option.Headers) != 2 {
+		t.Error(util.FormatTest("UploadPartCopyRequest: MergeToSignOption", strconv.Itoa(len(option.Headers)), strconv.Itoa(2)))
+	}
+
+	if option.Headers["x-bce-copy-source-range"] != "bytes=0-1023" {
+		t.Error(util.FormatTest("UploadPartCopyRequest: MergeToSignOption", option.Headers["x-bce-copy-source-range"], "bytes=0-1023"))
+	}
+
+	if option.Headers["x-bce-copy-source-if-match"] != "xxx" {
+		t.Error(util.FormatTest("UploadPartCopyRequest: MergeToSignOption", option.Headers["x-bce-copy-source-if-match"], "xxx"))
+	}
+}
+
+func TestCopyableObjectMetadata(t *testing.T) {
+	header := http.Header{
+		"Content-Length":      []string{"1024"},
+		"Content-Type":        []string{"text/plain"},
+		"Etag":                []string{"abc123"},
+		"X-Bce-Meta-Name":     []string{"hello"},
+		"X-Bce-Storage-Class": []string{STORAGE_CLASS_STANDARD_IA},
+	}
+	metadata := NewObjectMetadataFromHeader(header).copyable()
+
+	if metadata.ContentLength != 0 || metadata.ETag != "" {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", metadata.ETag, "empty ETag and ContentLength"))
+	}
+
+	if metadata.ContentType != "text/plain" {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", metadata.ContentType, "text/plain"))
+	}
+
+	if metadata.StorageClass != STORAGE_CLASS_STANDARD_IA {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", metadata.StorageClass, STORAGE_CLASS_STANDARD_IA))
+	}
+
+	if metadata.UserMetadata["x-bce-meta-name"] != "hello" {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", metadata.UserMetadata["x-bce-meta-name"], "hello"))
+	}
+}
+
 func TestMergeToSignOptionForGetObjectRequest(t *testing.T) {
 	lengthRange := "0-1024"
 	expected := "bytes=" + lengthRange
@@ -184,6 +276,87 @@ func TestMergeToSignOptionForGetObjectRequest(t *testing.T) {
 	}
 }
 
+func TestSetRangeForGetObjectRequest(t *testing.T) {
+	request := &GetObjectRequest{}
+
+	request.SetRange(0, 1023)
+
+	if request.Range != "0-1023" {
+		t.Error(util.FormatTest("GetObjectRequest: SetRange", request.Range, "0-1023"))
+	}
+
+	request.SetRangeFrom(100)
+
+	if request.Range != "100-" {
+		t.Error(util.FormatTest("GetObjectRequest: SetRangeFrom", request.Range, "100-"))
+	}
+
+	request.SetSuffixRange(500)
+
+	if request.Range != "-500" {
+		t.Error(util.FormatTest("GetObjectRequest: SetSuffixRange", request.Range, "-500"))
+	}
+}
+
+func TestParamsForGetObjectRequest(t *testing.T) {
+	request := &GetObjectRequest{}
+
+	if params := request.params(); params != nil {
+		t.Error(util.FormatTest("GetObjectRequest: params", strconv.Itoa(len(params)), "0"))
+	}
+
+	request.VersionId = "AAAA"
+	request.ResponseContentType = "application/octet-stream"
+	request.ResponseContentDisposition = "attachment"
+	params := request.params()
+
+	if len(params) != 3 || params["responseContentType"] != "application/octet-stream" ||
+		params["responseContentDisposition"] != "attachment" || params["versionId"] != "AAAA" {
+
+		t.Error(util.FormatTest("GetObjectRequest: params", fmt.Sprintf("%v", params), "3 params"))
+	}
+}
+
+func TestGetContentRange(t *testing.T) {
+	metadata := &ObjectMetadata{ContentRange: "bytes 100-1023/4096"}
+	contentRange, err := metadata.GetContentRange()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentRange.Start != 100 || contentRange.End != 1023 || contentRange.Total != 4096 {
+		t.Error(util.FormatTest("ObjectMetadata: GetContentRange", fmt.Sprintf("%v", contentRange),
+			"&{100 1023 4096}"))
+	}
+
+	if contentRange.Length() != 924 {
+		t.Error(util.FormatTest("ContentRange: Length", strconv.FormatInt(contentRange.Length(), 10), "924"))
+	}
+
+	metadata.ContentRange = "bytes */4096"
+	contentRange, err = metadata.GetContentRange()
+
+	if err != nil || contentRange.Start != -1 || contentRange.Total != 4096 || contentRange.Length() != 0 {
+		t.Error(util.FormatTest("ObjectMetadata: GetContentRange", fmt.Sprintf("%v", contentRange),
+			"&{-1 -1 4096}"))
+	}
+
+	metadata.ContentRange = ""
+
+	if contentRange, err := metadata.GetContentRange(); contentRange != nil || err != nil {
+		t.Error(util.FormatTest("ObjectMetadata: GetContentRange", fmt.Sprintf("%v", contentRange), "nil"))
+	}
+
+	for _, invalid := range []string{"bytes=0-1024", "bytes 10-1/100", "bytes 0-a/100", "bytes 0-1"} {
+		metadata.ContentRange = invalid
+
+		if _, err := metadata.GetContentRange(); err == nil {
+			t.Error(util.FormatTest("ObjectMetadata: GetContentRange "+invalid, "nil", "error"))
+		}
+	}
+}
+
 func TestErrorForDeleteMultipleObjectsError(t *testing.T) {
 	deleteMultipleObjectsError := DeleteMultipleObjectsError{
 		Key:     "error-key",
@@ -211,6 +384,26 @@ func TestErrorForDeleteMultipleObjectsError(t *testing.T) {
 	}
 }
 
+func TestErrForDeletePrefixResult(t *testing.T) {
+	result := &DeletePrefixResult{Deleted: 2}
+
+	if err := result.Err(); err != nil {
+		t.Error(util.FormatTest("DeletePrefixResult: Err", err.Error(), "nil"))
+	}
+
+	result.Errors = []DeleteMultipleObjectsError{
+		DeleteMultipleObjectsError{Key: "key-0", Code: "AccessDenied", Message: "Access denied."},
+		DeleteMultipleObjectsError{Key: "key-1", Code: "AccessDenied"},
+	}
+	expected := "2 objects failed to delete, the first is key-0: Access denied."
+
+	if err := result.Err(); err == nil {
+		t.Error(util.FormatTest("DeletePrefixResult: Err", "nil", expected))
+	} else if err.Error() != expected {
+		t.Error(util.FormatTest("DeletePrefixResult: Err", err.Error(), expected))
+	}
+}
+
 func TestSort(t *testing.T) {
 	request := CompleteMultipartUploadRequest{
 		BucketName: "test-bucket",
@@ -240,6 +433,43 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestValidateBucketReplication(t *testing.T) {
+	replication := BucketReplication{
+		Id:               "replication-0",
+		Status:           STATUS_ENABLED,
+		Resource:         []string{"bucket-gz/videos/*"},
+		Destination:      BucketReplicationDestination{Bucket: "bucket-bj", StorageClass: STORAGE_CLASS_COLD},
+		ReplicateHistory: &BucketReplicationDestination{Bucket: "bucket-bj"},
+		ReplicateDeletes: STATUS_DISABLED,
+	}
+
+	if err := replication.Validate("bucket-gz"); err != nil {
+		t.Error(util.FormatTest("BucketReplication: Validate", err.Error(), "nil"))
+	}
+
+	invalidReplications := []func(BucketReplication) BucketReplication{
+		func(r BucketReplication) BucketReplication { r.Id = ""; return r },
+		func(r BucketReplication) BucketReplication { r.Status = "on"; return r },
+		func(r BucketReplication) BucketReplication { r.ReplicateDeletes = ""; return r },
+		func(r BucketReplication) BucketReplication { r.Resource = nil; return r },
+		func(r BucketReplication) BucketReplication { r.Resource = []string{"bucket-bj/*"}; return r },
+		func(r BucketReplication) BucketReplication { r.Destination.Bucket = "bucket-gz"; return r },
+		func(r BucketReplication) BucketReplication { r.Destination.StorageClass = "HOT"; return r },
+		func(r BucketReplication) BucketReplication {
+			r.ReplicateHistory = &BucketReplicationDestination{Bucket: "bucket-hk"}
+			return r
+		},
+	}
+
+	for index, f := range invalidReplications {
+		invalidReplication := f(replication)
+
+		if err := invalidReplication.Validate("bucket-gz"); err == nil {
+			t.Error(util.FormatTest("BucketReplication: Validate "+strconv.Itoa(index), "nil", "error"))
+		}
+	}
+}
+
 func TestIsUserDefinedMetadata(t *testing.T) {
 	expected := true
 	result := IsUserDefinedMetadata("x-bce-meta-name")
@@ -277,3 +507,65 @@ func TestToUserDefinedMetadata(t *testing.T) {
 		t.Error(util.FormatTest("ToUserDefinedMetadata", result, expected))
 	}
 }
+
+func TestValidateObjectTagging(t *testing.T) {
+	tagging := NewObjectTagging(map[string]string{"project": "sdk", "env": "test"})
+
+	if tags := tagging.Tags(); len(tags) != 2 || tags["project"] != "sdk" {
+		t.Error(util.FormatTest("ObjectTagging: Tags", tags["project"], "sdk"))
+	}
+
+	if err := tagging.validate(); err != nil {
+		t.Error(util.FormatTest("ObjectTagging: validate", err.Error(), "nil"))
+	}
+
+	tooMany := make(map[string]string)
+
+	for i := 0; i <= MAX_OBJECT_TAG_COUNT; i++ {
+		tooMany["key"+strconv.Itoa(i)] = "value"
+	}
+
+	invalids := []ObjectTagging{
+		NewObjectTagging(map[string]string{"": "empty"}),
+		NewObjectTagging(tooMany),
+		ObjectTagging{TagSet: []ObjectTagSet{
+			{TagInfo: []ObjectTag{{Key: "a", Value: "1"}, {Key: "a", Value: "2"}}},
+		}},
+	}
+
+	for _, tagging := range invalids {
+		if err := tagging.validate(); err == nil {
+			t.Error(util.FormatTest("ObjectTagging: validate", "nil", "error"))
+		}
+	}
+}
+
+func TestCopySource(t *testing.T) {
+	expected := "/bucket-0/dir/a%20b.txt"
+
+	if source := copySource("bucket-0", "dir/a b.txt", ""); source != expected {
+		t.Error(util.FormatTest("copySource", source, expected))
+	}
+
+	expected = "/bucket-0/a.txt?versionId=AAAA%2Bv%3D"
+
+	if source := copySource("bucket-0", "a.txt", "AAAA+v="); source != expected {
+		t.Error(util.FormatTest("copySource", source, expected))
+	}
+}
+
+func TestObjectVersionId(t *testing.T) {
+	header := http.Header{"X-Bce-Version-Id": []string{"AAAA"}}
+
+	if metadata := NewObjectMetadataFromHeader(header); metadata.VersionId != "AAAA" {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", metadata.VersionId, "AAAA"))
+	}
+
+	if versionId := NewPutObjectResponse(header).GetVersionId(); versionId != "AAAA" {
+		t.Error(util.FormatTest("PutObjectResponse: GetVersionId", versionId, "AAAA"))
+	}
+
+	if params := versionIdParams(""); params != nil {
+		t.Error(util.FormatTest("versionIdParams", strconv.Itoa(len(params)), "0"))
+	}
+}
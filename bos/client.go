@@ -12,6 +12,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/guoyao/baidubce-sdk-go/bce"
 	"github.com/guoyao/baidubce-sdk-go/util"
@@ -24,6 +26,10 @@ var Endpoint = map[string]string{
 	"hk": "hk.bcebos.com",
 }
 
+// DefaultMultipartCopyParallel is the default count of concurrent part copies of
+// bos.MultipartCopyObjectFromRequest method.
+const DefaultMultipartCopyParallel = 8
+
 // Config contains all options for bos.Client.
 type Config struct {
 	*bce.Config
@@ -43,24 +49,104 @@ func NewClient(config *Config) *Client {
 	return &Client{bceClient}
 }
 
-func checkBucketName(bucketName string) {
+// checkBucketName checks the name of BOS Bucket by the naming rules of BOS, it should be 3 to 63 lowercase
+// letters, digits and '-', and should not start or end with '-'.
+func checkBucketName(bucketName string) error {
+	return validateBucketName("bucketName", bucketName)
+}
+
+// checkObjectKey checks the key of BOS Object, it should be an UTF-8 string of 1 to 1024 bytes, and should
+// not start with '/' or '\'.
+func checkObjectKey(objectKey string) error {
+	return validateObjectKey("objectKey", objectKey)
+}
+
+// checkObject checks the name of BOS Bucket and the key of BOS Object.
+func checkObject(bucketName, objectKey string) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
+	return checkObjectKey(objectKey)
+}
+
+// checkBucketOrObject checks the name of BOS Bucket, and the key of BOS Object if it is not empty.
+func checkBucketOrObject(bucketName, objectKey string) error {
+	if objectKey == "" {
+		return checkBucketName(bucketName)
+	}
+
+	return checkObject(bucketName, objectKey)
+}
+
+// validateBucketName checks the name of BOS Bucket, field is the name of the argument in the error.
+func validateBucketName(field, bucketName string) error {
 	if bucketName == "" {
-		panic("bucket name should not be empty.")
+		return bce.NewValidationError(field, "The bucket name should not be empty.")
+	}
+
+	if len(bucketName) < MIN_BUCKET_NAME_LENGTH || len(bucketName) > MAX_BUCKET_NAME_LENGTH {
+		return bce.NewValidationError(field, fmt.Sprintf("The length of bucket name %s should be from %d to %d.",
+			bucketName, MIN_BUCKET_NAME_LENGTH, MAX_BUCKET_NAME_LENGTH))
+	}
+
+	for _, r := range bucketName {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return bce.NewValidationError(field, fmt.Sprintf(
+				"The bucket name %q should only contain lowercase letters, digits and '-'.", bucketName))
+		}
 	}
 
-	if strings.Index(bucketName, "/") == 0 {
-		panic("bucket name should not be start with '/'")
+	if strings.HasPrefix(bucketName, "-") || strings.HasSuffix(bucketName, "-") {
+		return bce.NewValidationError(field, fmt.Sprintf("The bucket name %s should not start or end with '-'.",
+			bucketName))
 	}
+
+	return nil
 }
 
-func checkObjectKey(objectKey string) {
+// validateObjectKey checks the key of BOS Object, field is the name of the argument in the error.
+func validateObjectKey(field, objectKey string) error {
 	if objectKey == "" {
-		panic("object key should not be empty.")
+		return bce.NewValidationError(field, "The object key should not be empty.")
+	}
+
+	if len(objectKey) > MAX_OBJECT_KEY_LENGTH {
+		return bce.NewValidationError(field, fmt.Sprintf("The length of object key should not be more than %d bytes.",
+			MAX_OBJECT_KEY_LENGTH))
+	}
+
+	if !utf8.ValidString(objectKey) {
+		return bce.NewValidationError(field, fmt.Sprintf("The object key %q should be UTF-8 encoded.", objectKey))
+	}
+
+	if strings.HasPrefix(objectKey, "/") || strings.HasPrefix(objectKey, "\\") {
+		return bce.NewValidationError(field, fmt.Sprintf("The object key %s should not start with '/' or '\\'.",
+			objectKey))
+	}
+
+	return nil
+}
+
+// cloneSignOption returns a copy of bce.SignOption, so that it can be used for sending
+// another request, the timestamp is not copied and will be generated again.
+func cloneSignOption(option *bce.SignOption) *bce.SignOption {
+	if option == nil {
+		return nil
+	}
+
+	cloned := &bce.SignOption{
+		ExpirationPeriodInSeconds: option.ExpirationPeriodInSeconds,
+		Credentials:               option.Credentials,
 	}
 
-	if strings.Index(objectKey, "/") == 0 {
-		panic("object key should not be start with '/'")
+	cloned.AddHeaders(option.Headers)
+
+	if len(option.HeadersToSign) > 0 {
+		cloned.HeadersToSign = append([]string{}, option.HeadersToSign...)
 	}
+
+	return cloned
 }
 
 // GetBucketName returns the actual name of BOS Bucket.
@@ -89,6 +175,10 @@ func (c *Client) GetURL(bucketName, objectKey string, params map[string]string)
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketLocation.E6.8E.A5.E5.8F.A3
 func (c *Client) GetBucketLocation(bucketName string, option *bce.SignOption) (*Location, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
 	bucketName = c.GetBucketName(bucketName)
 	params := map[string]string{"location": ""}
 
@@ -98,7 +188,7 @@ func (c *Client) GetBucketLocation(bucketName string, option *bce.SignOption) (*
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("GetBucketLocation", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -130,7 +220,7 @@ func (c *Client) ListBuckets(option *bce.SignOption) (*BucketSummary, error) {
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("ListBuckets", "", "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -156,13 +246,17 @@ func (c *Client) ListBuckets(option *bce.SignOption) (*BucketSummary, error) {
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucket.E6.8E.A5.E5.8F.A3
 func (c *Client) CreateBucket(bucketName string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, "", nil), nil)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("CreateBucket", bucketName, "", req, option)
 
 	return err
 }
@@ -171,13 +265,17 @@ func (c *Client) CreateBucket(bucketName string, option *bce.SignOption) error {
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#HeadBucket.E6.8E.A5.E5.8F.A3
 func (c *Client) DoesBucketExist(bucketName string, option *bce.SignOption) (bool, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return false, err
+	}
+
 	req, err := bce.NewRequest("HEAD", c.GetURL(bucketName, "", nil), nil)
 
 	if err != nil {
 		return false, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("DoesBucketExist", bucketName, "", req, option)
 
 	if resp != nil {
 		switch {
@@ -195,13 +293,17 @@ func (c *Client) DoesBucketExist(bucketName string, option *bce.SignOption) (boo
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucket.E6.8E.A5.E5.8F.A3
 func (c *Client) DeleteBucket(bucketName string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, "", nil), nil)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("DeleteBucket", bucketName, "", req, option)
 
 	return err
 }
@@ -210,27 +312,32 @@ func (c *Client) DeleteBucket(bucketName string, option *bce.SignOption) error {
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketAcl.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketPrivate(bucketName string, option *bce.SignOption) error {
-	return c.setBucketAclFromString(bucketName, CannedAccessControlList["Private"], option)
+	return c.setBucketAclFromString("SetBucketPrivate", bucketName, CannedAccessControlList["Private"], option)
 }
 
 // SetBucketPublicRead sets authorization of a BOS Bucket to public-read.
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketAcl.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketPublicRead(bucketName string, option *bce.SignOption) error {
-	return c.setBucketAclFromString(bucketName, CannedAccessControlList["PublicRead"], option)
+	return c.setBucketAclFromString("SetBucketPublicRead", bucketName, CannedAccessControlList["PublicRead"], option)
 }
 
 // SetBucketPublicReadWrite sets authorization of a BOS Bucket to public-read-write.
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketAcl.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketPublicReadWrite(bucketName string, option *bce.SignOption) error {
-	return c.setBucketAclFromString(bucketName, CannedAccessControlList["PublicReadWrite"], option)
+	return c.setBucketAclFromString("SetBucketPublicReadWrite", bucketName, CannedAccessControlList["PublicReadWrite"],
+		option)
 }
 
 // GetBucketAcl gets all authorization info of a BOS Bucket.
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketAcl.E6.8E.A5.E5.8F.A3
 func (c *Client) GetBucketAcl(bucketName string, option *bce.SignOption) (*BucketAcl, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
 	params := map[string]string{"acl": ""}
 	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
 
@@ -238,7 +345,7 @@ func (c *Client) GetBucketAcl(bucketName string, option *bce.SignOption) (*Bucke
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("GetBucketAcl", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -264,6 +371,10 @@ func (c *Client) GetBucketAcl(bucketName string, option *bce.SignOption) (*Bucke
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketAcl.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketAcl(bucketName string, bucketAcl BucketAcl, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	byteArray, err := util.ToJson(bucketAcl, "accessControlList")
 
 	if err != nil {
@@ -277,7 +388,113 @@ func (c *Client) SetBucketAcl(bucketName string, bucketAcl BucketAcl, option *bc
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("SetBucketAcl", bucketName, "", req, option)
+
+	return err
+}
+
+// GetObjectAcl gets all authorization info of a BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObjectAcl
+func (c *Client) GetObjectAcl(bucketName, objectKey string, option *bce.SignOption) (*BucketAcl, error) {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"acl": ""}
+	req, err := bce.NewRequest("GET", c.GetURL(bucketName, objectKey, params), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest("GetObjectAcl", bucketName, objectKey, req, option)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var objectAcl *BucketAcl
+	err = json.Unmarshal(bodyContent, &objectAcl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return objectAcl, nil
+}
+
+// SetObjectAcl sets authorization info of a BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutObjectAcl
+func (c *Client) SetObjectAcl(bucketName, objectKey string, objectAcl BucketAcl, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	byteArray, err := util.ToJson(objectAcl, "accessControlList")
+
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{"acl": ""}
+	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, objectKey, params), bytes.NewReader(byteArray))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest("SetObjectAcl", bucketName, objectKey, req, option)
+
+	return err
+}
+
+// SetObjectCannedAcl sets authorization of a BOS Object to one of bos.CannedAccessControlList.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutObjectAcl
+func (c *Client) SetObjectCannedAcl(bucketName, objectKey, cannedAcl string, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	params := map[string]string{"acl": ""}
+	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, objectKey, params), nil)
+
+	if err != nil {
+		return err
+	}
+
+	option = bce.CheckSignOption(option)
+	option.AddHeader("x-bce-acl", cannedAcl)
+
+	_, err = c.sendRequest("SetObjectCannedAcl", bucketName, objectKey, req, option)
+
+	return err
+}
+
+// DeleteObjectAcl deletes authorization info of a BOS Object, so the authorization of BOS Bucket takes effect.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteObjectAcl
+func (c *Client) DeleteObjectAcl(bucketName, objectKey string, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	params := map[string]string{"acl": ""}
+	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, objectKey, params), nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest("DeleteObjectAcl", bucketName, objectKey, req, option)
 
 	return err
 }
@@ -288,9 +505,37 @@ func (c *Client) SetBucketAcl(bucketName string, bucketAcl BucketAcl, option *bc
 func (c *Client) PutObject(bucketName, objectKey string, data interface{},
 	metadata *ObjectMetadata, option *bce.SignOption) (PutObjectResponse, error) {
 
-	checkObjectKey(objectKey)
+	return c.PutObjectFromRequest(PutObjectRequest{
+		BucketName:     bucketName,
+		ObjectKey:      objectKey,
+		Data:           data,
+		ObjectMetadata: metadata,
+	}, option)
+}
+
+// PutObjectFromRequest creates a BOS Object, the preconditions are checked against the existing BOS Object.
+//
+// A bos.ConditionError is returned if the preconditions fail, and a bos.IntegrityError is returned
+// if the checksum of the data sent is not the same as the ETag or CRC64 returned by BOS.
+//
+// The Content-MD5 of string and []byte data is always sent. The data of io.Reader is read once more
+// to compute the Content-MD5 and SHA-256 only if Checksum is enabled, otherwise it is sent as is
+// and only verified by the ETag returned by BOS after uploading.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutObject.E6.8E.A5.E5.8F.A3
+func (c *Client) PutObjectFromRequest(putObjectRequest PutObjectRequest,
+	option *bce.SignOption) (PutObjectResponse, error) {
+
+	if err := checkObject(putObjectRequest.BucketName, putObjectRequest.ObjectKey); err != nil {
+		return nil, err
+	}
+
+	if err := putObjectRequest.ObjectMetadata.serverSideEncryption().validate(); err != nil {
+		return nil, err
+	}
 
 	var reader io.Reader
+	data := putObjectRequest.Data
 
 	if str, ok := data.(string); ok {
 		reader = strings.NewReader(str)
@@ -298,28 +543,66 @@ func (c *Client) PutObject(bucketName, objectKey string, data interface{},
 		reader = bytes.NewReader(byteArray)
 	} else if r, ok := data.(io.Reader); ok {
 		reader = r
+
+		// the SHA-256 is sent before the data, so a reader which is not seekable is buffered to compute it
+		if _, seekable := r.(io.Seeker); c.Checksum && !seekable {
+			byteArray, err := ioutil.ReadAll(r)
+
+			if err != nil {
+				return nil, err
+			}
+
+			data = byteArray
+			reader = bytes.NewReader(byteArray)
+		}
 	} else {
-		panic("data type should be string or []byte or io.Reader.")
+		return nil, bce.NewValidationError("data", "The data type should be string or []byte or io.Reader.")
+	}
+
+	var dataChecksum *util.ChecksumReader
+
+	// the MD5 and SHA-256 are computed in the same pass, the buffered data is not an io.Reader any more
+	if _, isReader := data.(io.Reader); c.Checksum || !isReader {
+		if seeker, ok := reader.(io.ReadSeeker); ok {
+			var err error
+
+			if dataChecksum, err = seekableChecksum(seeker, 0); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, objectKey, nil), reader)
+	req, err := bce.NewRequest("PUT", c.GetURL(putObjectRequest.BucketName, putObjectRequest.ObjectKey, nil), reader)
 
 	if err != nil {
 		return nil, err
 	}
 
 	option = bce.CheckSignOption(option)
-	option.AddHeader("Content-Type", util.GuessMimeType(objectKey))
+	option.AddHeader("Content-Type", util.GuessMimeType(putObjectRequest.ObjectKey))
 
-	if c.Checksum {
-		option.AddHeader("x-bce-content-sha256", util.GetSha256(data))
+	if c.Checksum && dataChecksum != nil {
+		option.AddHeader("x-bce-content-sha256", dataChecksum.SHA256())
 	}
 
-	if metadata != nil {
-		metadata.mergeToSignOption(option)
+	if putObjectRequest.ObjectMetadata != nil {
+		putObjectRequest.ObjectMetadata.mergeToSignOption(option)
 	}
 
-	resp, err := c.SendRequest(req, option)
+	if dataChecksum != nil {
+		option.AddHeader("Content-MD5", dataChecksum.ContentMD5())
+	}
+
+	putObjectRequest.ObjectConditions.mergeToSignOption(option)
+
+	checksumReader := wrapChecksumReader(req)
+	resp, err := c.sendRequest("PutObject", putObjectRequest.BucketName, putObjectRequest.ObjectKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	err = verifyUpload(putObjectRequest.BucketName, putObjectRequest.ObjectKey, checksumReader, resp.Header, true)
 
 	if err != nil {
 		return nil, err
@@ -334,15 +617,25 @@ func (c *Client) PutObject(bucketName, objectKey string, data interface{},
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteObject.E6.8E.A5.E5.8F.A3
 func (c *Client) DeleteObject(bucketName, objectKey string, option *bce.SignOption) error {
-	checkObjectKey(objectKey)
+	return c.DeleteObjectFromRequest(DeleteObjectRequest{BucketName: bucketName, ObjectKey: objectKey}, option)
+}
 
-	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, objectKey, nil), nil)
+// DeleteObjectFromRequest deletes a BOS Object, or a specified version of BOS Object if VersionId is not empty.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteObject.E6.8E.A5.E5.8F.A3
+func (c *Client) DeleteObjectFromRequest(deleteObjectRequest DeleteObjectRequest, option *bce.SignOption) error {
+	if err := checkObject(deleteObjectRequest.BucketName, deleteObjectRequest.ObjectKey); err != nil {
+		return err
+	}
+
+	req, err := bce.NewRequest("DELETE", c.GetURL(deleteObjectRequest.BucketName, deleteObjectRequest.ObjectKey,
+		versionIdParams(deleteObjectRequest.VersionId)), nil)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("DeleteObject", deleteObjectRequest.BucketName, deleteObjectRequest.ObjectKey, req, option)
 
 	return err
 }
@@ -353,7 +646,9 @@ func (c *Client) DeleteObject(bucketName, objectKey string, option *bce.SignOpti
 func (c *Client) DeleteMultipleObjects(bucketName string, objectKeys []string,
 	option *bce.SignOption) (*DeleteMultipleObjectsResponse, error) {
 
-	checkBucketName(bucketName)
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
 
 	keys := make([]string, 0, len(objectKeys))
 
@@ -393,7 +688,7 @@ func (c *Client) DeleteMultipleObjects(bucketName string, objectKeys []string,
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("DeleteMultipleObjects", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -419,244 +714,982 @@ func (c *Client) DeleteMultipleObjects(bucketName string, objectKeys []string,
 	return nil, nil
 }
 
-// ListObjects get a list of BOS Object for the specified BOS Bucket.
+// DeletePrefix deletes all BOS Objects under the prefix of a BOS Bucket.
 //
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucket.2FListObjects.E6.8E.A5.E5.8F.A3
-func (c *Client) ListObjects(bucketName string, option *bce.SignOption) (*ListObjectsResponse, error) {
-	return c.ListObjectsFromRequest(ListObjectsRequest{BucketName: bucketName}, option)
-}
-
-// ListObjectsFromRequest get a list of BOS Object for the specified BOS Bucket.
+// The keys are listed page by page and deleted by bos.DeleteMultipleObjects in batches concurrently,
+// the keys failed with retryable errors are retried at most DELETE_PREFIX_MAX_RETRY times, and the keys
+// still failed of all batches are aggregated into bos.DeletePrefixResult.
 //
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucket.2FListObjects.E6.8E.A5.E5.8F.A3
-func (c *Client) ListObjectsFromRequest(listObjectsRequest ListObjectsRequest,
-	option *bce.SignOption) (*ListObjectsResponse, error) {
+// The empty prefix is rejected unless AllowEmptyPrefix of options is true.
+func (c *Client) DeletePrefix(bucketName, prefix string,
+	deletePrefixOptions *DeletePrefixOptions) (*DeletePrefixResult, error) {
 
-	bucketName := listObjectsRequest.BucketName
-	params := make(map[string]string)
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
 
-	if listObjectsRequest.Delimiter != "" {
-		params["delimiter"] = listObjectsRequest.Delimiter
+	options := DeletePrefixOptions{}
+
+	if deletePrefixOptions != nil {
+		options = *deletePrefixOptions
 	}
 
-	if listObjectsRequest.Marker != "" {
-		params["marker"] = listObjectsRequest.Marker
+	if prefix == "" && !options.AllowEmptyPrefix {
+		return nil, bce.NewValidationError("prefix",
+			"The empty prefix deletes all objects of the bucket, set AllowEmptyPrefix to allow it.")
 	}
 
-	if listObjectsRequest.Prefix != "" {
-		params["prefix"] = listObjectsRequest.Prefix
+	if options.BatchSize <= 0 || options.BatchSize > MAX_DELETE_MULTIPLE_OBJECTS {
+		options.BatchSize = MAX_DELETE_MULTIPLE_OBJECTS
 	}
 
-	if listObjectsRequest.MaxKeys > 0 {
-		params["maxKeys"] = strconv.Itoa(listObjectsRequest.MaxKeys)
+	if options.Parallel <= 0 {
+		options.Parallel = 1
 	}
 
-	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
+	result := &DeletePrefixResult{}
 
-	if err != nil {
-		return nil, err
+	if options.DryRun {
+		result.Keys = make([]string, 0)
 	}
 
-	resp, err := c.SendRequest(req, option)
+	batches := make(chan []string)
 
-	if err != nil {
-		return nil, err
-	}
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
 
-	bodyContent, err := resp.GetBodyContent()
+	for i := 0; i < options.Parallel; i++ {
+		waitGroup.Add(1)
 
-	if err != nil {
-		return nil, err
-	}
+		go func() {
+			defer waitGroup.Done()
 
-	var listObjectsResponse *ListObjectsResponse
-	err = json.Unmarshal(bodyContent, &listObjectsResponse)
+			for keys := range batches {
+				failed := c.deleteBatchWithRetry(bucketName, keys)
 
-	if err != nil {
-		return nil, err
+				mutex.Lock()
+				result.Deleted += len(keys) - len(failed)
+				result.Errors = append(result.Errors, failed...)
+				mutex.Unlock()
+			}
+		}()
 	}
 
-	return listObjectsResponse, nil
-}
+	listObjectsRequest := ListObjectsRequest{
+		BucketName: bucketName,
+		Prefix:     prefix,
+		MaxKeys:    options.BatchSize,
+	}
 
-// CopyObject copies an existing BOS Object.
-//
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#CopyObject.E6.8E.A5.E5.8F.A3
-func (c *Client) CopyObject(srcBucketName, srcKey, destBucketName, destKey string,
-	option *bce.SignOption) (*CopyObjectResponse, error) {
+	var listError error
 
-	return c.CopyObjectFromRequest(CopyObjectRequest{
-		SrcBucketName:  srcBucketName,
-		SrcKey:         srcKey,
-		DestBucketName: destBucketName,
-		DestKey:        destKey,
-	}, option)
-}
+	for {
+		listObjectsResponse, err := c.ListObjectsFromRequest(listObjectsRequest, nil)
 
-// CopyObject copies an existing BOS Object.
-//
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#CopyObject.E6.8E.A5.E5.8F.A3
-func (c *Client) CopyObjectFromRequest(copyObjectRequest CopyObjectRequest,
-	option *bce.SignOption) (*CopyObjectResponse, error) {
+		if err != nil {
+			listError = err
+			break
+		}
 
-	checkBucketName(copyObjectRequest.SrcBucketName)
-	checkBucketName(copyObjectRequest.DestBucketName)
-	checkObjectKey(copyObjectRequest.SrcKey)
-	checkObjectKey(copyObjectRequest.DestKey)
+		keys := make([]string, 0, len(listObjectsResponse.Contents))
 
-	req, err := bce.NewRequest("PUT", c.GetURL(copyObjectRequest.DestBucketName, copyObjectRequest.DestKey, nil), nil)
+		for _, objectSummary := range listObjectsResponse.Contents {
+			keys = append(keys, objectSummary.Key)
+		}
 
-	if err != nil {
-		return nil, err
-	}
+		if options.DryRun {
+			result.Deleted += len(keys)
+			result.Keys = append(result.Keys, keys...)
+		} else if len(keys) > 0 {
+			batches <- keys
+		}
 
-	option = bce.CheckSignOption(option)
+		if !listObjectsResponse.IsTruncated || len(keys) == 0 {
+			break
+		}
 
-	source := util.URIEncodeExceptSlash(fmt.Sprintf("/%s/%s", copyObjectRequest.SrcBucketName,
-		copyObjectRequest.SrcKey))
+		listObjectsRequest.Marker = listObjectsResponse.NextMarker
 
-	option.AddHeader("x-bce-copy-source", source)
-	copyObjectRequest.mergeToSignOption(option)
+		if listObjectsRequest.Marker == "" {
+			listObjectsRequest.Marker = keys[len(keys)-1]
+		}
+	}
 
-	resp, err := c.SendRequest(req, option)
+	close(batches)
+	waitGroup.Wait()
 
-	if err != nil {
-		return nil, err
+	if listError != nil {
+		return result, listError
 	}
 
-	bodyContent, err := resp.GetBodyContent()
+	if options.AbortMultipartUploads {
+		abortedUploads, err := c.abortMultipartUploadsUnderPrefix(bucketName, prefix, options.DryRun)
+		result.AbortedUploads = abortedUploads
 
-	if err != nil {
-		return nil, err
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) abortMultipartUploadsUnderPrefix(bucketName, prefix string, dryRun bool) (int, error) {
+	aborted := 0
+	listMultipartUploadsRequest := ListMultipartUploadsRequest{
+		BucketName: bucketName,
+		Prefix:     prefix,
+	}
+
+	for {
+		listMultipartUploadsResponse, err := c.ListMultipartUploadsFromRequest(listMultipartUploadsRequest, nil)
+
+		if err != nil {
+			return aborted, err
+		}
+
+		for _, upload := range listMultipartUploadsResponse.Uploads {
+			if !dryRun {
+				err := c.AbortMultipartUpload(AbortMultipartUploadRequest{
+					BucketName: bucketName,
+					ObjectKey:  upload.Key,
+					UploadId:   upload.UploadId,
+				}, nil)
+
+				if err != nil {
+					return aborted, err
+				}
+			}
+
+			aborted++
+		}
+
+		uploads := listMultipartUploadsResponse.Uploads
+
+		if !listMultipartUploadsResponse.IsTruncated || len(uploads) == 0 {
+			break
+		}
+
+		// a key may have several uploads, so the upload id marker is carried to continue from
+		// the middle of them, the last upload is the marker if the response has no next markers
+		listMultipartUploadsRequest.KeyMarker = listMultipartUploadsResponse.NextKeyMarker
+		listMultipartUploadsRequest.UploadIdMarker = listMultipartUploadsResponse.NextUploadIdMarker
+
+		if listMultipartUploadsRequest.KeyMarker == "" {
+			listMultipartUploadsRequest.KeyMarker = uploads[len(uploads)-1].Key
+			listMultipartUploadsRequest.UploadIdMarker = uploads[len(uploads)-1].UploadId
+		}
+	}
+
+	return aborted, nil
+}
+
+// deleteBatchWithRetry deletes a batch of keys by bos.DeleteMultipleObjects, the keys failed with
+// retryable errors are retried at most DELETE_PREFIX_MAX_RETRY times, and the failed keys are returned.
+func (c *Client) deleteBatchWithRetry(bucketName string, keys []string) []DeleteMultipleObjectsError {
+	failed := make([]DeleteMultipleObjectsError, 0)
+
+	for retry := 0; ; retry++ {
+		retryKeys := make([]string, 0)
+		retryErrors := make([]DeleteMultipleObjectsError, 0)
+
+		for _, deleteError := range c.deleteBatch(bucketName, keys) {
+			if isRetryableDeleteError(deleteError.Code) {
+				retryKeys = append(retryKeys, deleteError.Key)
+				retryErrors = append(retryErrors, deleteError)
+			} else {
+				failed = append(failed, deleteError)
+			}
+		}
+
+		if len(retryKeys) == 0 || retry >= DELETE_PREFIX_MAX_RETRY {
+			return append(failed, retryErrors...)
+		}
+
+		time.Sleep(time.Duration(1<<uint(retry)) * DELETE_PREFIX_RETRY_DELAY)
+		keys = retryKeys
+	}
+}
+
+// deleteBatch deletes a batch of keys by bos.DeleteMultipleObjects, if the whole request failed,
+// each key is reported with the code and message of the request error.
+func (c *Client) deleteBatch(bucketName string, keys []string) []DeleteMultipleObjectsError {
+	deleteMultipleObjectsResponse, err := c.DeleteMultipleObjects(bucketName, keys, nil)
+	failed := make([]DeleteMultipleObjectsError, 0)
+
+	if err != nil {
+		code := ""
+
+		if bceError, ok := err.(*bce.Error); ok {
+			code = bceError.Code
+		}
+
+		for _, key := range keys {
+			failed = append(failed, DeleteMultipleObjectsError{Key: key, Code: code, Message: err.Error()})
+		}
+	} else if deleteMultipleObjectsResponse != nil {
+		failed = deleteMultipleObjectsResponse.Errors
+	}
+
+	return failed
+}
+
+func isRetryableDeleteError(code string) bool {
+	switch code {
+	case "InternalError", "ServiceUnavailable", "SlowDown", "RequestTimeout":
+		return true
+	}
+
+	return false
+}
+
+// ListObjects get a list of BOS Object for the specified BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucket.2FListObjects.E6.8E.A5.E5.8F.A3
+func (c *Client) ListObjects(bucketName string, option *bce.SignOption) (*ListObjectsResponse, error) {
+	return c.ListObjectsFromRequest(ListObjectsRequest{BucketName: bucketName}, option)
+}
+
+// ListObjectsFromRequest get a list of BOS Object for the specified BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucket.2FListObjects.E6.8E.A5.E5.8F.A3
+func (c *Client) ListObjectsFromRequest(listObjectsRequest ListObjectsRequest,
+	option *bce.SignOption) (*ListObjectsResponse, error) {
+
+	if err := checkBucketName(listObjectsRequest.BucketName); err != nil {
+		return nil, err
+	}
+
+	bucketName := listObjectsRequest.BucketName
+	params := make(map[string]string)
+
+	if listObjectsRequest.Delimiter != "" {
+		params["delimiter"] = listObjectsRequest.Delimiter
+	}
+
+	if listObjectsRequest.Marker != "" {
+		params["marker"] = listObjectsRequest.Marker
+	}
+
+	if listObjectsRequest.Prefix != "" {
+		params["prefix"] = listObjectsRequest.Prefix
+	}
+
+	if listObjectsRequest.MaxKeys > 0 {
+		params["maxKeys"] = strconv.Itoa(listObjectsRequest.MaxKeys)
+	}
+
+	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest("ListObjects", bucketName, "", req, option)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var listObjectsResponse *ListObjectsResponse
+	err = json.Unmarshal(bodyContent, &listObjectsResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listObjectsResponse, nil
+}
+
+// CopyObject copies an existing BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#CopyObject.E6.8E.A5.E5.8F.A3
+func (c *Client) CopyObject(srcBucketName, srcKey, destBucketName, destKey string,
+	option *bce.SignOption) (*CopyObjectResponse, error) {
+
+	return c.CopyObjectFromRequest(CopyObjectRequest{
+		SrcBucketName:  srcBucketName,
+		SrcKey:         srcKey,
+		DestBucketName: destBucketName,
+		DestKey:        destKey,
+	}, option)
+}
+
+// CopyObject copies an existing BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#CopyObject.E6.8E.A5.E5.8F.A3
+func (c *Client) CopyObjectFromRequest(copyObjectRequest CopyObjectRequest,
+	option *bce.SignOption) (*CopyObjectResponse, error) {
+
+	if err := validateBucketName("srcBucketName", copyObjectRequest.SrcBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateBucketName("destBucketName", copyObjectRequest.DestBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("srcKey", copyObjectRequest.SrcKey); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("destKey", copyObjectRequest.DestKey); err != nil {
+		return nil, err
+	}
+
+	if err := copyObjectRequest.validateServerSideEncryption(); err != nil {
+		return nil, err
+	}
+
+	req, err := bce.NewRequest("PUT", c.GetURL(copyObjectRequest.DestBucketName, copyObjectRequest.DestKey, nil), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option = bce.CheckSignOption(option)
+
+	source := copySource(copyObjectRequest.SrcBucketName, copyObjectRequest.SrcKey, copyObjectRequest.SrcVersionId)
+
+	option.AddHeader("x-bce-copy-source", source)
+	copyObjectRequest.mergeToSignOption(option)
+
+	resp, err := c.sendRequest("CopyObject", copyObjectRequest.DestBucketName, copyObjectRequest.DestKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var copyObjectResponse *CopyObjectResponse
+	err = json.Unmarshal(bodyContent, &copyObjectResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return copyObjectResponse, nil
+}
+
+// MultipartCopyObjectFromRequest copies an existing BOS Object by BOS Object Multipart Upload,
+// so it has no size limit of bos.CopyObjectFromRequest.
+//
+// The ranges of source object are copied by bos.UploadPartCopy concurrently, at most parallel
+// parts at a time (bos.DefaultMultipartCopyParallel if parallel is not positive). Metadata is
+// replaced if copyObjectRequest.ObjectMetadata is specified, otherwise it is copied from source.
+//
+// If a BOS Object is copied to itself, its ETag is checked again before the copy is completed,
+// and a bos.ConditionError is returned if it is changed while the parts are copied.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#UploadPartCopy
+func (c *Client) MultipartCopyObjectFromRequest(copyObjectRequest CopyObjectRequest, partSize int64,
+	parallel int, option *bce.SignOption) (*CompleteMultipartUploadResponse, error) {
+
+	if err := validateBucketName("srcBucketName", copyObjectRequest.SrcBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateBucketName("destBucketName", copyObjectRequest.DestBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("srcKey", copyObjectRequest.SrcKey); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("destKey", copyObjectRequest.DestKey); err != nil {
+		return nil, err
+	}
+
+	if err := copyObjectRequest.validateServerSideEncryption(); err != nil {
+		return nil, err
+	}
+
+	if partSize < MIN_PART_SIZE || partSize > MAX_PART_SIZE {
+		return nil, bce.NewValidationError("partSize", fmt.Sprintf("The part size %d should be from %d to %d.",
+			partSize, MIN_PART_SIZE, MAX_PART_SIZE))
+	}
+
+	if parallel <= 0 {
+		parallel = DefaultMultipartCopyParallel
+	}
+
+	srcMetadata, err := c.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+		BucketName:           copyObjectRequest.SrcBucketName,
+		ObjectKey:            copyObjectRequest.SrcKey,
+		VersionId:            copyObjectRequest.SrcVersionId,
+		ServerSideEncryption: copyObjectRequest.SourceServerSideEncryption,
+	}, cloneSignOption(option))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var totalSize int64 = srcMetadata.ContentLength
+	var partCount int = int(math.Ceil(float64(totalSize) / float64(partSize)))
+
+	if partCount == 0 {
+		partCount = 1
+	}
+
+	if partCount > MAX_PART_NUMBER {
+		return nil, bce.NewValidationError("partSize", fmt.Sprintf(
+			"The part size %d is too small for object size %d, the max part number is %d.",
+			partSize, totalSize, MAX_PART_NUMBER))
+	}
+
+	metadata := copyObjectRequest.ObjectMetadata
+
+	if metadata == nil {
+		metadata = srcMetadata.copyable()
+	}
+
+	if copyObjectRequest.ServerSideEncryption != nil {
+		copied := *metadata
+		copied.ServerSideEncryption = copyObjectRequest.ServerSideEncryption
+		metadata = &copied
+	}
+
+	initiateOption := cloneSignOption(option)
+
+	if initiateOption == nil {
+		initiateOption = &bce.SignOption{}
+	}
+
+	if copyObjectRequest.Acl != "" {
+		initiateOption.AddHeader("x-bce-acl", copyObjectRequest.Acl)
+	}
+
+	metadata.mergeToSignOption(initiateOption)
+
+	initiateMultipartUploadResponse, err := c.InitiateMultipartUpload(InitiateMultipartUploadRequest{
+		BucketName: copyObjectRequest.DestBucketName,
+		ObjectKey:  copyObjectRequest.DestKey,
+	}, initiateOption)
+
+	if err != nil {
+		return nil, err
+	}
+
+	uploadId := initiateMultipartUploadResponse.UploadId
+	sourceMatch := copyObjectRequest.SourceMatch
+
+	// only the key provided by customer is required to copy parts, SSE-BOS is specified when initiating
+	var partEncryption *ServerSideEncryption
+
+	if sse := metadata.ServerSideEncryption; sse != nil && sse.IsCustomerKey() {
+		partEncryption = sse
+	}
+
+	if sourceMatch == "" {
+		sourceMatch = srcMetadata.ETag
+	}
+
+	parts := make([]PartSummary, partCount)
+	partNumbers := make(chan int)
+
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var copyError error
+
+	for i := 0; i < parallel; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for partNumber := range partNumbers {
+				uploadPartCopyRequest := UploadPartCopyRequest{
+					SrcBucketName:         copyObjectRequest.SrcBucketName,
+					SrcKey:                copyObjectRequest.SrcKey,
+					SrcVersionId:          copyObjectRequest.SrcVersionId,
+					DestBucketName:        copyObjectRequest.DestBucketName,
+					DestKey:               copyObjectRequest.DestKey,
+					UploadId:              uploadId,
+					PartNumber:            partNumber,
+					SourceMatch:           sourceMatch,
+					SourceNoneMatch:       copyObjectRequest.SourceNoneMatch,
+					SourceModifiedSince:   copyObjectRequest.SourceModifiedSince,
+					SourceUnmodifiedSince: copyObjectRequest.SourceUnmodifiedSince,
+
+					ServerSideEncryption:       partEncryption,
+					SourceServerSideEncryption: copyObjectRequest.SourceServerSideEncryption,
+				}
+
+				if totalSize > 0 {
+					var start int64 = partSize * int64(partNumber-1)
+					var end int64 = int64(math.Min(float64(totalSize), float64(start+partSize))) - 1
+					uploadPartCopyRequest.SetSourceRange(start, end)
+				}
+
+				uploadPartCopyResponse, err := c.UploadPartCopy(uploadPartCopyRequest, cloneSignOption(option))
+
+				mutex.Lock()
+
+				if err != nil {
+					if copyError == nil {
+						copyError = err
+					}
+				} else {
+					parts[partNumber-1] = PartSummary{PartNumber: partNumber, ETag: uploadPartCopyResponse.ETag}
+				}
+
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for partNumber := 1; partNumber <= partCount; partNumber++ {
+		mutex.Lock()
+		failed := copyError != nil
+		mutex.Unlock()
+
+		if failed {
+			break
+		}
+
+		partNumbers <- partNumber
+	}
+
+	close(partNumbers)
+	waitGroup.Wait()
+
+	// the parts are guarded by the ETag, but a copy to itself would overwrite a change made after them
+	if copyError == nil && copyObjectRequest.SrcBucketName == copyObjectRequest.DestBucketName &&
+		copyObjectRequest.SrcKey == copyObjectRequest.DestKey {
+
+		copyError = c.checkSourceETag(copyObjectRequest, sourceMatch, option)
+	}
+
+	if copyError != nil {
+		c.AbortMultipartUpload(AbortMultipartUploadRequest{
+			BucketName: copyObjectRequest.DestBucketName,
+			ObjectKey:  copyObjectRequest.DestKey,
+			UploadId:   uploadId,
+		}, cloneSignOption(option))
+
+		return nil, copyError
+	}
+
+	return c.CompleteMultipartUpload(CompleteMultipartUploadRequest{
+		BucketName: copyObjectRequest.DestBucketName,
+		ObjectKey:  copyObjectRequest.DestKey,
+		UploadId:   uploadId,
+		Parts:      parts,
+	}, cloneSignOption(option))
+}
+
+// checkSourceETag checks the ETag of the source BOS Object of a copy, a bos.ConditionError of 412 response
+// is returned if it is changed.
+func (c *Client) checkSourceETag(copyObjectRequest CopyObjectRequest, eTag string, option *bce.SignOption) error {
+	metadata, err := c.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+		BucketName:           copyObjectRequest.SrcBucketName,
+		ObjectKey:            copyObjectRequest.SrcKey,
+		VersionId:            copyObjectRequest.SrcVersionId,
+		ServerSideEncryption: copyObjectRequest.SourceServerSideEncryption,
+	}, cloneSignOption(option))
+
+	if err != nil {
+		return err
+	}
+
+	if metadata.ETag != eTag {
+		return &ConditionError{StatusCode: http.StatusPreconditionFailed, ObjectMetadata: metadata}
+	}
+
+	return nil
+}
+
+// GetObject gets a BOS Object details.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
+func (c *Client) GetObject(bucketName, objectKey string, option *bce.SignOption) (*Object, error) {
+	return c.GetObjectFromRequest(GetObjectRequest{
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+	}, option)
+}
+
+// GetObjectFromRequest gets a BOS Object details.
+//
+// A bos.ConditionError is returned if the BOS Object is not modified or the preconditions fail.
+// The content of the whole BOS Object is verified while it is read, reading ObjectContent returns
+// a bos.IntegrityError instead of io.EOF if the content is corrupted.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
+func (c *Client) GetObjectFromRequest(getObjectRequest GetObjectRequest,
+	option *bce.SignOption) (*Object, error) {
+
+	if err := checkObject(getObjectRequest.BucketName, getObjectRequest.ObjectKey); err != nil {
+		return nil, err
+	}
+
+	if err := getObjectRequest.ServerSideEncryption.validate(); err != nil {
+		return nil, err
+	}
+
+	req, err := bce.NewRequest("GET", c.GetURL(getObjectRequest.BucketName, getObjectRequest.ObjectKey,
+		getObjectRequest.params()), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option = bce.CheckSignOption(option)
+	getObjectRequest.MergeToSignOption(option)
+
+	resp, err := c.sendRequest("GetObject", getObjectRequest.BucketName, getObjectRequest.ObjectKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	object := &Object{
+		ObjectMetadata: NewObjectMetadataFromHeader(resp.Header),
+		ObjectContent:  newIntegrityReadCloser(getObjectRequest.BucketName, getObjectRequest.ObjectKey, resp),
+	}
+
+	return object, nil
+}
+
+// GetObjectToFile gets the content of a BOS Object to local file.
+//
+// The content is verified while it is written, a bos.IntegrityError is returned if the content is corrupted.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
+func (c *Client) GetObjectToFile(getObjectRequest *GetObjectRequest, file *os.File,
+	option *bce.SignOption) (*ObjectMetadata, error) {
+
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	if err := checkObject(getObjectRequest.BucketName, getObjectRequest.ObjectKey); err != nil {
+		return nil, err
+	}
+
+	if err := getObjectRequest.ServerSideEncryption.validate(); err != nil {
+		return nil, err
+	}
+
+	req, err := bce.NewRequest("GET", c.GetURL(getObjectRequest.BucketName, getObjectRequest.ObjectKey,
+		getObjectRequest.params()), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option = bce.CheckSignOption(option)
+	getObjectRequest.MergeToSignOption(option)
+
+	resp, err := c.sendRequest("GetObjectToFile", getObjectRequest.BucketName, getObjectRequest.ObjectKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	objectMetadata := NewObjectMetadataFromHeader(resp.Header)
+
+	objectContent := newIntegrityReadCloser(getObjectRequest.BucketName, getObjectRequest.ObjectKey, resp)
+	defer objectContent.Close()
+
+	_, err = io.Copy(file, objectContent)
+
+	if err != nil {
+		return objectMetadata, err
+	}
+
+	return objectMetadata, nil
+}
+
+// GetObjectMetadata gets the metadata details of a BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObjectMeta.E6.8E.A5.E5.8F.A3
+func (c *Client) GetObjectMetadata(bucketName, objectKey string, option *bce.SignOption) (*ObjectMetadata, error) {
+	return c.GetObjectMetadataFromRequest(GetObjectMetadataRequest{BucketName: bucketName, ObjectKey: objectKey}, option)
+}
+
+// GetObjectMetadataFromRequest gets the metadata details of a BOS Object,
+// or a specified version of BOS Object if VersionId is not empty.
+//
+// A bos.ConditionError is returned if the BOS Object is not modified or the preconditions fail.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObjectMeta.E6.8E.A5.E5.8F.A3
+func (c *Client) GetObjectMetadataFromRequest(getObjectMetadataRequest GetObjectMetadataRequest,
+	option *bce.SignOption) (*ObjectMetadata, error) {
+
+	if err := checkObject(getObjectMetadataRequest.BucketName, getObjectMetadataRequest.ObjectKey); err != nil {
+		return nil, err
+	}
+
+	if err := getObjectMetadataRequest.ServerSideEncryption.validate(); err != nil {
+		return nil, err
+	}
+
+	req, err := bce.NewRequest("HEAD", c.GetURL(getObjectMetadataRequest.BucketName, getObjectMetadataRequest.ObjectKey,
+		versionIdParams(getObjectMetadataRequest.VersionId)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option = bce.CheckSignOption(option)
+	getObjectMetadataRequest.ObjectConditions.mergeToSignOption(option)
+	getObjectMetadataRequest.ServerSideEncryption.mergeToSignOption(option, false)
+
+	resp, err := c.sendRequest("GetObjectMetadata", getObjectMetadataRequest.BucketName,
+		getObjectMetadataRequest.ObjectKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	objectMetadata := NewObjectMetadataFromHeader(resp.Header)
+
+	return objectMetadata, nil
+}
+
+// SetObjectStorageClass changes the storage class of an existing BOS Object in place,
+// by copying the BOS Object to itself with its metadata kept.
+//
+// An archived BOS Object must be restored before its storage class can be changed.
+func (c *Client) SetObjectStorageClass(bucketName, objectKey, storageClass string, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	if !isStorageClass(storageClass) {
+		return bce.NewValidationError("storageClass", fmt.Sprintf("The storage class %s is not supported.",
+			storageClass))
+	}
+
+	metadata, err := c.GetObjectMetadata(bucketName, objectKey, cloneSignOption(option))
+
+	if err != nil {
+		return err
+	}
+
+	if metadata.StorageClass == storageClass ||
+		(metadata.StorageClass == "" && storageClass == STORAGE_CLASS_STANDARD) {
+		return nil
+	}
+
+	if metadata.StorageClass == STORAGE_CLASS_ARCHIVE && !metadata.IsRestored() {
+		return bce.NewValidationError("storageClass", fmt.Sprintf(
+			"The BOS Object %s is archived, please restore it first.", objectKey))
+	}
+
+	return c.replaceObjectMetadata(bucketName, objectKey, metadata, nil, func(objectMetadata *ObjectMetadata) {
+		objectMetadata.StorageClass = storageClass
+	}, option)
+}
+
+// UpdateObjectMetadata updates the metadata of an existing BOS Object without uploading it again.
+//
+// The current metadata is got and passed to update, and then the BOS Object is copied to itself with the
+// updated metadata. Keys of UserMetadata passed to update are lower case with prefix x-bce-meta-.
+// The copy is guarded by the current ETag, so it fails instead of overwriting if the BOS Object is
+// changed concurrently. A BOS Object larger than MAX_PART_SIZE is copied by parts, and its ETag is checked
+// again before the copy is completed, but it can still be overwritten by a change between the check and
+// the completion.
+//
+// A BOS Object encrypted with a key provided by customer should be updated by bos.UpdateObjectMetadataFromRequest.
+func (c *Client) UpdateObjectMetadata(bucketName, objectKey string, update func(*ObjectMetadata),
+	option *bce.SignOption) error {
+
+	return c.UpdateObjectMetadataFromRequest(UpdateObjectMetadataRequest{
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+		Update:     update,
+	}, option)
+}
+
+// UpdateObjectMetadataFromRequest updates the metadata of an existing BOS Object without uploading it again,
+// see bos.UpdateObjectMetadata for details.
+func (c *Client) UpdateObjectMetadataFromRequest(updateObjectMetadataRequest UpdateObjectMetadataRequest,
+	option *bce.SignOption) error {
+
+	bucketName, objectKey := updateObjectMetadataRequest.BucketName, updateObjectMetadataRequest.ObjectKey
+
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
 	}
 
-	var copyObjectResponse *CopyObjectResponse
-	err = json.Unmarshal(bodyContent, &copyObjectResponse)
+	if updateObjectMetadataRequest.Update == nil {
+		return bce.NewValidationError("update", "The update function should not be nil.")
+	}
 
-	if err != nil {
-		return nil, err
+	sse := updateObjectMetadataRequest.ServerSideEncryption
+
+	if err := sse.validate(); err != nil {
+		return err
 	}
 
-	return copyObjectResponse, nil
-}
+	metadata, err := c.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+		BucketName:           bucketName,
+		ObjectKey:            objectKey,
+		ServerSideEncryption: sse,
+	}, cloneSignOption(option))
 
-// GetObject gets a BOS Object details.
-//
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
-func (c *Client) GetObject(bucketName, objectKey string, option *bce.SignOption) (*Object, error) {
-	return c.GetObjectFromRequest(GetObjectRequest{
-		BucketName: bucketName,
-		ObjectKey:  objectKey,
-	}, option)
+	if err != nil {
+		return err
+	}
+
+	return c.replaceObjectMetadata(bucketName, objectKey, metadata, sse, updateObjectMetadataRequest.Update, option)
 }
 
-// GetObjectFromRequest gets a BOS Object details.
-//
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
-func (c *Client) GetObjectFromRequest(getObjectRequest GetObjectRequest,
-	option *bce.SignOption) (*Object, error) {
+// selfCopyPartSize is the part size of copying a BOS Object larger than it to itself by multipart copy,
+// it is the max size of bos.CopyObject. It is a variable so that tests can lower it.
+var selfCopyPartSize = MAX_PART_SIZE
 
-	checkBucketName(getObjectRequest.BucketName)
-	checkObjectKey(getObjectRequest.ObjectKey)
+// replaceObjectMetadata copies a BOS Object to itself with the metadata updated by update,
+// the copy fails if the ETag of the BOS Object is not the same as metadata. The BOS Object encrypted
+// with a key provided by customer is read and encrypted again with sse.
+func (c *Client) replaceObjectMetadata(bucketName, objectKey string, metadata *ObjectMetadata,
+	sse *ServerSideEncryption, update func(*ObjectMetadata), option *bce.SignOption) error {
 
-	req, err := bce.NewRequest("GET", c.GetURL(getObjectRequest.BucketName, getObjectRequest.ObjectKey, nil), nil)
+	customerKey := metadata.ServerSideEncryption != nil && metadata.ServerSideEncryption.IsCustomerKey()
 
-	if err != nil {
-		return nil, err
+	if customerKey && (sse == nil || sse.CustomerKey == nil) {
+		return bce.NewValidationError("serverSideEncryption", fmt.Sprintf(
+			"The object %s is encrypted with a customer key, the key should be provided.", objectKey))
 	}
 
-	option = bce.CheckSignOption(option)
-	getObjectRequest.MergeToSignOption(option)
+	objectMetadata := metadata.copyable()
+	update(objectMetadata)
 
-	resp, err := c.SendRequest(req, option)
+	copyObjectRequest := CopyObjectRequest{
+		SrcBucketName:  bucketName,
+		SrcKey:         objectKey,
+		DestBucketName: bucketName,
+		DestKey:        objectKey,
+		ObjectMetadata: objectMetadata,
+		SourceMatch:    metadata.ETag,
+	}
 
-	if err != nil {
-		return nil, err
+	if customerKey {
+		copyObjectRequest.ServerSideEncryption = sse
+		copyObjectRequest.SourceServerSideEncryption = sse
 	}
 
-	object := &Object{
-		ObjectMetadata: NewObjectMetadataFromHeader(resp.Header),
-		ObjectContent:  resp.Body,
+	var err error
+
+	if metadata.ContentLength > selfCopyPartSize {
+		_, err = c.MultipartCopyObjectFromRequest(copyObjectRequest, selfCopyPartSize, 0, option)
+	} else {
+		_, err = c.CopyObjectFromRequest(copyObjectRequest, option)
 	}
 
-	return object, nil
+	return err
 }
 
-// GetObjectToFile gets the content of a BOS Object to local file.
+// PutObjectTagging sets the tags of a BOS Object, all existing tags will be replaced.
 //
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObject.E6.8E.A5.E5.8F.A3
-func (c *Client) GetObjectToFile(getObjectRequest *GetObjectRequest, file *os.File,
-	option *bce.SignOption) (*ObjectMetadata, error) {
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutObjectTagging
+func (c *Client) PutObjectTagging(bucketName, objectKey string, objectTagging ObjectTagging,
+	option *bce.SignOption) error {
 
-	defer func() {
-		if file != nil {
-			file.Close()
-		}
-	}()
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
 
-	checkBucketName(getObjectRequest.BucketName)
-	checkObjectKey(getObjectRequest.ObjectKey)
+	if err := objectTagging.validate(); err != nil {
+		return err
+	}
 
-	req, err := bce.NewRequest("GET", c.GetURL(getObjectRequest.BucketName, getObjectRequest.ObjectKey, nil), nil)
+	return c.putSubResource("PutObjectTagging", bucketName, objectKey, "tagging", objectTagging, option)
+}
 
-	if err != nil {
+// GetObjectTagging gets the tags of a BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObjectTagging
+func (c *Client) GetObjectTagging(bucketName, objectKey string, option *bce.SignOption) (*ObjectTagging, error) {
+	if err := checkObject(bucketName, objectKey); err != nil {
 		return nil, err
 	}
 
-	option = bce.CheckSignOption(option)
-	getObjectRequest.MergeToSignOption(option)
+	var objectTagging ObjectTagging
 
-	resp, err := c.SendRequest(req, option)
+	if err := c.getSubResource("GetObjectTagging", bucketName, objectKey, "tagging",
+		&objectTagging, option); err != nil {
 
-	if err != nil {
 		return nil, err
 	}
 
-	objectMetadata := NewObjectMetadataFromHeader(resp.Header)
-
-	bodyContent, err := resp.GetBodyContent()
+	return &objectTagging, nil
+}
 
-	if err != nil {
-		return objectMetadata, err
+// DeleteObjectTagging deletes all tags of a BOS Object.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteObjectTagging
+func (c *Client) DeleteObjectTagging(bucketName, objectKey string, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
 	}
 
-	_, err = file.Write(bodyContent)
+	return c.deleteSubResource("DeleteObjectTagging", bucketName, objectKey, "tagging", option)
+}
 
-	if err != nil {
-		return objectMetadata, err
+// RestoreObject restores an archived BOS Object, so it can be read in the specified days.
+//
+// If days is 0 or tier is empty, the default value of BOS will be used.
+// The restore status can be got by bos.ObjectMetadata.Restore.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#RestoreObject
+func (c *Client) RestoreObject(bucketName, objectKey string, days int, tier string, option *bce.SignOption) error {
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
 	}
 
-	return objectMetadata, nil
-}
+	if days != 0 && (days < MIN_RESTORE_DAYS || days > MAX_RESTORE_DAYS) {
+		return bce.NewValidationError("days", fmt.Sprintf("The restore days %d should be from %d to %d.",
+			days, MIN_RESTORE_DAYS, MAX_RESTORE_DAYS))
+	}
 
-// GetObjectMetadata gets the metadata details of a BOS Object.
-//
-// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetObjectMeta.E6.8E.A5.E5.8F.A3
-func (c *Client) GetObjectMetadata(bucketName, objectKey string, option *bce.SignOption) (*ObjectMetadata, error) {
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	if tier != "" && tier != RESTORE_TIER_EXPEDITED && tier != RESTORE_TIER_STANDARD && tier != RESTORE_TIER_LOWCOST {
+		return bce.NewValidationError("tier", fmt.Sprintf("The restore tier %s is not supported.", tier))
+	}
 
-	req, err := bce.NewRequest("HEAD", c.GetURL(bucketName, objectKey, nil), nil)
+	params := map[string]string{"restore": ""}
+	req, err := bce.NewRequest("POST", c.GetURL(bucketName, objectKey, params), nil)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	option = bce.CheckSignOption(option)
 
-	if err != nil {
-		return nil, err
+	if days != 0 {
+		option.AddHeader("x-bce-restore-days", strconv.Itoa(days))
 	}
 
-	objectMetadata := NewObjectMetadataFromHeader(resp.Header)
+	if tier != "" {
+		option.AddHeader("x-bce-restore-tier", tier)
+	}
 
-	return objectMetadata, nil
+	_, err = c.sendRequest("RestoreObject", bucketName, objectKey, req, option)
+
+	return err
 }
 
 // GeneratePresignedUrl generates the full URL of a BOS Object.
 func (c *Client) GeneratePresignedUrl(bucketName, objectKey string, option *bce.SignOption) (string, error) {
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return "", err
+	}
 
 	req, err := bce.NewRequest("GET", c.GetURL(bucketName, objectKey, nil), nil)
 
@@ -679,8 +1712,13 @@ func (c *Client) GeneratePresignedUrl(bucketName, objectKey string, option *bce.
 func (c *Client) AppendObject(bucketName, objectKey string, offset int, data interface{},
 	metadata *ObjectMetadata, option *bce.SignOption) (AppendObjectResponse, error) {
 
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
+
+	if err := metadata.serverSideEncryption().validate(); err != nil {
+		return nil, err
+	}
 
 	var reader io.Reader
 
@@ -695,9 +1733,10 @@ func (c *Client) AppendObject(bucketName, objectKey string, offset int, data int
 			return nil, err
 		}
 
+		data = byteArray
 		reader = bytes.NewReader(byteArray)
 	} else {
-		panic("data type should be string or []byte or io.Reader.")
+		return nil, bce.NewValidationError("data", "The data type should be string or []byte or io.Reader.")
 	}
 
 	params := map[string]string{"append": ""}
@@ -716,14 +1755,28 @@ func (c *Client) AppendObject(bucketName, objectKey string, offset int, data int
 	option.AddHeader("Content-Type", util.GuessMimeType(objectKey))
 
 	if c.Checksum {
-		option.AddHeader("x-bce-content-sha256", util.GetSha256(data))
+		sha256Value, err := util.GetSha256(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		option.AddHeader("x-bce-content-sha256", sha256Value)
 	}
 
 	if metadata != nil {
 		metadata.mergeToSignOption(option)
 	}
 
-	resp, err := c.SendRequest(req, option)
+	contentMD5, err := util.GetMD5(data, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option.AddHeader("Content-MD5", contentMD5)
+
+	resp, err := c.sendRequest("AppendObject", bucketName, objectKey, req, option)
 
 	if err != nil {
 		return nil, err
@@ -743,8 +1796,13 @@ func (c *Client) InitiateMultipartUpload(initiateMultipartUploadRequest Initiate
 	bucketName := initiateMultipartUploadRequest.BucketName
 	objectKey := initiateMultipartUploadRequest.ObjectKey
 
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
+
+	if err := initiateMultipartUploadRequest.ObjectMetadata.serverSideEncryption().validate(); err != nil {
+		return nil, err
+	}
 
 	params := map[string]string{"uploads": ""}
 
@@ -761,7 +1819,7 @@ func (c *Client) InitiateMultipartUpload(initiateMultipartUploadRequest Initiate
 		initiateMultipartUploadRequest.ObjectMetadata.mergeToSignOption(option)
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("InitiateMultipartUpload", bucketName, objectKey, req, option)
 
 	if err != nil {
 		return nil, err
@@ -791,16 +1849,23 @@ func (c *Client) UploadPart(uploadPartRequest UploadPartRequest,
 
 	bucketName := uploadPartRequest.BucketName
 	objectKey := uploadPartRequest.ObjectKey
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
 
 	if uploadPartRequest.PartNumber < MIN_PART_NUMBER || uploadPartRequest.PartNumber > MAX_PART_NUMBER {
-		panic(fmt.Sprintf("Invalid partNumber %d. The valid range is from %d to %d.",
+		return nil, bce.NewValidationError("partNumber", fmt.Sprintf("The part number %d should be from %d to %d.",
 			uploadPartRequest.PartNumber, MIN_PART_NUMBER, MAX_PART_NUMBER))
 	}
 
-	if uploadPartRequest.PartSize > 1024*1024*1024*5 {
-		panic(fmt.Sprintf("PartNumber %d: Part Size should not be more than 5GB.", uploadPartRequest.PartSize))
+	if uploadPartRequest.PartSize > MAX_PART_SIZE {
+		return nil, bce.NewValidationError("partSize", fmt.Sprintf("The part size %d should not be more than 5GB.",
+			uploadPartRequest.PartSize))
+	}
+
+	if err := uploadPartRequest.ServerSideEncryption.validate(); err != nil {
+		return nil, err
 	}
 
 	params := map[string]string{
@@ -808,33 +1873,144 @@ func (c *Client) UploadPart(uploadPartRequest UploadPartRequest,
 		"uploadId":   uploadPartRequest.UploadId,
 	}
 
+	// a seekable part is uploaded from its beginning, even if it has been read or written by the caller
+	if seeker, ok := uploadPartRequest.PartData.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, objectKey, params), uploadPartRequest.PartData)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if uploadPartRequest.PartSize > 0 {
+		req.ContentLength = uploadPartRequest.PartSize
+	}
+
 	option = bce.CheckSignOption(option)
 	option.AddHeaders(map[string]string{
 		"Content-Length": strconv.FormatInt(uploadPartRequest.PartSize, 10),
 		"Content-Type":   "application/octet-stream",
 	})
 
-	if _, ok := option.Headers["Content-MD5"]; !ok {
-		option.AddHeader("Content-MD5", util.GetMD5(uploadPartRequest.PartData, true))
+	uploadPartRequest.ServerSideEncryption.mergeToSignOption(option, false)
+
+	// the checksum of a seekable part is sent with it, so BOS rejects the part if it is corrupted,
+	// the part which is not seekable is verified by its ETag after uploading instead
+	hasChecksum := util.GetMapValue(option.Headers, "Content-MD5", true) != "" &&
+		(!c.Checksum || util.GetMapValue(option.Headers, "x-bce-content-sha256", true) != "")
+
+	if seeker, ok := uploadPartRequest.PartData.(io.ReadSeeker); ok && !hasChecksum {
+		partChecksum, err := seekableChecksum(seeker, uploadPartRequest.PartSize)
+
+		if err != nil {
+			return nil, err
+		}
+
+		option.AddHeader("Content-MD5", partChecksum.ContentMD5())
+
+		if c.Checksum {
+			option.AddHeader("x-bce-content-sha256", partChecksum.SHA256())
+		}
 	}
 
-	resp, err := c.SendRequest(req, option)
+	checksumReader := wrapChecksumReader(req)
+	resp, err := c.sendRequest("UploadPart", bucketName, objectKey, req, option)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := verifyUpload(bucketName, objectKey, checksumReader, resp.Header, true); err != nil {
+		return nil, err
+	}
+
 	uploadPartResponse := NewUploadPartResponse(resp.Header)
 
 	return uploadPartResponse, nil
 }
 
+// UploadPartCopy copies a range of an existing BOS Object as a part of BOS Object Multipart Upload.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#UploadPartCopy
+func (c *Client) UploadPartCopy(uploadPartCopyRequest UploadPartCopyRequest,
+	option *bce.SignOption) (*UploadPartCopyResponse, error) {
+
+	if err := validateBucketName("srcBucketName", uploadPartCopyRequest.SrcBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateBucketName("destBucketName", uploadPartCopyRequest.DestBucketName); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("srcKey", uploadPartCopyRequest.SrcKey); err != nil {
+		return nil, err
+	}
+
+	if err := validateObjectKey("destKey", uploadPartCopyRequest.DestKey); err != nil {
+		return nil, err
+	}
+
+	if uploadPartCopyRequest.PartNumber < MIN_PART_NUMBER || uploadPartCopyRequest.PartNumber > MAX_PART_NUMBER {
+		return nil, bce.NewValidationError("partNumber", fmt.Sprintf("The part number %d should be from %d to %d.",
+			uploadPartCopyRequest.PartNumber, MIN_PART_NUMBER, MAX_PART_NUMBER))
+	}
+
+	if err := uploadPartCopyRequest.ServerSideEncryption.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uploadPartCopyRequest.SourceServerSideEncryption.validate(); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"partNumber": strconv.Itoa(uploadPartCopyRequest.PartNumber),
+		"uploadId":   uploadPartCopyRequest.UploadId,
+	}
+
+	req, err := bce.NewRequest("PUT", c.GetURL(uploadPartCopyRequest.DestBucketName,
+		uploadPartCopyRequest.DestKey, params), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	option = bce.CheckSignOption(option)
+
+	source := copySource(uploadPartCopyRequest.SrcBucketName, uploadPartCopyRequest.SrcKey,
+		uploadPartCopyRequest.SrcVersionId)
+
+	option.AddHeader("x-bce-copy-source", source)
+	uploadPartCopyRequest.mergeToSignOption(option)
+
+	resp, err := c.sendRequest("UploadPartCopy", uploadPartCopyRequest.DestBucketName,
+		uploadPartCopyRequest.DestKey, req, option)
+
+	if err = checkConditionResponse(resp, err); err != nil {
+		return nil, err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var uploadPartCopyResponse *UploadPartCopyResponse
+	err = json.Unmarshal(bodyContent, &uploadPartCopyResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return uploadPartCopyResponse, nil
+}
+
 // CompleteMultipartUpload is the last step for BOS Object Multipart Upload.
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#CompleteMultipartUpload.E6.8E.A5.E5.8F.A3
@@ -843,8 +2019,10 @@ func (c *Client) CompleteMultipartUpload(completeMultipartUploadRequest Complete
 
 	bucketName := completeMultipartUploadRequest.BucketName
 	objectKey := completeMultipartUploadRequest.ObjectKey
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
 
 	completeMultipartUploadRequest.sort()
 	params := map[string]string{"uploadId": completeMultipartUploadRequest.UploadId}
@@ -860,7 +2038,7 @@ func (c *Client) CompleteMultipartUpload(completeMultipartUploadRequest Complete
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("CompleteMultipartUpload", bucketName, objectKey, req, option)
 
 	if err != nil {
 		return nil, err
@@ -880,6 +2058,8 @@ func (c *Client) CompleteMultipartUpload(completeMultipartUploadRequest Complete
 		return nil, err
 	}
 
+	completeMultipartUploadResponse.ContentCrc64 = resp.Header.Get("x-bce-content-crc64ecma")
+
 	return completeMultipartUploadResponse, nil
 }
 
@@ -889,60 +2069,97 @@ func (c *Client) CompleteMultipartUpload(completeMultipartUploadRequest Complete
 func (c *Client) MultipartUploadFromFile(bucketName, objectKey, filePath string,
 	partSize int64) (*CompleteMultipartUploadResponse, error) {
 
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	return c.multipartUploadFromFile(bucketName, objectKey, filePath, partSize, nil)
+}
+
+func (c *Client) multipartUploadFromFile(bucketName, objectKey, filePath string,
+	partSize int64, metadata *ObjectMetadata) (*CompleteMultipartUploadResponse, error) {
 
-	initiateMultipartUploadRequest := InitiateMultipartUploadRequest{
-		BucketName: bucketName,
-		ObjectKey:  objectKey,
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
 	}
 
-	initiateMultipartUploadResponse, err := c.InitiateMultipartUpload(initiateMultipartUploadRequest, nil)
+	file, err := os.Open(filePath)
 
 	if err != nil {
 		return nil, err
 	}
 
-	uploadId := initiateMultipartUploadResponse.UploadId
-
-	file, err := os.Open(filePath)
 	defer file.Close()
 
+	fileInfo, err := file.Stat()
+
 	if err != nil {
 		return nil, err
 	}
 
-	fileInfo, err := file.Stat()
+	initiateMultipartUploadRequest := InitiateMultipartUploadRequest{
+		BucketName:     bucketName,
+		ObjectKey:      objectKey,
+		ObjectMetadata: metadata,
+	}
+
+	initiateMultipartUploadResponse, err := c.InitiateMultipartUpload(initiateMultipartUploadRequest, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
+	uploadId := initiateMultipartUploadResponse.UploadId
+
 	var totalSize int64 = fileInfo.Size()
 	var partCount int = int(math.Ceil(float64(totalSize) / float64(partSize)))
 
-	parts := make([]PartSummary, 0, partCount)
+	parts := make([]PartSummary, partCount)
+	partCrc64s := make([]uint64, partCount)
 
 	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var uploadError error
+
+	// setUploadError keeps the first error, no more parts are uploaded after it
+	setUploadError := func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if uploadError == nil {
+			uploadError = err
+		}
+	}
 
 	for i := 0; i < partCount; i++ {
+		mutex.Lock()
+		failed := uploadError != nil
+		mutex.Unlock()
+
+		if failed {
+			break
+		}
+
 		var skipBytes int64 = partSize * int64(i)
 		var size int64 = int64(math.Min(float64(totalSize-skipBytes), float64(partSize)))
 
 		tempFile, err := util.TempFile(nil, "", "")
 
 		if err != nil {
-			return nil, err
+			setUploadError(err)
+			break
 		}
 
-		limitReader := io.LimitReader(file, size)
-		_, err = io.Copy(tempFile, limitReader)
+		// the checksum is computed while the part is copied, so it is not read again before uploading
+		checksumReader := util.NewChecksumReader(io.LimitReader(file, size))
+		_, err = io.Copy(tempFile, checksumReader)
 
 		if err != nil {
-			return nil, err
+			tempFile.Close()
+			os.Remove(tempFile.Name())
+			setUploadError(err)
+			break
 		}
 
 		partNumber := i + 1
+		partCrc64s[i] = checksumReader.CRC64()
+		parts[i] = PartSummary{PartNumber: partNumber}
 
 		uploadPartRequest := UploadPartRequest{
 			BucketName: bucketName,
@@ -953,9 +2170,14 @@ func (c *Client) MultipartUploadFromFile(bucketName, objectKey, filePath string,
 			PartData:   tempFile,
 		}
 
-		waitGroup.Add(1)
+		uploadPartOption := &bce.SignOption{}
+		uploadPartOption.AddHeader("Content-MD5", checksumReader.ContentMD5())
+
+		if c.Checksum {
+			uploadPartOption.AddHeader("x-bce-content-sha256", checksumReader.SHA256())
+		}
 
-		parts = append(parts, PartSummary{PartNumber: partNumber})
+		waitGroup.Add(1)
 
 		go func(partNumber int, f *os.File) {
 			defer func() {
@@ -964,42 +2186,59 @@ func (c *Client) MultipartUploadFromFile(bucketName, objectKey, filePath string,
 				waitGroup.Done()
 			}()
 
-			uploadPartResponse, uploadPartError := c.UploadPart(uploadPartRequest, nil)
+			uploadPartResponse, uploadPartError := c.UploadPart(uploadPartRequest, uploadPartOption)
 			uploadPartRequest.PartData = nil
 
 			if uploadPartError != nil {
-				panic(uploadPartError)
+				setUploadError(uploadPartError)
+				return
 			}
 
+			mutex.Lock()
 			parts[partNumber-1].ETag = uploadPartResponse.GetETag()
+			mutex.Unlock()
 		}(partNumber, tempFile)
 	}
 
+	// the in-flight parts are waited for, so their temp files are removed before returning
 	waitGroup.Wait()
-	waitGroup.Add(1)
 
-	var completeMultipartUploadResponse *CompleteMultipartUploadResponse
-
-	go func() {
-		defer waitGroup.Done()
-
-		completeMultipartUploadRequest := CompleteMultipartUploadRequest{
+	if uploadError != nil {
+		c.AbortMultipartUpload(AbortMultipartUploadRequest{
 			BucketName: bucketName,
 			ObjectKey:  objectKey,
 			UploadId:   uploadId,
-			Parts:      parts,
-		}
+		}, nil)
 
-		completeResponse, completeError := c.CompleteMultipartUpload(completeMultipartUploadRequest, nil)
+		return nil, uploadError
+	}
 
-		if completeError != nil {
-			panic(completeError)
-		}
+	completeMultipartUploadRequest := CompleteMultipartUploadRequest{
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+		UploadId:   uploadId,
+		Parts:      parts,
+	}
 
-		completeMultipartUploadResponse = completeResponse
-	}()
+	completeMultipartUploadResponse, err := c.CompleteMultipartUpload(completeMultipartUploadRequest, nil)
 
-	waitGroup.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	// the CRC64 of the whole BOS Object is combined from the CRC64 of all parts
+	var crc64 uint64
+
+	for i, partCrc64 := range partCrc64s {
+		crc64 = util.CRC64Combine(crc64, partCrc64, int64(math.Min(float64(totalSize-partSize*int64(i)),
+			float64(partSize))))
+	}
+
+	err = verifyCRC64(bucketName, objectKey, completeMultipartUploadResponse.ContentCrc64, crc64)
+
+	if err != nil {
+		return nil, err
+	}
 
 	return completeMultipartUploadResponse, nil
 }
@@ -1012,8 +2251,10 @@ func (c *Client) AbortMultipartUpload(abortMultipartUploadRequest AbortMultipart
 
 	bucketName := abortMultipartUploadRequest.BucketName
 	objectKey := abortMultipartUploadRequest.ObjectKey
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return err
+	}
 
 	params := map[string]string{"uploadId": abortMultipartUploadRequest.UploadId}
 
@@ -1023,7 +2264,7 @@ func (c *Client) AbortMultipartUpload(abortMultipartUploadRequest AbortMultipart
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("AbortMultipartUpload", bucketName, objectKey, req, option)
 
 	return err
 }
@@ -1047,6 +2288,10 @@ func (c *Client) ListParts(bucketName, objectKey, uploadId string,
 func (c *Client) ListPartsFromRequest(listPartsRequest ListPartsRequest,
 	option *bce.SignOption) (*ListPartsResponse, error) {
 
+	if err := checkObject(listPartsRequest.BucketName, listPartsRequest.ObjectKey); err != nil {
+		return nil, err
+	}
+
 	bucketName := listPartsRequest.BucketName
 	objectKey := listPartsRequest.ObjectKey
 
@@ -1066,7 +2311,7 @@ func (c *Client) ListPartsFromRequest(listPartsRequest ListPartsRequest,
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("ListParts", bucketName, objectKey, req, option)
 
 	if err != nil {
 		return nil, err
@@ -1104,6 +2349,10 @@ func (c *Client) ListMultipartUploads(bucketName string,
 func (c *Client) ListMultipartUploadsFromRequest(listMultipartUploadsRequest ListMultipartUploadsRequest,
 	option *bce.SignOption) (*ListMultipartUploadsResponse, error) {
 
+	if err := checkBucketName(listMultipartUploadsRequest.BucketName); err != nil {
+		return nil, err
+	}
+
 	bucketName := listMultipartUploadsRequest.BucketName
 
 	params := map[string]string{"uploads": ""}
@@ -1116,6 +2365,10 @@ func (c *Client) ListMultipartUploadsFromRequest(listMultipartUploadsRequest Lis
 		params["keyMarker"] = listMultipartUploadsRequest.KeyMarker
 	}
 
+	if listMultipartUploadsRequest.UploadIdMarker != "" {
+		params["uploadIdMarker"] = listMultipartUploadsRequest.UploadIdMarker
+	}
+
 	if listMultipartUploadsRequest.Prefix != "" {
 		params["prefix"] = listMultipartUploadsRequest.Prefix
 	}
@@ -1130,7 +2383,7 @@ func (c *Client) ListMultipartUploadsFromRequest(listMultipartUploadsRequest Lis
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("ListMultipartUploads", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -1157,6 +2410,10 @@ func (c *Client) ListMultipartUploadsFromRequest(listMultipartUploadsRequest Lis
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketCors.E6.8E.A5.E5.8F.A3
 func (c *Client) GetBucketCors(bucketName string, option *bce.SignOption) (*BucketCors, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
 	params := map[string]string{"cors": ""}
 	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
 
@@ -1164,7 +2421,7 @@ func (c *Client) GetBucketCors(bucketName string, option *bce.SignOption) (*Buck
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("GetBucketCors", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -1191,6 +2448,10 @@ func (c *Client) GetBucketCors(bucketName string, option *bce.SignOption) (*Buck
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketCors.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketCors(bucketName string, bucketCors BucketCors, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	byteArray, err := util.ToJson(bucketCors, "corsConfiguration")
 
 	if err != nil {
@@ -1204,7 +2465,7 @@ func (c *Client) SetBucketCors(bucketName string, bucketCors BucketCors, option
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("SetBucketCors", bucketName, "", req, option)
 
 	return err
 }
@@ -1213,6 +2474,10 @@ func (c *Client) SetBucketCors(bucketName string, bucketCors BucketCors, option
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketCors.E6.8E.A5.E5.8F.A3
 func (c *Client) DeleteBucketCors(bucketName string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	params := map[string]string{"cors": ""}
 	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, "", params), nil)
 
@@ -1220,7 +2485,7 @@ func (c *Client) DeleteBucketCors(bucketName string, option *bce.SignOption) err
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("DeleteBucketCors", bucketName, "", req, option)
 
 	return err
 }
@@ -1231,8 +2496,9 @@ func (c *Client) DeleteBucketCors(bucketName string, option *bce.SignOption) err
 func (c *Client) OptionsObject(bucketName, objectKey, origin, accessControlRequestMethod,
 	accessControlRequestHeaders string) (*bce.Response, error) {
 
-	checkBucketName(bucketName)
-	checkObjectKey(objectKey)
+	if err := checkObject(bucketName, objectKey); err != nil {
+		return nil, err
+	}
 
 	req, err := bce.NewRequest("OPTIONS", c.GetURL(bucketName, objectKey, nil), nil)
 
@@ -1245,13 +2511,21 @@ func (c *Client) OptionsObject(bucketName, objectKey, origin, accessControlReque
 	option.AddHeader("Access-Control-Request-Method", accessControlRequestMethod)
 	option.AddHeader("Access-Control-Request-Headers", accessControlRequestHeaders)
 
-	return c.SendRequest(req, option)
+	return c.sendRequest("OptionsObject", bucketName, objectKey, req, option)
 }
 
 // SetBucketLogging sets the log settings of a BOS Bucket.
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketLogging.E6.8E.A5.E5.8F.A3
 func (c *Client) SetBucketLogging(bucketName, targetBucket, targetPrefix string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
+	if err := validateBucketName("targetBucket", targetBucket); err != nil {
+		return err
+	}
+
 	params := map[string]string{"logging": ""}
 	body, err := util.ToJson(map[string]string{
 		"targetBucket": targetBucket,
@@ -1268,7 +2542,7 @@ func (c *Client) SetBucketLogging(bucketName, targetBucket, targetPrefix string,
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("SetBucketLogging", bucketName, "", req, option)
 
 	return err
 }
@@ -1277,6 +2551,10 @@ func (c *Client) SetBucketLogging(bucketName, targetBucket, targetPrefix string,
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketLogging
 func (c *Client) GetBucketLogging(bucketName string, option *bce.SignOption) (*BucketLogging, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
 	params := map[string]string{"logging": ""}
 	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
 
@@ -1284,7 +2562,7 @@ func (c *Client) GetBucketLogging(bucketName string, option *bce.SignOption) (*B
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("GetBucketLogging", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -1311,6 +2589,10 @@ func (c *Client) GetBucketLogging(bucketName string, option *bce.SignOption) (*B
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketLogging
 func (c *Client) DeleteBucketLogging(bucketName string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	params := map[string]string{"logging": ""}
 	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, "", params), nil)
 
@@ -1318,15 +2600,22 @@ func (c *Client) DeleteBucketLogging(bucketName string, option *bce.SignOption)
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("DeleteBucketLogging", bucketName, "", req, option)
 
 	return err
 }
 
 // SetBucketlifecycle set lifecycle configuration of a bucket
 //
+// The configuration is sent as is, call bos.BucketLifecycle.Validate before it to check the rules
+// and the conflicts between them, or build the rules by bos.BucketLifecycleRuleBuilder.
+//
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketlifecycle
 func (c *Client) SetBucketLifecycle(bucketName string, bucketLifecycle BucketLifecycle, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	byteArray, err := util.ToJson(bucketLifecycle, "rule")
 
 	if err != nil {
@@ -1340,7 +2629,7 @@ func (c *Client) SetBucketLifecycle(bucketName string, bucketLifecycle BucketLif
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("SetBucketLifecycle", bucketName, "", req, option)
 
 	return err
 }
@@ -1349,6 +2638,10 @@ func (c *Client) SetBucketLifecycle(bucketName string, bucketLifecycle BucketLif
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetLifeCycle
 func (c *Client) GetBucketLifecycle(bucketName string, option *bce.SignOption) (*BucketLifecycle, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
 	params := map[string]string{"lifecycle": ""}
 	req, err := bce.NewRequest("GET", c.GetURL(bucketName, "", params), nil)
 
@@ -1356,7 +2649,7 @@ func (c *Client) GetBucketLifecycle(bucketName string, option *bce.SignOption) (
 		return nil, err
 	}
 
-	resp, err := c.SendRequest(req, option)
+	resp, err := c.sendRequest("GetBucketLifecycle", bucketName, "", req, option)
 
 	if err != nil {
 		return nil, err
@@ -1383,6 +2676,10 @@ func (c *Client) GetBucketLifecycle(bucketName string, option *bce.SignOption) (
 //
 // For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteLifeCycle
 func (c *Client) DeleteBucketLifecycle(bucketName string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	params := map[string]string{"lifecycle": ""}
 	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, "", params), nil)
 
@@ -1390,12 +2687,400 @@ func (c *Client) DeleteBucketLifecycle(bucketName string, option *bce.SignOption
 		return err
 	}
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest("DeleteBucketLifecycle", bucketName, "", req, option)
+
+	return err
+}
+
+// SetBucketEncryption sets the default server side encryption of a BOS Bucket,
+// the algorithm is SERVER_SIDE_ENCRYPTION_AES256 or SERVER_SIDE_ENCRYPTION_SM4.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketEncryption
+func (c *Client) SetBucketEncryption(bucketName string, bucketEncryption BucketEncryption,
+	option *bce.SignOption) error {
+
+	if algorithm := bucketEncryption.EncryptionAlgorithm; algorithm != SERVER_SIDE_ENCRYPTION_AES256 &&
+		algorithm != SERVER_SIDE_ENCRYPTION_SM4 {
+
+		return bce.NewValidationError("encryptionAlgorithm", fmt.Sprintf(
+			"The encryption algorithm %s is not supported, it should be %s or %s.",
+			algorithm, SERVER_SIDE_ENCRYPTION_AES256, SERVER_SIDE_ENCRYPTION_SM4))
+	}
+
+	return c.putSubResource("SetBucketEncryption", bucketName, "", "encryption", bucketEncryption, option)
+}
+
+// GetBucketEncryption gets the default server side encryption of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketEncryption
+func (c *Client) GetBucketEncryption(bucketName string, option *bce.SignOption) (*BucketEncryption, error) {
+	var bucketEncryption *BucketEncryption
+
+	if err := c.getSubResource("GetBucketEncryption", bucketName, "", "encryption",
+		&bucketEncryption, option); err != nil {
+
+		return nil, err
+	}
+
+	return bucketEncryption, nil
+}
+
+// DeleteBucketEncryption deletes the default server side encryption of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketEncryption
+func (c *Client) DeleteBucketEncryption(bucketName string, option *bce.SignOption) error {
+	return c.deleteSubResource("DeleteBucketEncryption", bucketName, "", "encryption", option)
+}
+
+// SetBucketReferer sets the referer whitelist and blacklist of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketReferer
+func (c *Client) SetBucketReferer(bucketName string, bucketReferer BucketReferer, option *bce.SignOption) error {
+	for _, referer := range append(bucketReferer.WhiteList, bucketReferer.BlackList...) {
+		if strings.TrimSpace(referer) == "" {
+			return bce.NewValidationError("referer", "The referer should not be empty.")
+		}
+	}
+
+	return c.putSubResource("SetBucketReferer", bucketName, "", "referer", bucketReferer, option)
+}
+
+// GetBucketReferer gets the referer whitelist and blacklist of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketReferer
+func (c *Client) GetBucketReferer(bucketName string, option *bce.SignOption) (*BucketReferer, error) {
+	var bucketReferer *BucketReferer
+
+	if err := c.getSubResource("GetBucketReferer", bucketName, "", "referer", &bucketReferer, option); err != nil {
+		return nil, err
+	}
+
+	return bucketReferer, nil
+}
+
+// DeleteBucketReferer deletes the referer whitelist and blacklist of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketReferer
+func (c *Client) DeleteBucketReferer(bucketName string, option *bce.SignOption) error {
+	return c.deleteSubResource("DeleteBucketReferer", bucketName, "", "referer", option)
+}
+
+// SetBucketStaticWebsite sets the static website hosting settings of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketStaticWebsite
+func (c *Client) SetBucketStaticWebsite(bucketName string, bucketStaticWebsite BucketStaticWebsite,
+	option *bce.SignOption) error {
+
+	if bucketStaticWebsite.Index == "" {
+		return bce.NewValidationError("index", "The index document should not be empty.")
+	}
+
+	for _, document := range []string{bucketStaticWebsite.Index, bucketStaticWebsite.NotFound} {
+		if strings.Contains(document, "/") {
+			return bce.NewValidationError("document", fmt.Sprintf("The document %s should not contain '/'.",
+				document))
+		}
+	}
+
+	return c.putSubResource("SetBucketStaticWebsite", bucketName, "", "website", bucketStaticWebsite, option)
+}
+
+// GetBucketStaticWebsite gets the static website hosting settings of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketStaticWebsite
+func (c *Client) GetBucketStaticWebsite(bucketName string, option *bce.SignOption) (*BucketStaticWebsite, error) {
+	var bucketStaticWebsite *BucketStaticWebsite
+
+	if err := c.getSubResource("GetBucketStaticWebsite", bucketName, "", "website",
+		&bucketStaticWebsite, option); err != nil {
+
+		return nil, err
+	}
+
+	return bucketStaticWebsite, nil
+}
+
+// DeleteBucketStaticWebsite deletes the static website hosting settings of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketStaticWebsite
+func (c *Client) DeleteBucketStaticWebsite(bucketName string, option *bce.SignOption) error {
+	return c.deleteSubResource("DeleteBucketStaticWebsite", bucketName, "", "website", option)
+}
+
+// PutBucketReplication sets the cross region replication configuration of a BOS Bucket.
+//
+// The configuration is checked by bos.BucketReplication.Validate before sending request.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketReplication
+func (c *Client) PutBucketReplication(bucketName string, bucketReplication BucketReplication,
+	option *bce.SignOption) error {
+
+	if err := bucketReplication.Validate(bucketName); err != nil {
+		return err
+	}
+
+	return c.putSubResource("PutBucketReplication", bucketName, "", "replication", bucketReplication, option)
+}
+
+// GetBucketReplication gets the cross region replication configuration of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketReplication
+func (c *Client) GetBucketReplication(bucketName string, option *bce.SignOption) (*BucketReplication, error) {
+	var bucketReplication *BucketReplication
+
+	if err := c.getSubResource("GetBucketReplication", bucketName, "", "replication",
+		&bucketReplication, option); err != nil {
+
+		return nil, err
+	}
+
+	return bucketReplication, nil
+}
+
+// DeleteBucketReplication deletes the cross region replication configuration of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#DeleteBucketReplication
+func (c *Client) DeleteBucketReplication(bucketName string, option *bce.SignOption) error {
+	return c.deleteSubResource("DeleteBucketReplication", bucketName, "", "replication", option)
+}
+
+// GetBucketReplicationProgress gets the progress of cross region replication of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketReplicationProgress
+func (c *Client) GetBucketReplicationProgress(bucketName string,
+	option *bce.SignOption) (*BucketReplicationProgress, error) {
+
+	var bucketReplicationProgress *BucketReplicationProgress
+
+	if err := c.getSubResource("GetBucketReplicationProgress", bucketName, "", "replicationProgress",
+		&bucketReplicationProgress, option); err != nil {
+
+		return nil, err
+	}
+
+	return bucketReplicationProgress, nil
+}
+
+// PutBucketVersioning enables or suspends versioning of a BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketVersioning
+func (c *Client) PutBucketVersioning(bucketName, status string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
+	if status != VERSIONING_ENABLED && status != VERSIONING_SUSPENDED {
+		return bce.NewValidationError("status", fmt.Sprintf("The versioning status %s is not supported.", status))
+	}
+
+	return c.putSubResource("PutBucketVersioning", bucketName, "", "versioning", BucketVersioning{Status: status}, option)
+}
+
+// GetBucketVersioning gets the versioning status of a BOS Bucket,
+// Status is empty if versioning has never been enabled.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#GetBucketVersioning
+func (c *Client) GetBucketVersioning(bucketName string, option *bce.SignOption) (*BucketVersioning, error) {
+	if err := checkBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
+	var bucketVersioning BucketVersioning
+
+	if err := c.getSubResource("GetBucketVersioning", bucketName, "", "versioning",
+		&bucketVersioning, option); err != nil {
+
+		return nil, err
+	}
+
+	return &bucketVersioning, nil
+}
+
+// ListObjectVersions gets a list of versions of BOS Object for the specified BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#ListObjectVersions
+func (c *Client) ListObjectVersions(bucketName string, option *bce.SignOption) (*ListObjectVersionsResponse, error) {
+	return c.ListObjectVersionsFromRequest(ListObjectVersionsRequest{BucketName: bucketName}, option)
+}
+
+// ListObjectVersionsFromRequest gets a list of versions of BOS Object for the specified BOS Bucket.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#ListObjectVersions
+func (c *Client) ListObjectVersionsFromRequest(listObjectVersionsRequest ListObjectVersionsRequest,
+	option *bce.SignOption) (*ListObjectVersionsResponse, error) {
+
+	if err := checkBucketName(listObjectVersionsRequest.BucketName); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"versions": ""}
+
+	if listObjectVersionsRequest.Delimiter != "" {
+		params["delimiter"] = listObjectVersionsRequest.Delimiter
+	}
+
+	if listObjectVersionsRequest.Prefix != "" {
+		params["prefix"] = listObjectVersionsRequest.Prefix
+	}
+
+	if listObjectVersionsRequest.KeyMarker != "" {
+		params["keyMarker"] = listObjectVersionsRequest.KeyMarker
+	}
+
+	if listObjectVersionsRequest.VersionIdMarker != "" {
+		params["versionIdMarker"] = listObjectVersionsRequest.VersionIdMarker
+	}
+
+	if listObjectVersionsRequest.MaxKeys > 0 {
+		params["maxKeys"] = strconv.Itoa(listObjectVersionsRequest.MaxKeys)
+	}
+
+	req, err := bce.NewRequest("GET", c.GetURL(listObjectVersionsRequest.BucketName, "", params), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest("ListObjectVersions", listObjectVersionsRequest.BucketName, "", req, option)
+
+	if err != nil {
+		return nil, err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var listObjectVersionsResponse *ListObjectVersionsResponse
+	err = json.Unmarshal(bodyContent, &listObjectVersionsResponse)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listObjectVersionsResponse, nil
+}
+
+// ListObjectVersionsPages lists all versions of BOS Object page by page from the markers of the request,
+// f is called with each page, and the listing stops if f returns false.
+func (c *Client) ListObjectVersionsPages(listObjectVersionsRequest ListObjectVersionsRequest,
+	f func(*ListObjectVersionsResponse) bool, option *bce.SignOption) error {
+
+	for {
+		listObjectVersionsResponse, err := c.ListObjectVersionsFromRequest(listObjectVersionsRequest,
+			cloneSignOption(option))
+
+		if err != nil {
+			return err
+		}
+
+		if !f(listObjectVersionsResponse) || !listObjectVersionsResponse.IsTruncated ||
+			listObjectVersionsResponse.NextKeyMarker == "" {
+			return nil
+		}
+
+		listObjectVersionsRequest.KeyMarker = listObjectVersionsResponse.NextKeyMarker
+		listObjectVersionsRequest.VersionIdMarker = listObjectVersionsResponse.NextVersionIdMarker
+	}
+}
+
+// sendRequest sends the request of a BOS operation, the operation is the name of the method of bos.Client,
+// such as "PutObject", it is reported to the bce.Metrics of bce.Config.
+func (c *Client) sendRequest(operation, bucketName, objectKey string, req *bce.Request,
+	option *bce.SignOption) (*bce.Response, error) {
+
+	return c.SendOperationRequest(&bce.Operation{
+		Service: "bos",
+		Name:    operation,
+		Bucket:  bucketName,
+		Key:     objectKey,
+	}, req, option)
+}
+
+// putSubResource sends the JSON of configuration to a sub resource of BOS Bucket, such as `?encryption`,
+// or a sub resource of BOS Object if objectKey is not empty.
+func (c *Client) putSubResource(operation, bucketName, objectKey, subResource string,
+	configuration interface{}, option *bce.SignOption) error {
+
+	if err := checkBucketOrObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	byteArray, err := util.ToJson(configuration)
+
+	if err != nil {
+		return err
+	}
+
+	params := map[string]string{subResource: ""}
+	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, objectKey, params), bytes.NewReader(byteArray))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest(operation, bucketName, objectKey, req, option)
+
+	return err
+}
+
+// getSubResource gets a sub resource of BOS Bucket or BOS Object, and unmarshals the JSON response into result.
+func (c *Client) getSubResource(operation, bucketName, objectKey, subResource string,
+	result interface{}, option *bce.SignOption) error {
+
+	if err := checkBucketOrObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	params := map[string]string{subResource: ""}
+	req, err := bce.NewRequest("GET", c.GetURL(bucketName, objectKey, params), nil)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.sendRequest(operation, bucketName, objectKey, req, option)
+
+	if err != nil {
+		return err
+	}
+
+	bodyContent, err := resp.GetBodyContent()
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bodyContent, result)
+}
+
+// deleteSubResource deletes a sub resource of BOS Bucket or BOS Object.
+func (c *Client) deleteSubResource(operation, bucketName, objectKey, subResource string,
+	option *bce.SignOption) error {
+
+	if err := checkBucketOrObject(bucketName, objectKey); err != nil {
+		return err
+	}
+
+	params := map[string]string{subResource: ""}
+	req, err := bce.NewRequest("DELETE", c.GetURL(bucketName, objectKey, params), nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest(operation, bucketName, objectKey, req, option)
 
 	return err
 }
 
-func (c *Client) setBucketAclFromString(bucketName, acl string, option *bce.SignOption) error {
+func (c *Client) setBucketAclFromString(operation, bucketName, acl string, option *bce.SignOption) error {
+	if err := checkBucketName(bucketName); err != nil {
+		return err
+	}
+
 	params := map[string]string{"acl": ""}
 	req, err := bce.NewRequest("PUT", c.GetURL(bucketName, "", params), nil)
 
@@ -1408,7 +3093,7 @@ func (c *Client) setBucketAclFromString(bucketName, acl string, option *bce.Sign
 	headers := map[string]string{"x-bce-acl": acl}
 	option.AddHeaders(headers)
 
-	_, err = c.SendRequest(req, option)
+	_, err = c.sendRequest(operation, bucketName, "", req, option)
 
 	return err
 }
@@ -1,6 +1,8 @@
 package bos
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,76 +16,153 @@ import (
 	"time"
 
 	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
 	"github.com/guoyao/baidubce-sdk-go/util"
 )
 
 var credentials = bce.NewCredentials(os.Getenv("BAIDU_BCE_AK"), os.Getenv("BAIDU_BCE_SK"))
 
+// fakeServer serves the tests in memory if BAIDU_BCE_AK is not specified.
+var fakeServer *bostest.Server
+
 //var bceConfig = bce.NewConfig(credentials)
-var bceConfig = &bce.Config{
-	Credentials: credentials,
-	Checksum:    true,
-	Region:      os.Getenv("BOS_REGION"),
-}
+var bceConfig = newTestConfig()
 var bosConfig = NewConfig(bceConfig)
 var bosClient = NewClient(bosConfig)
 
-func TestCheckBucketName(t *testing.T) {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(err)
-
-			defer func() {
-				if err := recover(); err != nil {
-					fmt.Println(err)
-
-					defer func() {
-						if err := recover(); err != nil {
-							fmt.Println(err)
-							t.Error(util.FormatTest("checkBucketName", "panic", "no panic"))
-						}
-					}()
+func newTestConfig() *bce.Config {
+	config := &bce.Config{
+		Credentials: credentials,
+		Checksum:    true,
+		Region:      os.Getenv("BOS_REGION"),
+	}
 
-					checkBucketName("bucket-0")
-				}
-			}()
+	if credentials.AccessKeyID == "" {
+		fakeServer = bostest.NewServer()
+		fakeConfig := fakeServer.NewConfig()
+		config.Credentials = fakeConfig.Credentials
+		config.ProxyHost = fakeConfig.ProxyHost
+		config.ProxyPort = fakeConfig.ProxyPort
+	}
+
+	return config
+}
+
+// newTestHTTPClient returns a http.Client which sends requests to the same BOS as bosClient.
+func newTestHTTPClient() *http.Client {
+	if fakeServer != nil {
+		return fakeServer.HTTPClient()
+	}
+
+	return &http.Client{}
+}
+
+// newFakeClient returns a client of a new fake server with the bucket created if bucketName is not empty,
+// the server should be closed by the caller.
+func newFakeClient(t *testing.T, bucketName string) (*bostest.Server, *Client) {
+	server := bostest.NewServer()
+	config := server.NewConfig()
+	config.RetryPolicy = bce.NewDefaultRetryPolicy(3, time.Millisecond)
+	client := NewClient(NewConfig(config))
 
-			checkBucketName("/bucket-0")
-			t.Error(util.FormatTest("checkBucketName", "no panic", "panic"))
+	if bucketName != "" {
+		if err := client.CreateBucket(bucketName, nil); err != nil {
+			server.Close()
+			t.Fatal(err)
 		}
-	}()
+	}
 
-	checkBucketName("")
-	t.Error(util.FormatTest("checkBucketName", "no panic", "panic"))
+	return server, client
+}
+
+func TestCheckBucketName(t *testing.T) {
+	valid := []string{"bucket-0", "abc", "0-a-0", strings.Repeat("a", MAX_BUCKET_NAME_LENGTH)}
+
+	for _, bucketName := range valid {
+		if err := checkBucketName(bucketName); err != nil {
+			t.Error(util.FormatTest("checkBucketName "+bucketName, err.Error(), "nil"))
+		}
+	}
+
+	invalid := []string{"", "/bucket-0", "ab", strings.Repeat("a", MAX_BUCKET_NAME_LENGTH+1), "Bucket-0",
+		"bucket_0", "bucket.0", "-bucket-0", "bucket-0-", "桶-bucket"}
+
+	for _, bucketName := range invalid {
+		err := checkBucketName(bucketName)
+
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "bucketName" {
+			t.Error(util.FormatTest("checkBucketName "+bucketName, fmt.Sprintf("%v", err), "bce.ValidationError"))
+		}
+	}
 }
 
 func TestCheckObjectKey(t *testing.T) {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(err)
-
-			defer func() {
-				if err := recover(); err != nil {
-					fmt.Println(err)
-
-					defer func() {
-						if err := recover(); err != nil {
-							fmt.Println(err)
-							t.Error(util.FormatTest("checkObjectKey", "panic", "no panic"))
-						}
-					}()
+	valid := []string{"object-0", "a/b/c.txt", "对象.txt", strings.Repeat("a", MAX_OBJECT_KEY_LENGTH)}
 
-					checkObjectKey("object-0")
-				}
-			}()
+	for _, objectKey := range valid {
+		if err := checkObjectKey(objectKey); err != nil {
+			t.Error(util.FormatTest("checkObjectKey "+objectKey, err.Error(), "nil"))
+		}
+	}
+
+	invalid := []string{"", "/object-0", "\\object-0", strings.Repeat("a", MAX_OBJECT_KEY_LENGTH+1), "object-\xff"}
 
-			checkObjectKey("/object-0")
-			t.Error(util.FormatTest("checkObjectKey", "no panic", "panic"))
+	for _, objectKey := range invalid {
+		err := checkObjectKey(objectKey)
+
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "objectKey" {
+			t.Error(util.FormatTest("checkObjectKey "+objectKey, fmt.Sprintf("%v", err), "bce.ValidationError"))
 		}
-	}()
+	}
+
+	err := validateObjectKey("srcKey", "")
+
+	if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "srcKey" {
+		t.Error(util.FormatTest("validateObjectKey", fmt.Sprintf("%v", err), "srcKey"))
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	_, err := bosClient.GetObject("Invalid_Bucket", "object-0", nil)
+
+	if _, ok := err.(*bce.ValidationError); !ok {
+		t.Error(util.FormatTest("GetObject", fmt.Sprintf("%v", err), "bce.ValidationError"))
+	}
+
+	if err := bosClient.SetBucketCors("bucket-0/", BucketCors{}, nil); err == nil {
+		t.Error(util.FormatTest("SetBucketCors", "nil", "bce.ValidationError"))
+	}
+
+	if err := bosClient.SetBucketReferer("-bucket", BucketReferer{}, nil); err == nil {
+		t.Error(util.FormatTest("SetBucketReferer", "nil", "bce.ValidationError"))
+	}
+
+	if err := bosClient.SetBucketLogging("bucket-0", "", "logs/", nil); err == nil {
+		t.Error(util.FormatTest("SetBucketLogging", "nil", "bce.ValidationError"))
+	}
 
-	checkObjectKey("")
-	t.Error(util.FormatTest("checkObjectKey", "no panic", "panic"))
+	_, uploadPartCopyError := bosClient.UploadPartCopy(UploadPartCopyRequest{SrcBucketName: "bucket-0",
+		SrcKey: "object-0", DestBucketName: "bucket-0", DestKey: "object-1", UploadId: "upload-0"}, nil)
+	_, syncError := bosClient.SyncToBucket(SyncRequest{LocalDir: ".", BucketName: "bucket-0", CompareMode: "hash"})
+
+	// the invalid arguments which are checked before sending request
+	fields := map[string]error{
+		"days":                bosClient.RestoreObject("bucket-0", "object-0", 31, "", nil),
+		"tier":                bosClient.RestoreObject("bucket-0", "object-0", 1, "Bulk", nil),
+		"storageClass":        bosClient.SetObjectStorageClass("bucket-0", "object-0", "COLD_STANDARD", nil),
+		"partNumber":          uploadPartCopyError,
+		"encryptionAlgorithm": bosClient.SetBucketEncryption("bucket-0", BucketEncryption{EncryptionAlgorithm: "DES"}, nil),
+		"status":              bosClient.PutBucketVersioning("bucket-0", "Disabled", nil),
+		"id":                  (&BucketReplication{}).Validate("bucket-0"),
+		"algorithm":           NewServerSideEncryption("AES128").validate(),
+		"compareMode":         syncError,
+	}
+
+	for field, err := range fields {
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != field {
+			t.Error(util.FormatTest(field, fmt.Sprintf("%v", err), "bce.ValidationError"))
+		}
+	}
 }
 
 func TestGetURL(t *testing.T) {
@@ -243,6 +322,64 @@ func TestSetBucketAcl(t *testing.T) {
 	})
 }
 
+func TestSetObjectCannedAcl(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-set-object-canned-acl-"
+	method := "SetObjectCannedAcl"
+	objectKey := "put-object-from-string.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		metadata := &ObjectMetadata{Acl: CannedAccessControlList["Private"]}
+		_, err := bosClient.PutObject(bucketName, objectKey, "Hello World", metadata, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if err := bosClient.SetObjectCannedAcl(bucketName, objectKey,
+			CannedAccessControlList["PublicRead"], nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if objectAcl, err := bosClient.GetObjectAcl(bucketName, objectKey, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if len(objectAcl.AccessControlList) == 0 {
+			t.Error(util.FormatTest(method, "empty access control list", "public-read grant"))
+		}
+	})
+}
+
+func TestSetObjectAcl(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-set-object-acl-"
+	method := "SetObjectAcl"
+	objectKey := "put-object-from-string.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		_, err := bosClient.PutObject(bucketName, objectKey, "Hello World", nil, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		objectAcl := BucketAcl{
+			AccessControlList: []Grant{
+				Grant{
+					Grantee: []BucketGrantee{
+						BucketGrantee{Id: "another-user-id"},
+					},
+					Permission: []string{"READ"},
+				},
+			},
+		}
+
+		if err := bosClient.SetObjectAcl(bucketName, objectKey, objectAcl, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if gotObjectAcl, err := bosClient.GetObjectAcl(bucketName, objectKey, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if len(gotObjectAcl.AccessControlList) != 1 {
+			t.Error(util.FormatTest(method, strconv.Itoa(len(gotObjectAcl.AccessControlList)), "1"))
+		} else if err := bosClient.DeleteObjectAcl(bucketName, objectKey, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		}
+	})
+}
+
 func TestPutObject(t *testing.T) {
 	bucketNamePrefix := "baidubce-sdk-go-test-for-put-object-"
 	method := "PutObject"
@@ -303,16 +440,10 @@ func TestPutObject(t *testing.T) {
 		}
 	})
 
-	defer func() {
-		if err := recover(); err == nil {
-			t.Error(util.FormatTest(method, "panic", "nil"))
-		}
-	}()
-
 	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
 		_, err := bosClient.PutObject(bucketName, objectKey, 1, nil, nil)
-		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "data" {
+			t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "bce.ValidationError"))
 		}
 	})
 }
@@ -392,6 +523,198 @@ func TestDeleteMultipleObjects(t *testing.T) {
 	}
 }
 
+func TestDeletePrefix(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-delete-prefix-"
+	method := "DeletePrefix"
+	objectKeys := []string{"logs/a.txt", "logs/b.txt", "logs/2017/c.txt", "data/d.txt"}
+
+	around(t, method, bucketNamePrefix, objectKeys, func(bucketName string) {
+		for _, objectKey := range objectKeys {
+			if _, err := bosClient.PutObject(bucketName, objectKey, "Hello World", nil, nil); err != nil {
+				t.Error(util.FormatTest(method, err.Error(), "nil"))
+				return
+			}
+		}
+
+		result, err := bosClient.DeletePrefix(bucketName, "logs/", &DeletePrefixOptions{DryRun: true})
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if len(result.Keys) != 3 {
+			t.Error(util.FormatTest(method, strconv.Itoa(len(result.Keys)), "3"))
+		}
+
+		result, err = bosClient.DeletePrefix(bucketName, "logs/", &DeletePrefixOptions{
+			BatchSize:             2,
+			Parallel:              2,
+			AbortMultipartUploads: true,
+		})
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if result.Err() != nil {
+			t.Error(util.FormatTest(method, result.Err().Error(), "nil"))
+		} else if result.Deleted != 3 {
+			t.Error(util.FormatTest(method, strconv.Itoa(result.Deleted), "3"))
+		} else {
+			listObjectsResponse, err := bosClient.ListObjects(bucketName, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest(method, err.Error(), "nil"))
+			} else if length := len(listObjectsResponse.Contents); length != 1 {
+				t.Error(util.FormatTest(method, strconv.Itoa(length), "1"))
+			}
+		}
+	})
+}
+
+func TestDeletePrefixWithFakeServer(t *testing.T) {
+	method := "DeletePrefix"
+	bucketName := "delete-prefix"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	// the retry of DeletePrefix is tested, not the retry of bce.Client
+	client.RetryPolicy = bce.NewDefaultRetryPolicy(0, time.Millisecond)
+
+	objectKeys := []string{"logs/a.txt", "logs/b.txt", "logs/c.txt", "logs/d.txt", "logs/e.txt", "data/f.txt"}
+
+	for _, objectKey := range objectKeys {
+		if _, err := client.PutObject(bucketName, objectKey, "Hello World", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := client.DeletePrefix(bucketName, "", nil)
+
+	if _, ok := err.(*bce.ValidationError); !ok {
+		t.Error(util.FormatTest(method+" empty prefix", fmt.Sprintf("%v", err), "*bce.ValidationError"))
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "POST",
+		Param:      "delete",
+		Nth:        2,
+		Times:      1,
+		StatusCode: http.StatusServiceUnavailable,
+		Code:       "ServiceUnavailable",
+	})
+
+	server.ClearRequests()
+	result, err := client.DeletePrefix(bucketName, "logs/", &DeletePrefixOptions{BatchSize: 2})
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if result.Err() != nil {
+		t.Error(util.FormatTest(method, result.Err().Error(), "nil"))
+	} else if result.Deleted != 5 {
+		t.Error(util.FormatTest(method, strconv.Itoa(result.Deleted), "5"))
+	}
+
+	deleteRequests := 0
+
+	for _, request := range server.Requests() {
+		if _, ok := request.Query["delete"]; ok {
+			deleteRequests++
+		}
+	}
+
+	// 3 batches and the retry of the failed one
+	if deleteRequests != 4 {
+		t.Error(util.FormatTest(method+" delete requests", strconv.Itoa(deleteRequests), "4"))
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "POST",
+		Param:      "delete",
+		StatusCode: http.StatusInternalServerError,
+		Code:       "InternalError",
+	})
+
+	result, err = client.DeletePrefix(bucketName, "", &DeletePrefixOptions{AllowEmptyPrefix: true})
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if len(result.Errors) != 1 || result.Errors[0].Key != "data/f.txt" ||
+		result.Errors[0].Code != "InternalError" {
+
+		t.Error(util.FormatTest(method+" errors", fmt.Sprintf("%v", result.Errors), "data/f.txt InternalError"))
+	}
+}
+
+func TestAbortMultipartUploadsUnderPrefix(t *testing.T) {
+	method := "abortMultipartUploadsUnderPrefix"
+	bucketName := "abort-multipart-uploads"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	// a key with several uploads makes the pages end in the middle of its uploads
+	for _, objectKey := range []string{"logs/a", "logs/b", "logs/b", "logs/b", "logs/c", "data/d"} {
+		_, err := client.InitiateMultipartUpload(InitiateMultipartUploadRequest{
+			BucketName: bucketName,
+			ObjectKey:  objectKey,
+		}, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listMultipartUploadsRequest := ListMultipartUploadsRequest{
+		BucketName: bucketName,
+		Prefix:     "logs/",
+		MaxUploads: 2,
+	}
+	uploadIds := make(map[string]bool)
+
+	for {
+		listMultipartUploadsResponse, err := client.ListMultipartUploadsFromRequest(listMultipartUploadsRequest, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, upload := range listMultipartUploadsResponse.Uploads {
+			uploadIds[upload.UploadId] = true
+		}
+
+		if !listMultipartUploadsResponse.IsTruncated {
+			break
+		}
+
+		listMultipartUploadsRequest.KeyMarker = listMultipartUploadsResponse.NextKeyMarker
+		listMultipartUploadsRequest.UploadIdMarker = listMultipartUploadsResponse.NextUploadIdMarker
+	}
+
+	if len(uploadIds) != 5 {
+		t.Error(util.FormatTest("ListMultipartUploads pages", strconv.Itoa(len(uploadIds)), "5"))
+	}
+
+	aborted, err := client.abortMultipartUploadsUnderPrefix(bucketName, "logs/", true)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if aborted != 5 {
+		t.Error(util.FormatTest(method+" dry run", strconv.Itoa(aborted), "5"))
+	}
+
+	aborted, err = client.abortMultipartUploadsUnderPrefix(bucketName, "logs/", false)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if aborted != 5 {
+		t.Error(util.FormatTest(method, strconv.Itoa(aborted), "5"))
+	}
+
+	listMultipartUploadsResponse, err := client.ListMultipartUploads(bucketName, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(listMultipartUploadsResponse.Uploads); length != 1 {
+		t.Error(util.FormatTest(method+" remaining uploads", strconv.Itoa(length), "1"))
+	}
+}
+
 func TestListObjects(t *testing.T) {
 	bucketNamePrefix := "baidubce-sdk-go-test-for-list-objects-"
 	method := "ListObjects"
@@ -413,92 +736,782 @@ func TestListObjects(t *testing.T) {
 	})
 }
 
-func TestListObjectsFromRequest(t *testing.T) {
-	bucketNamePrefix := "baidubce-sdk-go-test-for-list-objects-from-request-"
-	method := "ListObjectsFromRequest"
-	str := "Hello World 你好"
+func TestListObjectsFromRequest(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-list-objects-from-request-"
+	method := "ListObjectsFromRequest"
+	str := "Hello World 你好"
+
+	objects := []string{
+		"hello.txt",
+		"examples/list-objects-from-request/put-object-from-string.txt",
+		"examples/list-objects-from-request/put-object-from-string-2.txt",
+		"examples2/list-objects-from-request/put-object-from-string-3.txt",
+		"examples2/list-objects-from-request/put-object-from-string.txt",
+		"examples3/list-objects-from-request/put-object-from-string-2.txt",
+		"examples3/list-objects-from-request/put-object-from-string-3.txt",
+	}
+	objectsLength := len(objects)
+
+	around(t, method, bucketNamePrefix, objects, func(bucketName string) {
+		for _, objectKey := range objects {
+			_, err := bosClient.PutObject(bucketName, objectKey, str, nil, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest(method, err.Error(), "nil"))
+				return
+			}
+		}
+
+		listObjectsRequest := ListObjectsRequest{
+			BucketName: bucketName,
+		}
+		listObjectsResponse, err := bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if length := len(listObjectsResponse.Contents); length != objectsLength {
+			t.Error(util.FormatTest(method, strconv.Itoa(length), strconv.Itoa(objectsLength)))
+		}
+
+		listObjectsRequest = ListObjectsRequest{
+			BucketName: bucketName,
+			Delimiter:  "/",
+		}
+		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else {
+			if length := len(listObjectsResponse.Contents); length != 1 {
+				t.Error(util.FormatTest(method, strconv.Itoa(length), "1"))
+			}
+
+			if length := len(listObjectsResponse.CommonPrefixes); length != 3 {
+				t.Error(util.FormatTest(method, strconv.Itoa(length), "3"))
+			}
+		}
+
+		listObjectsRequest = ListObjectsRequest{
+			BucketName: bucketName,
+			Prefix:     "examples2",
+		}
+		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if length := len(listObjectsResponse.Contents); length != 2 {
+			t.Error(util.FormatTest(method, strconv.Itoa(length), "2"))
+		}
+
+		listObjectsRequest = ListObjectsRequest{
+			BucketName: bucketName,
+			MaxKeys:    2,
+		}
+		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if length := len(listObjectsResponse.Contents); length != 2 {
+			t.Error(util.FormatTest(method, strconv.Itoa(length), "2"))
+		}
+
+		listObjectsRequest = ListObjectsRequest{
+			BucketName: bucketName,
+			Marker:     "examples2",
+		}
+		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if length := len(listObjectsResponse.Contents); length != 5 {
+			t.Error(util.FormatTest(method, strconv.Itoa(length), "5"))
+		}
+	})
+}
+
+func TestSetObjectStorageClass(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-set-object-storage-class-"
+	method := "SetObjectStorageClass"
+	objectKey := "set-object-storage-class.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		metadata := &ObjectMetadata{ContentType: "text/plain"}
+		metadata.AddUserMetadata("x-bce-meta-name", "hello")
+
+		_, err := bosClient.PutObject(bucketName, objectKey, "Hello World", metadata, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		if err := bosClient.SetObjectStorageClass(bucketName, objectKey, "HOT", nil); err == nil {
+			t.Error(util.FormatTest(method, "nil", "error"))
+		}
+
+		err = bosClient.SetObjectStorageClass(bucketName, objectKey, STORAGE_CLASS_COLD, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		objectMetadata, err := bosClient.GetObjectMetadata(bucketName, objectKey, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if objectMetadata.StorageClass != STORAGE_CLASS_COLD {
+			t.Error(util.FormatTest(method, objectMetadata.StorageClass, STORAGE_CLASS_COLD))
+		} else if name := util.GetMapValue(objectMetadata.UserMetadata, "x-bce-meta-name", true); name != "hello" {
+			t.Error(util.FormatTest(method, name, "hello"))
+		}
+	})
+}
+
+func TestSetObjectStorageClassByMultipartCopy(t *testing.T) {
+	method := "SetObjectStorageClass"
+	bucketName := "set-object-storage-class"
+	objectKey := "large.bin"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	// the object is larger than the part size, so it is copied to itself by 3 parts
+	defer func(partSize int64) { selfCopyPartSize = partSize }(selfCopyPartSize)
+	selfCopyPartSize = MIN_PART_SIZE
+
+	data := bytes.Repeat([]byte("0123456789"), int(MIN_PART_SIZE*2/10+1))
+	metadata := &ObjectMetadata{ContentType: "application/octet-stream"}
+	metadata.AddUserMetadata("x-bce-meta-name", "large")
+
+	if _, err := client.PutObject(bucketName, objectKey, data, metadata, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server.ClearRequests()
+
+	if err := client.SetObjectStorageClass(bucketName, objectKey, STORAGE_CLASS_COLD, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	partCopies := 0
+
+	for _, request := range server.Requests() {
+		if request.Method == "PUT" && request.Query.Get("partNumber") != "" {
+			partCopies++
+		}
+	}
+
+	if partCopies != 3 {
+		t.Error(util.FormatTest(method+" part copies", strconv.Itoa(partCopies), "3"))
+	}
+
+	objectMetadata, err := client.GetObjectMetadata(bucketName, objectKey, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if objectMetadata.StorageClass != STORAGE_CLASS_COLD {
+		t.Error(util.FormatTest(method, objectMetadata.StorageClass, STORAGE_CLASS_COLD))
+	}
+
+	if objectMetadata.ContentLength != int64(len(data)) {
+		t.Error(util.FormatTest(method, strconv.FormatInt(objectMetadata.ContentLength, 10),
+			strconv.Itoa(len(data))))
+	}
+
+	if name := util.GetMapValue(objectMetadata.UserMetadata, "x-bce-meta-name", true); name != "large" {
+		t.Error(util.FormatTest(method, name, "large"))
+	}
+
+	object, err := client.GetObject(bucketName, objectKey, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadAll(object.ObjectContent)
+	object.ObjectContent.Close()
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if !bytes.Equal(content, data) {
+		t.Error(util.FormatTest(method, "content changed", "content kept"))
+	}
+
+	listMultipartUploadsResponse, err := client.ListMultipartUploads(bucketName, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(listMultipartUploadsResponse.Uploads); length != 0 {
+		t.Error(util.FormatTest(method+" uploads left", strconv.Itoa(length), "0"))
+	}
+}
+
+func TestUpdateObjectMetadataChangedWhileCopying(t *testing.T) {
+	method := "UpdateObjectMetadata"
+	bucketName := "update-object-metadata-changed"
+	objectKey := "large.bin"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	defer func(partSize int64) { selfCopyPartSize = partSize }(selfCopyPartSize)
+	selfCopyPartSize = MIN_PART_SIZE
+
+	data := bytes.Repeat([]byte("0123456789"), int(MIN_PART_SIZE*2/10+1))
+
+	if _, err := client.PutObject(bucketName, objectKey, data, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the responses of part copies are delayed, and the object is overwritten after all parts are copied
+	server.AddRule(&bostest.Rule{Method: "PUT", Param: "partNumber", Delay: 200 * time.Millisecond})
+	server.ClearRequests()
+	overwritten := make(chan error, 1)
+
+	go func() {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			partCopies := 0
+
+			for _, request := range server.Requests() {
+				if request.Query.Get("partNumber") != "" {
+					partCopies++
+				}
+			}
+
+			if partCopies == 3 {
+				_, err := client.PutObject(bucketName, objectKey, "changed", nil, nil)
+				overwritten <- err
+				return
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+
+		overwritten <- errors.New("the parts are not copied")
+	}()
+
+	err := client.UpdateObjectMetadata(bucketName, objectKey, func(objectMetadata *ObjectMetadata) {
+		objectMetadata.AddUserMetadata("x-bce-meta-name", "large")
+	}, nil)
+
+	if err := <-overwritten; err != nil {
+		t.Fatal(err)
+	}
+
+	if conditionError, ok := err.(*ConditionError); !ok || !conditionError.PreconditionFailed() {
+		t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "*bos.ConditionError of 412"))
+	}
+
+	object, err := client.GetObject(bucketName, objectKey, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadAll(object.ObjectContent)
+	object.ObjectContent.Close()
+
+	if err != nil || string(content) != "changed" {
+		t.Error(util.FormatTest(method, fmt.Sprintf("%d bytes", len(content)), "changed"))
+	}
+
+	listMultipartUploadsResponse, err := client.ListMultipartUploads(bucketName, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(listMultipartUploadsResponse.Uploads); length != 0 {
+		t.Error(util.FormatTest(method+" uploads left", strconv.Itoa(length), "0"))
+	}
+}
+
+func TestUpdateObjectMetadataWithCustomerKey(t *testing.T) {
+	method := "UpdateObjectMetadataFromRequest"
+	bucketName := "update-object-metadata-sse-c"
+	objectKey := "secret.txt"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	sse := NewCustomerKeyEncryption(bytes.Repeat([]byte("k"), SSE_CUSTOMER_KEY_SIZE))
+	metadata := &ObjectMetadata{ContentType: "text/plain", ServerSideEncryption: sse}
+
+	if _, err := client.PutObject(bucketName, objectKey, "Hello World", metadata, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(objectMetadata *ObjectMetadata) {
+		objectMetadata.AddUserMetadata("x-bce-meta-name", "secret")
+	}
+
+	if err := client.UpdateObjectMetadata(bucketName, objectKey, update, nil); err == nil {
+		t.Error(util.FormatTest(method+" without key", "nil", "error"))
+	}
+
+	err := client.UpdateObjectMetadataFromRequest(UpdateObjectMetadataRequest{
+		BucketName:           bucketName,
+		ObjectKey:            objectKey,
+		Update:               update,
+		ServerSideEncryption: sse,
+	}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objectMetadata, err := client.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+		BucketName:           bucketName,
+		ObjectKey:            objectKey,
+		ServerSideEncryption: sse,
+	}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := util.GetMapValue(objectMetadata.UserMetadata, "x-bce-meta-name", true); name != "secret" {
+		t.Error(util.FormatTest(method, name, "secret"))
+	}
+
+	if objectMetadata.ServerSideEncryption == nil ||
+		objectMetadata.ServerSideEncryption.CustomerKeyMD5 != sse.customerKeyMD5() {
+
+		t.Error(util.FormatTest(method, fmt.Sprintf("%+v", objectMetadata.ServerSideEncryption), sse.customerKeyMD5()))
+	}
+
+	// the customer key is required if the metadata shows the object is encrypted with it
+	err = client.replaceObjectMetadata(bucketName, objectKey, objectMetadata, nil, update, nil)
+
+	if _, ok := err.(*bce.ValidationError); !ok {
+		t.Error(util.FormatTest("replaceObjectMetadata", fmt.Sprintf("%v", err), "*bce.ValidationError"))
+	}
+}
+
+func TestUpdateObjectMetadata(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-update-object-metadata-"
+	method := "UpdateObjectMetadata"
+	objectKey := "update-object-metadata.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		metadata := &ObjectMetadata{ContentType: "text/plain"}
+		metadata.AddUserMetadata("x-bce-meta-name", "hello")
+		metadata.AddUserMetadata("x-bce-meta-tmp", "tmp")
+
+		_, err := bosClient.PutObject(bucketName, objectKey, "Hello World", metadata, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		err = bosClient.UpdateObjectMetadata(bucketName, objectKey, func(objectMetadata *ObjectMetadata) {
+			objectMetadata.AddUserMetadata("x-bce-meta-name", "world")
+			delete(objectMetadata.UserMetadata, "x-bce-meta-tmp")
+		}, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		objectMetadata, err := bosClient.GetObjectMetadata(bucketName, objectKey, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if objectMetadata.ContentType != "text/plain" {
+			t.Error(util.FormatTest(method, objectMetadata.ContentType, "text/plain"))
+		} else if name := util.GetMapValue(objectMetadata.UserMetadata, "x-bce-meta-name", true); name != "world" {
+			t.Error(util.FormatTest(method, name, "world"))
+		} else if len(objectMetadata.UserMetadata) != 1 {
+			t.Error(util.FormatTest(method, strconv.Itoa(len(objectMetadata.UserMetadata)), "1"))
+		}
+	})
+}
+
+func TestPutObjectTagging(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-object-tagging-"
+	method := "PutObjectTagging"
+	objectKey := "object-tagging.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		_, err := bosClient.PutObject(bucketName, objectKey, "Hello World", nil, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		tagging := NewObjectTagging(map[string]string{"project": "sdk"})
+
+		if err := bosClient.PutObjectTagging(bucketName, objectKey, tagging, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		objectTagging, err := bosClient.GetObjectTagging(bucketName, objectKey, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("GetObjectTagging", err.Error(), "nil"))
+		} else if project := objectTagging.Tags()["project"]; project != "sdk" {
+			t.Error(util.FormatTest("GetObjectTagging", project, "sdk"))
+		}
+
+		if err := bosClient.DeleteObjectTagging(bucketName, objectKey, nil); err != nil {
+			t.Error(util.FormatTest("DeleteObjectTagging", err.Error(), "nil"))
+		}
+	})
+}
+
+func TestBucketVersioning(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-bucket-versioning-"
+	method := "PutBucketVersioning"
+	objectKey := "bucket-versioning.txt"
+
+	if err := bosClient.PutBucketVersioning("bucket-0", "on", nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+
+	around(t, method, bucketNamePrefix, "", func(bucketName string) {
+		if err := bosClient.PutBucketVersioning(bucketName, VERSIONING_ENABLED, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		bucketVersioning, err := bosClient.GetBucketVersioning(bucketName, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("GetBucketVersioning", err.Error(), "nil"))
+		} else if bucketVersioning.Status != VERSIONING_ENABLED {
+			t.Error(util.FormatTest("GetBucketVersioning", bucketVersioning.Status, VERSIONING_ENABLED))
+		}
+
+		var versionIds []string
+
+		for _, str := range []string{"v1", "v2"} {
+			putObjectResponse, err := bosClient.PutObject(bucketName, objectKey, str, nil, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest(method, err.Error(), "nil"))
+				return
+			}
+
+			versionIds = append(versionIds, putObjectResponse.GetVersionId())
+		}
+
+		object, err := bosClient.GetObjectFromRequest(GetObjectRequest{
+			BucketName: bucketName,
+			ObjectKey:  objectKey,
+			VersionId:  versionIds[0],
+		}, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("GetObjectFromRequest", err.Error(), "nil"))
+		} else {
+			byteArray, _ := ioutil.ReadAll(object.ObjectContent)
+			object.ObjectContent.Close()
+
+			if string(byteArray) != "v1" {
+				t.Error(util.FormatTest("GetObjectFromRequest", string(byteArray), "v1"))
+			}
+		}
+
+		var count int
+
+		err = bosClient.ListObjectVersionsPages(ListObjectVersionsRequest{BucketName: bucketName, MaxKeys: 1},
+			func(listObjectVersionsResponse *ListObjectVersionsResponse) bool {
+				count += len(listObjectVersionsResponse.Versions)
+				return true
+			}, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("ListObjectVersionsPages", err.Error(), "nil"))
+		} else if count != 2 {
+			t.Error(util.FormatTest("ListObjectVersionsPages", strconv.Itoa(count), "2"))
+		}
+
+		for _, versionId := range versionIds {
+			err := bosClient.DeleteObjectFromRequest(DeleteObjectRequest{
+				BucketName: bucketName,
+				ObjectKey:  objectKey,
+				VersionId:  versionId,
+			}, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest("DeleteObjectFromRequest", err.Error(), "nil"))
+			}
+		}
+	})
+}
+
+func TestListObjectVersions(t *testing.T) {
+	method := "ListObjectVersions"
+	bucketName := "list-object-versions"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	if err := client.PutBucketVersioning(bucketName, VERSIONING_ENABLED, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, item := range [][2]string{{"a.txt", "v1"}, {"a.txt", "v2"}, {"b.txt", "b"}, {"logs/c.txt", "c"}} {
+		if _, err := client.PutObject(bucketName, item[0], item[1], nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.DeleteObject(bucketName, "a.txt", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	listObjectVersionsResponse, err := client.ListObjectVersions(bucketName, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	versions := listObjectVersionsResponse.Versions
+	summaries := make([]string, 0, len(versions))
+
+	for _, version := range versions {
+		summaries = append(summaries, fmt.Sprintf("%s:%d:%t:%t", version.Key, version.Size, version.IsLatest,
+			version.IsDeleteMarker))
+	}
+
+	// the delete marker is the latest version of a.txt, and the versions of a key are listed from the latest
+	expected := "a.txt:0:true:true,a.txt:2:false:false,a.txt:2:false:false,b.txt:1:true:false,logs/c.txt:1:true:false"
+
+	if strings.Join(summaries, ",") != expected {
+		t.Error(util.FormatTest(method, strings.Join(summaries, ","), expected))
+	}
+
+	listObjectVersionsResponse, err = client.ListObjectVersionsFromRequest(ListObjectVersionsRequest{
+		BucketName: bucketName,
+		Delimiter:  "/",
+	}, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(listObjectVersionsResponse.Versions); length != 4 ||
+		len(listObjectVersionsResponse.CommonPrefixes) != 1 ||
+		listObjectVersionsResponse.CommonPrefixes[0]["prefix"] != "logs/" {
+
+		t.Error(util.FormatTest(method+" delimiter", strconv.Itoa(length), "4 versions and logs/"))
+	}
+
+	listObjectVersionsResponse, err = client.ListObjectVersionsFromRequest(ListObjectVersionsRequest{
+		BucketName: bucketName,
+		Prefix:     "logs/",
+	}, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(listObjectVersionsResponse.Versions); length != 1 {
+		t.Error(util.FormatTest(method+" prefix", strconv.Itoa(length), "1"))
+	}
+
+	// the pages end in the middle of the versions of a.txt, so the version id marker is required
+	versionIds := make(map[string]bool)
+	pages := 0
+
+	err = client.ListObjectVersionsPages(ListObjectVersionsRequest{BucketName: bucketName, MaxKeys: 2},
+		func(listObjectVersionsResponse *ListObjectVersionsResponse) bool {
+			pages++
+
+			for _, version := range listObjectVersionsResponse.Versions {
+				versionIds[version.VersionId] = true
+			}
+
+			return true
+		}, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest("ListObjectVersionsPages", err.Error(), "nil"))
+	} else if len(versionIds) != 5 || pages != 3 {
+		t.Error(util.FormatTest("ListObjectVersionsPages", fmt.Sprintf("%d versions in %d pages",
+			len(versionIds), pages), "5 versions in 3 pages"))
+	}
+
+	pages = 0
+
+	err = client.ListObjectVersionsPages(ListObjectVersionsRequest{BucketName: bucketName, MaxKeys: 2},
+		func(listObjectVersionsResponse *ListObjectVersionsResponse) bool {
+			pages++
+			return false
+		}, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest("ListObjectVersionsPages", err.Error(), "nil"))
+	} else if pages != 1 {
+		t.Error(util.FormatTest("ListObjectVersionsPages stopped", strconv.Itoa(pages), "1"))
+	}
+
+	if _, err := client.ListObjectVersions("no-such-bucket", nil); err == nil {
+		t.Error(util.FormatTest(method+" no such bucket", "nil", "error"))
+	}
+}
+
+func TestConditionalGetObject(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-conditional-get-object-"
+	method := "GetObjectFromRequest"
+	objectKey := "conditional-get-object.txt"
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		putObjectResponse, err := bosClient.PutObject(bucketName, objectKey, "Hello World", nil, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		getObjectRequest := GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+		getObjectRequest.IfNoneMatch = putObjectResponse.GetETag()
+
+		if _, err := bosClient.GetObjectFromRequest(getObjectRequest, nil); !IsNotModified(err) {
+			t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "ConditionError of 304"))
+		}
+
+		_, err = bosClient.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+			BucketName:       bucketName,
+			ObjectKey:        objectKey,
+			ObjectConditions: ObjectConditions{IfMatch: "not-exists"},
+		}, nil)
+
+		if !IsPreconditionFailed(err) {
+			t.Error(util.FormatTest("GetObjectMetadataFromRequest", fmt.Sprintf("%v", err), "ConditionError of 412"))
+		}
+
+		_, err = bosClient.PutObjectFromRequest(PutObjectRequest{
+			BucketName:       bucketName,
+			ObjectKey:        objectKey,
+			Data:             "Hello",
+			ObjectConditions: ObjectConditions{IfMatch: putObjectResponse.GetETag()},
+		}, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest("PutObjectFromRequest", err.Error(), "nil"))
+		}
+	})
+}
+
+func TestEncryptionClient(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-encryption-client-"
+	method := "EncryptionClient"
+	objectKey := "encryption-client.txt"
+	str := strings.Repeat("Hello World 你好 ", 100)
+
+	around(t, method, bucketNamePrefix, objectKey, func(bucketName string) {
+		for _, algorithm := range []string{ENCRYPTION_ALGORITHM_AES_GCM, ENCRYPTION_ALGORITHM_AES_CTR} {
+			encryptionClient := newTestEncryptionClient(t, algorithm)
+
+			if _, err := encryptionClient.PutObject(bucketName, objectKey, str, nil, nil); err != nil {
+				t.Error(util.FormatTest(method+" "+algorithm, err.Error(), "nil"))
+				continue
+			}
+
+			object, err := bosClient.GetObject(bucketName, objectKey, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest(method+" "+algorithm, err.Error(), "nil"))
+				continue
+			}
+
+			byteArray, _ := ioutil.ReadAll(object.ObjectContent)
+			object.ObjectContent.Close()
+
+			if string(byteArray) == str {
+				t.Error(util.FormatTest(method+" "+algorithm, "plaintext", "ciphertext"))
+			}
+
+			getObjectRequest := GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+			getObjectRequest.SetRange(21, 530)
+			object, err = encryptionClient.GetObjectFromRequest(getObjectRequest, nil)
+
+			if err != nil {
+				t.Error(util.FormatTest(method+" "+algorithm, err.Error(), "nil"))
+				continue
+			}
+
+			byteArray, _ = ioutil.ReadAll(object.ObjectContent)
+			object.ObjectContent.Close()
+
+			if string(byteArray) != str[21:531] {
+				t.Error(util.FormatTest(method+" "+algorithm, string(byteArray), str[21:531]))
+			}
+		}
+	})
+}
+
+func TestRestoreObject(t *testing.T) {
+	method := "RestoreObject"
 
-	objects := []string{
-		"hello.txt",
-		"examples/list-objects-from-request/put-object-from-string.txt",
-		"examples/list-objects-from-request/put-object-from-string-2.txt",
-		"examples2/list-objects-from-request/put-object-from-string-3.txt",
-		"examples2/list-objects-from-request/put-object-from-string.txt",
-		"examples3/list-objects-from-request/put-object-from-string-2.txt",
-		"examples3/list-objects-from-request/put-object-from-string-3.txt",
+	if err := bosClient.RestoreObject("bucket-0", "object", 31, "", nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
 	}
-	objectsLength := len(objects)
 
-	around(t, method, bucketNamePrefix, objects, func(bucketName string) {
-		for _, objectKey := range objects {
-			_, err := bosClient.PutObject(bucketName, objectKey, str, nil, nil)
+	if err := bosClient.RestoreObject("bucket-0", "object", 1, "Bulk", nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+}
 
-			if err != nil {
-				t.Error(util.FormatTest(method, err.Error(), "nil"))
-				return
-			}
-		}
+func TestRestoreObjectWithFakeServer(t *testing.T) {
+	method := "RestoreObject"
+	bucketName := "restore-object"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	for objectKey, storageClass := range map[string]string{"archive.txt": STORAGE_CLASS_ARCHIVE,
+		"standard.txt": STORAGE_CLASS_STANDARD} {
+
+		_, err := client.PutObject(bucketName, objectKey, "Hello World", &ObjectMetadata{StorageClass: storageClass}, nil)
 
-		listObjectsRequest := ListObjectsRequest{
-			BucketName: bucketName,
-		}
-		listObjectsResponse, err := bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
 		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
-		} else if length := len(listObjectsResponse.Contents); length != objectsLength {
-			t.Error(util.FormatTest(method, strconv.Itoa(length), strconv.Itoa(objectsLength)))
+			t.Fatal(err)
 		}
+	}
 
-		listObjectsRequest = ListObjectsRequest{
-			BucketName: bucketName,
-			Delimiter:  "/",
-		}
-		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
-		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
-		} else {
-			if length := len(listObjectsResponse.Contents); length != 1 {
-				t.Error(util.FormatTest(method, strconv.Itoa(length), "1"))
-			}
+	err := client.SetObjectStorageClass(bucketName, "archive.txt", STORAGE_CLASS_COLD, nil)
 
-			if length := len(listObjectsResponse.CommonPrefixes); length != 3 {
-				t.Error(util.FormatTest(method, strconv.Itoa(length), "3"))
-			}
-		}
+	if err == nil || !strings.Contains(err.Error(), "restore") {
+		t.Error(util.FormatTest("SetObjectStorageClass archived", fmt.Sprintf("%v", err), "restore error"))
+	}
 
-		listObjectsRequest = ListObjectsRequest{
-			BucketName: bucketName,
-			Prefix:     "examples2",
-		}
-		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
-		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
-		} else if length := len(listObjectsResponse.Contents); length != 2 {
-			t.Error(util.FormatTest(method, strconv.Itoa(length), "2"))
-		}
+	failures := map[string]struct {
+		objectKey string
+		code      string
+	}{
+		"not archived": {"standard.txt", "InvalidObjectState"},
+		"not found":    {"missing.txt", "NoSuchKey"},
+	}
 
-		listObjectsRequest = ListObjectsRequest{
-			BucketName: bucketName,
-			MaxKeys:    2,
-		}
-		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
-		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
-		} else if length := len(listObjectsResponse.Contents); length != 2 {
-			t.Error(util.FormatTest(method, strconv.Itoa(length), "2"))
-		}
+	for name, expected := range failures {
+		err := client.RestoreObject(bucketName, expected.objectKey, 0, "", nil)
 
-		listObjectsRequest = ListObjectsRequest{
-			BucketName: bucketName,
-			Marker:     "examples2",
-		}
-		listObjectsResponse, err = bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
-		if err != nil {
-			t.Error(util.FormatTest(method, err.Error(), "nil"))
-		} else if length := len(listObjectsResponse.Contents); length != 5 {
-			t.Error(util.FormatTest(method, strconv.Itoa(length), "5"))
+		if bceError, ok := err.(*bce.Error); !ok || bceError.Code != expected.code {
+			t.Error(util.FormatTest(method+" "+name, fmt.Sprintf("%v", err), expected.code))
 		}
-	})
+	}
+
+	if err := client.RestoreObject(bucketName, "archive.txt", 3, RESTORE_TIER_STANDARD, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	request := server.Requests()[len(server.Requests())-1]
+
+	if days := request.Header.Get("x-bce-restore-days"); days != "3" {
+		t.Error(util.FormatTest(method+" days", days, "3"))
+	}
+
+	if tier := request.Header.Get("x-bce-restore-tier"); tier != RESTORE_TIER_STANDARD {
+		t.Error(util.FormatTest(method+" tier", tier, RESTORE_TIER_STANDARD))
+	}
+
+	objectMetadata, err := client.GetObjectMetadata(bucketName, "archive.txt", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !objectMetadata.IsRestored() {
+		t.Error(util.FormatTest(method+": IsRestored", "false", "true"))
+	}
+
+	if err := client.SetObjectStorageClass(bucketName, "archive.txt", STORAGE_CLASS_COLD, nil); err != nil {
+		t.Error(util.FormatTest("SetObjectStorageClass restored", err.Error(), "nil"))
+	}
 }
 
 func TestCopyObject(t *testing.T) {
@@ -588,6 +1601,87 @@ func TestCopyObjectFromRequest(t *testing.T) {
 	})
 }
 
+func TestMultipartCopyObjectFromRequest(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-multipart-copy-object-from-request-"
+	method := "MultipartCopyObjectFromRequest"
+	objectKey := "test-multipart-copy"
+	destKey := "test-multipart-copy-dest"
+
+	around(t, method, bucketNamePrefix, []string{objectKey, destKey}, func(bucketName string) {
+		file, err := util.TempFileWithSize(1024 * 1024 * 11)
+
+		defer func() {
+			if file != nil {
+				file.Close()
+				os.Remove(file.Name())
+			}
+		}()
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		metadata := &ObjectMetadata{ContentType: "video/mp4"}
+		metadata.AddUserMetadata("name", "master")
+		_, err = bosClient.PutObject(bucketName, objectKey, file, metadata, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		copyObjectRequest := CopyObjectRequest{
+			SrcBucketName:  bucketName,
+			SrcKey:         objectKey,
+			DestBucketName: bucketName,
+			DestKey:        destKey,
+		}
+
+		_, err = bosClient.MultipartCopyObjectFromRequest(copyObjectRequest, MIN_PART_SIZE, 2, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+			return
+		}
+
+		objectMetadata, err := bosClient.GetObjectMetadata(bucketName, destKey, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if objectMetadata.ContentLength != 1024*1024*11 {
+			t.Error(util.FormatTest(method, strconv.FormatInt(objectMetadata.ContentLength, 10), strconv.Itoa(1024*1024*11)))
+		} else if value := util.GetMapValue(objectMetadata.UserMetadata, "x-bce-meta-name", true); value != "master" {
+			t.Error(util.FormatTest(method, value, "master"))
+		}
+	})
+}
+
+func TestCloneSignOption(t *testing.T) {
+	if cloneSignOption(nil) != nil {
+		t.Error(util.FormatTest("cloneSignOption", "not nil", "nil"))
+	}
+
+	option := &bce.SignOption{
+		Credentials: bce.NewCredentials("ak", "sk"),
+		Headers:     map[string]string{"x-bce-security-token": "token"},
+	}
+	cloned := cloneSignOption(option)
+	cloned.AddHeader("x-bce-acl", "private")
+
+	if cloned.Credentials != option.Credentials {
+		t.Error(util.FormatTest("cloneSignOption", "different credentials", "same credentials"))
+	}
+
+	if cloned.Headers["x-bce-security-token"] != "token" {
+		t.Error(util.FormatTest("cloneSignOption", cloned.Headers["x-bce-security-token"], "token"))
+	}
+
+	if _, ok := option.Headers["x-bce-acl"]; ok {
+		t.Error(util.FormatTest("cloneSignOption", "headers shared", "headers copied"))
+	}
+}
+
 func TestGetObject(t *testing.T) {
 	bucketNamePrefix := "baidubce-sdk-go-test-for-get-object-"
 	method := "GetObject"
@@ -752,7 +1846,7 @@ func TestGeneratePresignedUrl(t *testing.T) {
 				if err != nil {
 					t.Error(util.FormatTest(method, err.Error(), "nil"))
 				} else {
-					httpClient := http.Client{}
+					httpClient := newTestHTTPClient()
 					res, err := httpClient.Do(req)
 
 					if err != nil {
@@ -832,13 +1926,11 @@ func TestAppendObject(t *testing.T) {
 									if err == nil {
 										t.Error(util.FormatTest(method, "nil", "error"))
 									} else {
-										defer func() {
-											if err := recover(); err == nil {
-												t.Error(util.FormatTest(method, "nil", "error"))
-											}
-										}()
-
 										_, err = bosClient.AppendObject(bucketName, objectKey, offset, 12, nil, nil)
+
+										if _, ok := err.(*bce.ValidationError); !ok {
+											t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "bce.ValidationError"))
+										}
 									}
 								}
 							}
@@ -1069,43 +2161,28 @@ func TestListParts(t *testing.T) {
 			t.Error(util.FormatTest(method, fmt.Sprintf("part count is %d", partCount), "part count should be 1"))
 		}
 
-		func() {
-			defer func() {
-				if err := recover(); err == nil {
-					t.Error(util.FormatTest(method, "nil", "error"))
-				}
-			}()
-
-			uploadPartRequest := UploadPartRequest{
-				BucketName: bucketName,
-				ObjectKey:  objectKey,
-				UploadId:   initiateMultipartUploadResponse.UploadId,
-				PartSize:   1024*1024*1024*5 + 1,
-				PartNumber: partCount + 1,
-				PartData:   nil,
-			}
+		uploadPartRequest := UploadPartRequest{
+			BucketName: bucketName,
+			ObjectKey:  objectKey,
+			UploadId:   initiateMultipartUploadResponse.UploadId,
+			PartSize:   1024*1024*1024*5 + 1,
+			PartNumber: partCount + 1,
+			PartData:   nil,
+		}
 
-			bosClient.UploadPart(uploadPartRequest, nil)
-		}()
+		_, err = bosClient.UploadPart(uploadPartRequest, nil)
 
-		func() {
-			defer func() {
-				if err := recover(); err == nil {
-					t.Error(util.FormatTest(method, "nil", "error"))
-				}
-			}()
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "partSize" {
+			t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "bce.ValidationError of partSize"))
+		}
 
-			uploadPartRequest := UploadPartRequest{
-				BucketName: bucketName,
-				ObjectKey:  objectKey,
-				UploadId:   initiateMultipartUploadResponse.UploadId,
-				PartSize:   1024 * 1024 * 1024 * 5,
-				PartNumber: MAX_PART_NUMBER + 1,
-				PartData:   nil,
-			}
+		uploadPartRequest.PartSize = 1024 * 1024 * 1024 * 5
+		uploadPartRequest.PartNumber = MAX_PART_NUMBER + 1
+		_, err = bosClient.UploadPart(uploadPartRequest, nil)
 
-			bosClient.UploadPart(uploadPartRequest, nil)
-		}()
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != "partNumber" {
+			t.Error(util.FormatTest(method, fmt.Sprintf("%v", err), "bce.ValidationError of partNumber"))
+		}
 	})
 }
 
@@ -1459,6 +2536,33 @@ func TestDeleteBucketLogging(t *testing.T) {
 	})
 }
 
+func TestSetBucketLifecycleWithFakeServer(t *testing.T) {
+	method := "SetBucketLifecycle"
+	bucketName := "set-bucket-lifecycle"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	// the conflict is rejected by Validate, but it is not checked by SetBucketLifecycle
+	bucketLifecycle := BucketLifecycle{
+		Rule: []BucketLifecycleItem{
+			NewBucketLifecycleRuleBuilder("1").Prefix(bucketName, "logs/").AfterDays(30).DeleteObject().rule,
+			NewBucketLifecycleRuleBuilder("2").Prefix(bucketName, "logs/").AfterDays(60).DeleteObject().rule,
+		},
+	}
+
+	if err := bucketLifecycle.Validate(bucketName); err == nil {
+		t.Error(util.FormatTest(method+": Validate", "nil", "error"))
+	}
+
+	if err := client.SetBucketLifecycle(bucketName, bucketLifecycle, nil); err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if bucketLifecycle, err := client.GetBucketLifecycle(bucketName, nil); err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if length := len(bucketLifecycle.Rule); length != 2 {
+		t.Error(util.FormatTest(method, strconv.Itoa(length), "2"))
+	}
+}
+
 func TestSetBucketLifecycle(t *testing.T) {
 	bucketNamePrefix := "baidubce-sdk-go-test-for-set-bucket-lifecycle-"
 	method := "SetBucketLifecycle"
@@ -1626,6 +2730,130 @@ func TestDeleteBucketLifecycle(t *testing.T) {
 	})
 }
 
+func TestBucketEncryption(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-bucket-encryption-"
+	method := "SetBucketEncryption"
+
+	if err := bosClient.SetBucketEncryption("bucket-0", BucketEncryption{EncryptionAlgorithm: "DES"}, nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+
+	around(t, method, bucketNamePrefix, "", func(bucketName string) {
+		bucketEncryption := BucketEncryption{EncryptionAlgorithm: SERVER_SIDE_ENCRYPTION_AES256}
+
+		if err := bosClient.SetBucketEncryption(bucketName, bucketEncryption, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if gotBucketEncryption, err := bosClient.GetBucketEncryption(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("GetBucketEncryption", err.Error(), "nil"))
+		} else if gotBucketEncryption.EncryptionAlgorithm != SERVER_SIDE_ENCRYPTION_AES256 {
+			t.Error(util.FormatTest("GetBucketEncryption", gotBucketEncryption.EncryptionAlgorithm, SERVER_SIDE_ENCRYPTION_AES256))
+		} else if err := bosClient.DeleteBucketEncryption(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("DeleteBucketEncryption", err.Error(), "nil"))
+		}
+	})
+}
+
+func TestBucketEncryptionWithFakeServer(t *testing.T) {
+	method := "SetBucketEncryption"
+	bucketName := "bucket-encryption"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	for _, algorithm := range []string{SERVER_SIDE_ENCRYPTION_AES256, SERVER_SIDE_ENCRYPTION_SM4} {
+		bucketEncryption := BucketEncryption{EncryptionAlgorithm: algorithm}
+
+		if err := client.SetBucketEncryption(bucketName, bucketEncryption, nil); err != nil {
+			t.Error(util.FormatTest(method+" "+algorithm, err.Error(), "nil"))
+		} else if gotBucketEncryption, err := client.GetBucketEncryption(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("GetBucketEncryption "+algorithm, err.Error(), "nil"))
+		} else if gotBucketEncryption.EncryptionAlgorithm != algorithm {
+			t.Error(util.FormatTest("GetBucketEncryption", gotBucketEncryption.EncryptionAlgorithm, algorithm))
+		}
+	}
+}
+
+func TestBucketReferer(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-bucket-referer-"
+	method := "SetBucketReferer"
+
+	if err := bosClient.SetBucketReferer("bucket-0", BucketReferer{WhiteList: []string{" "}}, nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+
+	around(t, method, bucketNamePrefix, "", func(bucketName string) {
+		bucketReferer := BucketReferer{
+			AllowEmptyReferer: true,
+			WhiteList:         []string{"http://www.example.com/*", "https://*.example.com/*"},
+		}
+
+		if err := bosClient.SetBucketReferer(bucketName, bucketReferer, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if gotBucketReferer, err := bosClient.GetBucketReferer(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("GetBucketReferer", err.Error(), "nil"))
+		} else if len(gotBucketReferer.WhiteList) != 2 {
+			t.Error(util.FormatTest("GetBucketReferer", strconv.Itoa(len(gotBucketReferer.WhiteList)), "2"))
+		} else if err := bosClient.DeleteBucketReferer(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("DeleteBucketReferer", err.Error(), "nil"))
+		}
+	})
+}
+
+func TestBucketStaticWebsite(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-bucket-static-website-"
+	method := "SetBucketStaticWebsite"
+
+	if err := bosClient.SetBucketStaticWebsite("bucket-0", BucketStaticWebsite{}, nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+
+	if err := bosClient.SetBucketStaticWebsite("bucket-0", BucketStaticWebsite{Index: "a/index.html"}, nil); err == nil {
+		t.Error(util.FormatTest(method, "nil", "error"))
+	}
+
+	around(t, method, bucketNamePrefix, "", func(bucketName string) {
+		bucketStaticWebsite := BucketStaticWebsite{Index: "index.html", NotFound: "404.html"}
+
+		if err := bosClient.SetBucketStaticWebsite(bucketName, bucketStaticWebsite, nil); err != nil {
+			t.Error(util.FormatTest(method, err.Error(), "nil"))
+		} else if gotBucketStaticWebsite, err := bosClient.GetBucketStaticWebsite(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("GetBucketStaticWebsite", err.Error(), "nil"))
+		} else if gotBucketStaticWebsite.Index != "index.html" {
+			t.Error(util.FormatTest("GetBucketStaticWebsite", gotBucketStaticWebsite.Index, "index.html"))
+		} else if err := bosClient.DeleteBucketStaticWebsite(bucketName, nil); err != nil {
+			t.Error(util.FormatTest("DeleteBucketStaticWebsite", err.Error(), "nil"))
+		}
+	})
+}
+
+func TestBucketReplication(t *testing.T) {
+	bucketNamePrefix := "baidubce-sdk-go-test-for-bucket-replication-"
+	method := "PutBucketReplication"
+
+	around(t, method, bucketNamePrefix, "", func(destBucketName string) {
+		around(t, method, bucketNamePrefix+"src-", "", func(bucketName string) {
+			bucketReplication := BucketReplication{
+				Id:               "replication-0",
+				Status:           STATUS_ENABLED,
+				Resource:         []string{bucketName + "/*"},
+				Destination:      BucketReplicationDestination{Bucket: destBucketName},
+				ReplicateDeletes: STATUS_ENABLED,
+			}
+
+			if err := bosClient.PutBucketReplication(bucketName, bucketReplication, nil); err != nil {
+				t.Error(util.FormatTest(method, err.Error(), "nil"))
+			} else if gotBucketReplication, err := bosClient.GetBucketReplication(bucketName, nil); err != nil {
+				t.Error(util.FormatTest("GetBucketReplication", err.Error(), "nil"))
+			} else if gotBucketReplication.Destination.Bucket != destBucketName {
+				t.Error(util.FormatTest("GetBucketReplication", gotBucketReplication.Destination.Bucket, destBucketName))
+			} else if _, err := bosClient.GetBucketReplicationProgress(bucketName, nil); err != nil {
+				t.Error(util.FormatTest("GetBucketReplicationProgress", err.Error(), "nil"))
+			} else if err := bosClient.DeleteBucketReplication(bucketName, nil); err != nil {
+				t.Error(util.FormatTest("DeleteBucketReplication", err.Error(), "nil"))
+			}
+		})
+	})
+}
+
 func TestPubObjectBySTS(t *testing.T) {
 	bucketNamePrefix := "baidubce-sdk-go-test-for-put-object-by-sts-"
 	method := "PutObject"
@@ -1719,3 +2947,91 @@ func around(t *testing.T, method, bucketNamePrefix string, objectKey interface{}
 		}
 	}
 }
+
+// testMetrics records the metrics reported by bce.Client.
+type testMetrics struct {
+	mutex    sync.Mutex
+	requests []*bce.RequestMetrics
+}
+
+func (metrics *testMetrics) ObserveRequest(requestMetrics *bce.RequestMetrics) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	metrics.requests = append(metrics.requests, requestMetrics)
+}
+
+func TestSendRequestMetrics(t *testing.T) {
+	server := bostest.NewServer()
+	defer server.Close()
+
+	metrics := &testMetrics{}
+	config := server.NewConfig()
+	config.Metrics = metrics
+	config.RetryPolicy = bce.NewDefaultRetryPolicy(3, time.Millisecond)
+	client := NewClient(NewConfig(config))
+
+	bucketName, objectKey, data := "metrics-bucket", "a.txt", "metrics"
+
+	if err := client.CreateBucket(bucketName, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.PutObject(bucketName, objectKey, data, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "GET",
+		Path:       "/" + bucketName + "/" + objectKey,
+		Times:      1,
+		StatusCode: http.StatusServiceUnavailable,
+	})
+
+	object, err := client.GetObject(bucketName, objectKey, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object.ObjectContent.Close()
+
+	client.GetObject(bucketName, "no-such-key", nil)
+	client.SetBucketPrivate(bucketName, nil)
+
+	expected := []bce.RequestMetrics{
+		{Operation: bce.Operation{Service: "bos", Name: "CreateBucket", Bucket: bucketName},
+			Method: "PUT", StatusCode: http.StatusOK, Attempts: 1},
+		{Operation: bce.Operation{Service: "bos", Name: "PutObject", Bucket: bucketName, Key: objectKey},
+			Method: "PUT", StatusCode: http.StatusOK, Attempts: 1, BytesSent: int64(len(data))},
+		{Operation: bce.Operation{Service: "bos", Name: "GetObject", Bucket: bucketName, Key: objectKey},
+			Method: "GET", StatusCode: http.StatusOK, Attempts: 2, BytesReceived: int64(len(data))},
+		{Operation: bce.Operation{Service: "bos", Name: "GetObject", Bucket: bucketName, Key: "no-such-key"},
+			Method: "GET", StatusCode: http.StatusNotFound, ErrorCode: "NoSuchKey", Attempts: 1},
+		{Operation: bce.Operation{Service: "bos", Name: "SetBucketPrivate", Bucket: bucketName},
+			Method: "PUT", StatusCode: http.StatusOK, Attempts: 1},
+	}
+
+	if len(metrics.requests) != len(expected) {
+		t.Fatal(util.FormatTest("SendRequestMetrics", strconv.Itoa(len(metrics.requests)), strconv.Itoa(len(expected))))
+	}
+
+	for i, requestMetrics := range metrics.requests {
+		actual := *requestMetrics
+		actual.Err, actual.Duration = nil, 0
+
+		// the body of error response is not checked, it is generated by the fake server
+		if actual.ErrorCode != "" {
+			actual.BytesReceived = 0
+		}
+
+		if actual != expected[i] {
+			t.Error(util.FormatTest("SendRequestMetrics", fmt.Sprintf("%+v", actual), fmt.Sprintf("%+v", expected[i])))
+		}
+
+		if (requestMetrics.Err != nil) != (requestMetrics.ErrorCode != "") {
+			t.Error(util.FormatTest("SendRequestMetrics "+requestMetrics.Name, fmt.Sprint(requestMetrics.Err),
+				requestMetrics.ErrorCode))
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package bos
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// ObjectConditions defined a struct for the preconditions of conditional GET, HEAD and PUT requests.
+//
+// Zero values are ignored. Use "*" as IfNoneMatch of a PUT request to create a BOS Object only if it does not exist.
+type ObjectConditions struct {
+	IfMatch           string
+	IfNoneMatch       string
+	IfModifiedSince   time.Time
+	IfUnmodifiedSince time.Time
+}
+
+func (conditions *ObjectConditions) mergeToSignOption(option *bce.SignOption) {
+	if conditions.IfMatch != "" {
+		option.AddHeader("If-Match", quoteETag(conditions.IfMatch))
+	}
+
+	if conditions.IfNoneMatch != "" {
+		option.AddHeader("If-None-Match", quoteETag(conditions.IfNoneMatch))
+	}
+
+	if !conditions.IfModifiedSince.IsZero() {
+		option.AddHeader("If-Modified-Since", conditions.IfModifiedSince.UTC().Format(http.TimeFormat))
+	}
+
+	if !conditions.IfUnmodifiedSince.IsZero() {
+		option.AddHeader("If-Unmodified-Since", conditions.IfUnmodifiedSince.UTC().Format(http.TimeFormat))
+	}
+}
+
+// quoteETag quotes an ETag as HTTP requires, the ETag of bos.ObjectMetadata is unquoted.
+func quoteETag(etag string) string {
+	if etag == "*" || (len(etag) > 1 && etag[0] == '"' && etag[len(etag)-1] == '"') {
+		return etag
+	}
+
+	return "\"" + etag + "\""
+}
+
+// ConditionError is returned instead of bce.Error when the BOS Object of a conditional request
+// is not modified (304) or a precondition fails (412).
+type ConditionError struct {
+	StatusCode int
+	RequestId  string
+
+	// ObjectMetadata is parsed from the response header, for example it contains the current ETag of a 304 response.
+	ObjectMetadata *ObjectMetadata
+}
+
+// Error returns the formatted error message.
+func (err *ConditionError) Error() string {
+	return fmt.Sprintf("Condition not met, Status Code: %d, Request Id: \"%s\"", err.StatusCode, err.RequestId)
+}
+
+// NotModified checks whether the BOS Object is not modified, the cached content can be used.
+func (err *ConditionError) NotModified() bool {
+	return err.StatusCode == http.StatusNotModified
+}
+
+// PreconditionFailed checks whether the BOS Object does not match the preconditions.
+func (err *ConditionError) PreconditionFailed() bool {
+	return err.StatusCode == http.StatusPreconditionFailed
+}
+
+// IsNotModified checks whether err is a bos.ConditionError of 304 response.
+func IsNotModified(err error) bool {
+	conditionError, ok := err.(*ConditionError)
+	return ok && conditionError.NotModified()
+}
+
+// IsPreconditionFailed checks whether err is a bos.ConditionError of 412 response.
+func IsPreconditionFailed(err error) bool {
+	conditionError, ok := err.(*ConditionError)
+	return ok && conditionError.PreconditionFailed()
+}
+
+// checkConditionResponse converts 304 and 412 responses to bos.ConditionError, other errors are returned as is.
+func checkConditionResponse(resp *bce.Response, err error) error {
+	if resp == nil || resp.Response == nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusNotModified && resp.StatusCode != http.StatusPreconditionFailed {
+		return err
+	}
+
+	if resp.BodyContent == nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+
+	return &ConditionError{
+		StatusCode:     resp.StatusCode,
+		RequestId:      resp.Header.Get("x-bce-request-id"),
+		ObjectMetadata: NewObjectMetadataFromHeader(resp.Header),
+	}
+}
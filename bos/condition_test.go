@@ -0,0 +1,73 @@
+package bos
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestMergeToSignOptionForObjectConditions(t *testing.T) {
+	conditions := ObjectConditions{
+		IfMatch:         "abc",
+		IfNoneMatch:     "*",
+		IfModifiedSince: time.Date(2017, 1, 3, 13, 30, 19, 0, time.FixedZone("CST", 8*3600)),
+	}
+
+	option := &bce.SignOption{}
+	conditions.mergeToSignOption(option)
+
+	expected := map[string]string{
+		"If-Match":          "\"abc\"",
+		"If-None-Match":     "*",
+		"If-Modified-Since": "Tue, 03 Jan 2017 05:30:19 GMT",
+	}
+
+	for key, value := range expected {
+		if option.Headers[key] != value {
+			t.Error(util.FormatTest("ObjectConditions: mergeToSignOption", option.Headers[key], value))
+		}
+	}
+
+	if _, ok := option.Headers["If-Unmodified-Since"]; ok {
+		t.Error(util.FormatTest("ObjectConditions: mergeToSignOption", "If-Unmodified-Since", "not exists"))
+	}
+}
+
+func TestCheckConditionResponse(t *testing.T) {
+	err := errors.New("failed")
+
+	if result := checkConditionResponse(nil, err); result != err {
+		t.Error(util.FormatTest("checkConditionResponse", result.Error(), err.Error()))
+	}
+
+	resp := bce.NewResponse(&http.Response{
+		StatusCode: http.StatusNotModified,
+		Header:     http.Header{"Etag": []string{"\"abc\""}, "X-Bce-Request-Id": []string{"123"}},
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	})
+	result := checkConditionResponse(resp, nil)
+
+	if !IsNotModified(result) || IsPreconditionFailed(result) {
+		t.Error(util.FormatTest("checkConditionResponse", "not ConditionError of 304", "ConditionError of 304"))
+	} else if etag := result.(*ConditionError).ObjectMetadata.ETag; etag != "abc" {
+		t.Error(util.FormatTest("checkConditionResponse", etag, "abc"))
+	}
+
+	resp.StatusCode = http.StatusPreconditionFailed
+
+	if result := checkConditionResponse(resp, err); !IsPreconditionFailed(result) {
+		t.Error(util.FormatTest("checkConditionResponse", "not ConditionError of 412", "ConditionError of 412"))
+	}
+
+	resp.StatusCode = http.StatusOK
+
+	if result := checkConditionResponse(resp, nil); result != nil {
+		t.Error(util.FormatTest("checkConditionResponse", result.Error(), "nil"))
+	}
+}
@@ -0,0 +1,771 @@
+package bos
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// ENCRYPTION_ALGORITHM is the content cipher of client side encryption.
+//
+// AES/GCM authenticates the content but the whole BOS Object has to be read for ranged reads,
+// AES/CTR supports ranged reads and multipart uploads.
+const ENCRYPTION_ALGORITHM_AES_GCM = "AES/GCM/NoPadding"
+const ENCRYPTION_ALGORITHM_AES_CTR = "AES/CTR/NoPadding"
+
+// The user metadata of BOS Object which holds the envelope of client side encryption.
+const (
+	EncryptionKeyMetadata             = "x-bce-meta-client-side-encryption-key"
+	EncryptionIVMetadata              = "x-bce-meta-client-side-encryption-iv"
+	EncryptionAlgorithmMetadata       = "x-bce-meta-client-side-encryption-cek-alg"
+	EncryptionMaterialMetadata        = "x-bce-meta-client-side-encryption-matdesc"
+	EncryptionUnencryptedSizeMetadata = "x-bce-meta-client-side-encryption-unencrypted-content-length"
+)
+
+// DATA_KEY_SIZE is the size of the per-object data key, AES-256 is used.
+const DATA_KEY_SIZE int = 32
+
+const gcmNonceSize int = 12
+
+// MasterKeyProvider wraps the per-object data keys with a master key, such as a local key or a KMS key.
+type MasterKeyProvider interface {
+	// WrapKey encrypts a data key, the description is stored along with the BOS Object
+	// and passed to UnwrapKey to identify the master key.
+	WrapKey(dataKey []byte) (wrappedKey []byte, description string, err error)
+
+	// UnwrapKey decrypts a data key wrapped by WrapKey.
+	UnwrapKey(wrappedKey []byte, description string) ([]byte, error)
+}
+
+// AESMasterKeyProvider wraps data keys with a local AES master key by AES-GCM.
+type AESMasterKeyProvider struct {
+	keyId string
+	aead  cipher.AEAD
+}
+
+// NewAESMasterKeyProvider returns a bos.AESMasterKeyProvider, keyId identifies the master key
+// and masterKey should be 16, 24 or 32 bytes.
+func NewAESMasterKeyProvider(keyId string, masterKey []byte) (*AESMasterKeyProvider, error) {
+	block, err := aes.NewCipher(masterKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESMasterKeyProvider{keyId: keyId, aead: aead}, nil
+}
+
+// WrapKey encrypts a data key with the master key.
+func (provider *AESMasterKeyProvider) WrapKey(dataKey []byte) ([]byte, string, error) {
+	nonce, err := randomBytes(provider.aead.NonceSize())
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return provider.aead.Seal(nonce, nonce, dataKey, []byte(provider.keyId)), provider.keyId, nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the master key.
+func (provider *AESMasterKeyProvider) UnwrapKey(wrappedKey []byte, description string) ([]byte, error) {
+	if description != provider.keyId {
+		return nil, fmt.Errorf("Unknown master key %s.", description)
+	}
+
+	nonceSize := provider.aead.NonceSize()
+
+	if len(wrappedKey) < nonceSize {
+		return nil, errors.New("Invalid wrapped key.")
+	}
+
+	return provider.aead.Open(nil, wrappedKey[:nonceSize], wrappedKey[nonceSize:], []byte(provider.keyId))
+}
+
+// EncryptionClient is a BOS Client which encrypts BOS Objects on client side before uploading,
+// and decrypts them after downloading.
+//
+// Each BOS Object is encrypted with its own data key, the data key is wrapped by the MasterKeyProvider,
+// and the wrapped key and IV are stored in the user metadata of BOS Object. Objects without the
+// envelope are returned as is, so an EncryptionClient can read unencrypted BOS Objects too.
+//
+// EncryptionClient only provides the methods which encrypt or decrypt the content, other methods
+// of bos.Client would store the content in plaintext, so please call them on the wrapped bos.Client.
+type EncryptionClient struct {
+	client    *Client
+	provider  MasterKeyProvider
+	algorithm string
+}
+
+// NewEncryptionClient returns an EncryptionClient, the algorithm is used by PutObject,
+// multipart uploads always use ENCRYPTION_ALGORITHM_AES_CTR.
+func NewEncryptionClient(client *Client, provider MasterKeyProvider, algorithm string) (*EncryptionClient, error) {
+	if algorithm != ENCRYPTION_ALGORITHM_AES_GCM && algorithm != ENCRYPTION_ALGORITHM_AES_CTR {
+		return nil, fmt.Errorf("Invalid encryption algorithm %s.", algorithm)
+	}
+
+	if provider == nil {
+		return nil, errors.New("Master key provider should not be nil.")
+	}
+
+	return &EncryptionClient{client: client, provider: provider, algorithm: algorithm}, nil
+}
+
+// encryptionEnvelope holds the data key and IV of an encrypted BOS Object.
+type encryptionEnvelope struct {
+	algorithm       string
+	dataKey         []byte
+	iv              []byte
+	unencryptedSize int64
+}
+
+func (c *EncryptionClient) newEnvelope(algorithm string) (*encryptionEnvelope, error) {
+	dataKey, err := randomBytes(DATA_KEY_SIZE)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ivSize := aes.BlockSize
+
+	if algorithm == ENCRYPTION_ALGORITHM_AES_GCM {
+		ivSize = gcmNonceSize
+	}
+
+	iv, err := randomBytes(ivSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptionEnvelope{algorithm: algorithm, dataKey: dataKey, iv: iv}, nil
+}
+
+// metadata returns a copy of metadata with the envelope stored in its user metadata.
+func (c *EncryptionClient) metadata(envelope *encryptionEnvelope, metadata *ObjectMetadata) (*ObjectMetadata, error) {
+	wrappedKey, description, err := c.provider.WrapKey(envelope.dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objectMetadata := &ObjectMetadata{}
+
+	if metadata != nil {
+		*objectMetadata = *metadata
+		objectMetadata.UserMetadata = nil
+		objectMetadata.ContentLength = 0
+		objectMetadata.ContentMD5 = ""
+		objectMetadata.ContentSha256 = ""
+
+		for key, value := range metadata.UserMetadata {
+			objectMetadata.AddUserMetadata(key, value)
+		}
+	}
+
+	objectMetadata.AddUserMetadata(EncryptionKeyMetadata, base64.StdEncoding.EncodeToString(wrappedKey))
+	objectMetadata.AddUserMetadata(EncryptionIVMetadata, base64.StdEncoding.EncodeToString(envelope.iv))
+	objectMetadata.AddUserMetadata(EncryptionAlgorithmMetadata, envelope.algorithm)
+	objectMetadata.AddUserMetadata(EncryptionMaterialMetadata, description)
+	objectMetadata.AddUserMetadata(EncryptionUnencryptedSizeMetadata,
+		strconv.FormatInt(envelope.unencryptedSize, 10))
+
+	return objectMetadata, nil
+}
+
+// openEnvelope gets the envelope from the metadata of BOS Object, it returns nil if the BOS Object is not encrypted.
+func (c *EncryptionClient) openEnvelope(metadata *ObjectMetadata) (*encryptionEnvelope, error) {
+	algorithm := util.GetMapValue(metadata.UserMetadata, EncryptionAlgorithmMetadata, true)
+
+	if algorithm == "" {
+		return nil, nil
+	}
+
+	if algorithm != ENCRYPTION_ALGORITHM_AES_GCM && algorithm != ENCRYPTION_ALGORITHM_AES_CTR {
+		return nil, fmt.Errorf("Unsupported encryption algorithm %s.", algorithm)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(
+		util.GetMapValue(metadata.UserMetadata, EncryptionKeyMetadata, true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(util.GetMapValue(metadata.UserMetadata, EncryptionIVMetadata, true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	unencryptedSize, err := strconv.ParseInt(
+		util.GetMapValue(metadata.UserMetadata, EncryptionUnencryptedSizeMetadata, true), 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := c.provider.UnwrapKey(wrappedKey,
+		util.GetMapValue(metadata.UserMetadata, EncryptionMaterialMetadata, true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptionEnvelope{
+		algorithm:       algorithm,
+		dataKey:         dataKey,
+		iv:              iv,
+		unencryptedSize: unencryptedSize,
+	}, nil
+}
+
+// PutObject encrypts the data and creates a BOS Object.
+//
+// The data is encrypted in memory, please use MultipartUploadFromFile for large files.
+func (c *EncryptionClient) PutObject(bucketName, objectKey string, data interface{},
+	metadata *ObjectMetadata, option *bce.SignOption) (PutObjectResponse, error) {
+
+	var plaintext []byte
+
+	if str, ok := data.(string); ok {
+		plaintext = []byte(str)
+	} else if byteArray, ok := data.([]byte); ok {
+		plaintext = byteArray
+	} else if r, ok := data.(io.Reader); ok {
+		byteArray, err := ioutil.ReadAll(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext = byteArray
+	} else {
+		return nil, errors.New("data type should be string or []byte or io.Reader.")
+	}
+
+	envelope, err := c.newEnvelope(c.algorithm)
+
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.unencryptedSize = int64(len(plaintext))
+	ciphertext, err := envelope.encrypt(plaintext)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objectMetadata, err := c.metadata(envelope, metadata)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.client.PutObject(bucketName, objectKey, ciphertext, objectMetadata, option)
+}
+
+// GetObject gets and decrypts a BOS Object.
+func (c *EncryptionClient) GetObject(bucketName, objectKey string, option *bce.SignOption) (*Object, error) {
+	return c.GetObjectFromRequest(GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}, option)
+}
+
+// GetObjectFromRequest gets and decrypts a BOS Object, the Range of the request is the range of decrypted content.
+//
+// A ranged read of AES/CTR BOS Object only gets the blocks in the range, while a ranged read of
+// AES/GCM BOS Object gets the whole BOS Object to authenticate it. The ContentLength of the returned
+// metadata is the size of decrypted content.
+func (c *EncryptionClient) GetObjectFromRequest(getObjectRequest GetObjectRequest,
+	option *bce.SignOption) (*Object, error) {
+
+	if getObjectRequest.Range == "" {
+		object, err := c.client.GetObjectFromRequest(getObjectRequest, option)
+
+		if err != nil {
+			return nil, err
+		}
+
+		envelope, err := c.openEnvelope(object.ObjectMetadata)
+
+		if err != nil || envelope == nil {
+			if err != nil {
+				object.ObjectContent.Close()
+			}
+
+			return object, err
+		}
+
+		return envelope.decryptObject(getObjectRequest, object, 0, 0, envelope.unencryptedSize)
+	}
+
+	metadata, err := c.client.GetObjectMetadataFromRequest(GetObjectMetadataRequest{
+		BucketName:       getObjectRequest.BucketName,
+		ObjectKey:        getObjectRequest.ObjectKey,
+		VersionId:        getObjectRequest.VersionId,
+		ObjectConditions: getObjectRequest.ObjectConditions,
+
+		ServerSideEncryption: getObjectRequest.ServerSideEncryption,
+	}, cloneSignOption(option))
+
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := c.openEnvelope(metadata)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if envelope == nil {
+		return c.client.GetObjectFromRequest(getObjectRequest, option)
+	}
+
+	start, end, err := parseByteRange(getObjectRequest.Range, envelope.unencryptedSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the BOS Object may be overwritten between the two requests
+	getObjectRequest.ObjectConditions.IfMatch = metadata.ETag
+
+	var skip int64
+
+	if envelope.algorithm == ENCRYPTION_ALGORITHM_AES_GCM {
+		getObjectRequest.Range = ""
+		skip = start
+	} else {
+		alignedStart := start / aes.BlockSize * aes.BlockSize
+		getObjectRequest.Range = fmt.Sprintf("%d-%d", alignedStart, end)
+		skip = start - alignedStart
+	}
+
+	object, err := c.client.GetObjectFromRequest(getObjectRequest, option)
+
+	if err != nil {
+		return nil, err
+	}
+
+	object, err = envelope.decryptObject(getObjectRequest, object, start-skip, skip, end-start+1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	object.ObjectMetadata.ContentRange = fmt.Sprintf("bytes %d-%d/%d", start, end, envelope.unencryptedSize)
+
+	return object, nil
+}
+
+// GetObjectToFile gets and decrypts the content of a BOS Object to local file.
+func (c *EncryptionClient) GetObjectToFile(getObjectRequest *GetObjectRequest, file *os.File,
+	option *bce.SignOption) (*ObjectMetadata, error) {
+
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	object, err := c.GetObjectFromRequest(*getObjectRequest, option)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer object.ObjectContent.Close()
+
+	_, err = io.Copy(file, object.ObjectContent)
+
+	return object.ObjectMetadata, err
+}
+
+// MultipartUploadFromFile encrypts a local file by AES/CTR and uploads it by BOS Object Multipart Upload,
+// partSize should be a multiple of 16 bytes.
+func (c *EncryptionClient) MultipartUploadFromFile(bucketName, objectKey, filePath string,
+	partSize int64) (*CompleteMultipartUploadResponse, error) {
+
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := c.InitiateEncryptionMultipartUpload(bucketName, objectKey, fileInfo.Size(), partSize, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var partCount int = int(math.Ceil(float64(fileInfo.Size()) / float64(partSize)))
+
+	if partCount == 0 {
+		partCount = 1
+	}
+
+	parts := make([]PartSummary, partCount)
+	partNumbers := make(chan int)
+
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var uploadError error
+
+	for i := 0; i < DefaultMultipartCopyParallel; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for partNumber := range partNumbers {
+				offset := partSize * int64(partNumber-1)
+				size := int64(math.Min(float64(fileInfo.Size()-offset), float64(partSize)))
+
+				uploadPartResponse, err := upload.UploadPart(partNumber, io.NewSectionReader(file, offset, size), size)
+
+				mutex.Lock()
+
+				if err != nil {
+					if uploadError == nil {
+						uploadError = err
+					}
+				} else {
+					parts[partNumber-1] = PartSummary{PartNumber: partNumber, ETag: uploadPartResponse.GetETag()}
+				}
+
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for partNumber := 1; partNumber <= partCount; partNumber++ {
+		mutex.Lock()
+		failed := uploadError != nil
+		mutex.Unlock()
+
+		if failed {
+			break
+		}
+
+		partNumbers <- partNumber
+	}
+
+	close(partNumbers)
+	waitGroup.Wait()
+
+	if uploadError != nil {
+		upload.Abort()
+		return nil, uploadError
+	}
+
+	return upload.Complete(parts)
+}
+
+// EncryptionMultipartUpload defined a struct for an encrypted BOS Object Multipart Upload,
+// all parts except the last one must be partSize bytes.
+type EncryptionMultipartUpload struct {
+	client     *EncryptionClient
+	envelope   *encryptionEnvelope
+	BucketName string
+	ObjectKey  string
+	UploadId   string
+	PartSize   int64
+}
+
+// InitiateEncryptionMultipartUpload initiates an encrypted BOS Object Multipart Upload, size is the total size
+// of unencrypted content, and partSize should be a multiple of 16 bytes.
+func (c *EncryptionClient) InitiateEncryptionMultipartUpload(bucketName, objectKey string, size, partSize int64,
+	metadata *ObjectMetadata) (*EncryptionMultipartUpload, error) {
+
+	if partSize < MIN_PART_SIZE || partSize > MAX_PART_SIZE || partSize%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("Invalid part size %d. It should be a multiple of %d from %d to %d.",
+			partSize, aes.BlockSize, MIN_PART_SIZE, MAX_PART_SIZE)
+	}
+
+	envelope, err := c.newEnvelope(ENCRYPTION_ALGORITHM_AES_CTR)
+
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.unencryptedSize = size
+	objectMetadata, err := c.metadata(envelope, metadata)
+
+	if err != nil {
+		return nil, err
+	}
+
+	initiateMultipartUploadResponse, err := c.client.InitiateMultipartUpload(InitiateMultipartUploadRequest{
+		BucketName:     bucketName,
+		ObjectKey:      objectKey,
+		ObjectMetadata: objectMetadata,
+	}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptionMultipartUpload{
+		client:     c,
+		envelope:   envelope,
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+		UploadId:   initiateMultipartUploadResponse.UploadId,
+		PartSize:   partSize,
+	}, nil
+}
+
+// UploadPart encrypts and uploads a part, size is the size of data.
+func (upload *EncryptionMultipartUpload) UploadPart(partNumber int, data io.Reader,
+	size int64) (UploadPartResponse, error) {
+
+	if partNumber < MIN_PART_NUMBER || partNumber > MAX_PART_NUMBER {
+		return nil, fmt.Errorf("Invalid partNumber %d. The valid range is from %d to %d.",
+			partNumber, MIN_PART_NUMBER, MAX_PART_NUMBER)
+	}
+
+	offset := upload.PartSize * int64(partNumber-1)
+	stream, err := upload.envelope.ctrStream(offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return upload.client.client.UploadPart(UploadPartRequest{
+		BucketName: upload.BucketName,
+		ObjectKey:  upload.ObjectKey,
+		UploadId:   upload.UploadId,
+		PartSize:   size,
+		PartNumber: partNumber,
+		PartData:   cipher.StreamReader{S: stream, R: data},
+	}, nil)
+}
+
+// Complete completes the encrypted BOS Object Multipart Upload.
+func (upload *EncryptionMultipartUpload) Complete(parts []PartSummary) (*CompleteMultipartUploadResponse, error) {
+	return upload.client.client.CompleteMultipartUpload(CompleteMultipartUploadRequest{
+		BucketName: upload.BucketName,
+		ObjectKey:  upload.ObjectKey,
+		UploadId:   upload.UploadId,
+		Parts:      parts,
+	}, nil)
+}
+
+// Abort aborts the encrypted BOS Object Multipart Upload.
+func (upload *EncryptionMultipartUpload) Abort() error {
+	return upload.client.client.AbortMultipartUpload(AbortMultipartUploadRequest{
+		BucketName: upload.BucketName,
+		ObjectKey:  upload.ObjectKey,
+		UploadId:   upload.UploadId,
+	}, nil)
+}
+
+func (envelope *encryptionEnvelope) encrypt(plaintext []byte) ([]byte, error) {
+	if envelope.algorithm == ENCRYPTION_ALGORITHM_AES_GCM {
+		aead, err := envelope.gcm()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return aead.Seal(nil, envelope.iv, plaintext, nil), nil
+	}
+
+	stream, err := envelope.ctrStream(0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, len(plaintext))
+	stream.XORKeyStream(ciphertext, plaintext)
+
+	return ciphertext, nil
+}
+
+// decryptObject replaces the content of object with the decrypted content, offset is the position
+// of the content in the whole BOS Object, and the first skip bytes of the decrypted content are dropped.
+func (envelope *encryptionEnvelope) decryptObject(getObjectRequest GetObjectRequest, object *Object,
+	offset, skip, length int64) (*Object, error) {
+
+	var content io.Reader
+
+	if envelope.algorithm == ENCRYPTION_ALGORITHM_AES_GCM {
+		defer object.ObjectContent.Close()
+
+		ciphertext, err := ioutil.ReadAll(object.ObjectContent)
+
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := envelope.gcm()
+
+		if err != nil {
+			return nil, err
+		}
+
+		plaintext, err := aead.Open(nil, envelope.iv, ciphertext, nil)
+
+		if err != nil {
+			return nil, &IntegrityError{getObjectRequest.BucketName, getObjectRequest.ObjectKey,
+				ENCRYPTION_ALGORITHM_AES_GCM, "authenticated content", err.Error()}
+		}
+
+		content = bytes.NewReader(plaintext)
+		object.ObjectContent = ioutil.NopCloser(content)
+	} else {
+		stream, err := envelope.ctrStream(offset)
+
+		if err != nil {
+			object.ObjectContent.Close()
+			return nil, err
+		}
+
+		content = cipher.StreamReader{S: stream, R: object.ObjectContent}
+		object.ObjectContent = struct {
+			io.Reader
+			io.Closer
+		}{content, object.ObjectContent}
+	}
+
+	if skip > 0 {
+		if _, err := io.CopyN(ioutil.Discard, content, skip); err != nil {
+			object.ObjectContent.Close()
+			return nil, err
+		}
+	}
+
+	if length < envelope.unencryptedSize {
+		object.ObjectContent = struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(content, length), object.ObjectContent}
+	}
+
+	object.ObjectMetadata.ContentLength = length
+
+	return object, nil
+}
+
+func (envelope *encryptionEnvelope) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(envelope.dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// ctrStream returns the AES/CTR key stream starting from offset, offset should be a multiple of 16 bytes.
+func (envelope *encryptionEnvelope) ctrStream(offset int64) (cipher.Stream, error) {
+	block, err := aes.NewCipher(envelope.dataKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewCTR(block, addCounter(envelope.iv, uint64(offset/aes.BlockSize))), nil
+}
+
+// addCounter adds blocks to the 128 bits big endian counter iv.
+func addCounter(iv []byte, blocks uint64) []byte {
+	counter := make([]byte, len(iv))
+	copy(counter, iv)
+
+	for i := len(counter) - 1; i >= 0 && blocks > 0; i-- {
+		sum := uint64(counter[i]) + blocks&0xff
+		counter[i] = byte(sum)
+		blocks = blocks>>8 + sum>>8
+	}
+
+	return counter
+}
+
+// parseByteRange parses the Range field of bos.GetObjectRequest, such as "0-99", "100-" and "-500",
+// and returns the inclusive positions in content of size.
+func parseByteRange(value string, size int64) (int64, int64, error) {
+	invalidError := fmt.Errorf("Invalid range %s for size %d.", value, size)
+	index := strings.Index(value, "-")
+
+	if index == -1 {
+		return 0, 0, invalidError
+	}
+
+	startValue, endValue := value[:index], value[index+1:]
+
+	if startValue == "" {
+		length, err := strconv.ParseInt(endValue, 10, 64)
+
+		if err != nil || length <= 0 || size == 0 {
+			return 0, 0, invalidError
+		}
+
+		if length > size {
+			length = size
+		}
+
+		return size - length, size - 1, nil
+	}
+
+	start, err := strconv.ParseInt(startValue, 10, 64)
+
+	if err != nil || start >= size {
+		return 0, 0, invalidError
+	}
+
+	end := size - 1
+
+	if endValue != "" {
+		end, err = strconv.ParseInt(endValue, 10, 64)
+
+		if err != nil || end < start {
+			return 0, 0, invalidError
+		}
+
+		if end >= size {
+			end = size - 1
+		}
+	}
+
+	return start, end, nil
+}
+
+func randomBytes(size int) ([]byte, error) {
+	byteArray := make([]byte, size)
+
+	if _, err := io.ReadFull(rand.Reader, byteArray); err != nil {
+		return nil, err
+	}
+
+	return byteArray, nil
+}
@@ -0,0 +1,438 @@
+package bos
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func newTestEncryptionClient(t *testing.T, algorithm string) *EncryptionClient {
+	provider, err := NewAESMasterKeyProvider("test-key", bytes.Repeat([]byte("k"), 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptionClient, err := NewEncryptionClient(bosClient, provider, algorithm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return encryptionClient
+}
+
+func TestAESMasterKeyProvider(t *testing.T) {
+	provider, err := NewAESMasterKeyProvider("test-key", bytes.Repeat([]byte("k"), 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	wrappedKey, description, err := provider.WrapKey(dataKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unwrappedKey, err := provider.UnwrapKey(wrappedKey, description); err != nil ||
+		!bytes.Equal(unwrappedKey, dataKey) {
+
+		t.Error(util.FormatTest("AESMasterKeyProvider: UnwrapKey", string(unwrappedKey), string(dataKey)))
+	}
+
+	if _, err := provider.UnwrapKey(wrappedKey, "other-key"); err == nil {
+		t.Error(util.FormatTest("AESMasterKeyProvider: UnwrapKey", "nil", "error"))
+	}
+
+	wrappedKey[len(wrappedKey)-1] ^= 1
+
+	if _, err := provider.UnwrapKey(wrappedKey, description); err == nil {
+		t.Error(util.FormatTest("AESMasterKeyProvider: UnwrapKey", "nil", "error"))
+	}
+
+	if _, err := NewEncryptionClient(bosClient, provider, "AES/ECB"); err == nil {
+		t.Error(util.FormatTest("NewEncryptionClient", "nil", "error"))
+	}
+}
+
+func TestEncryptionEnvelope(t *testing.T) {
+	plaintext := []byte(strings.Repeat("Hello World 你好 ", 100))
+
+	for _, algorithm := range []string{ENCRYPTION_ALGORITHM_AES_GCM, ENCRYPTION_ALGORITHM_AES_CTR} {
+		encryptionClient := newTestEncryptionClient(t, algorithm)
+		envelope, err := encryptionClient.newEnvelope(algorithm)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		envelope.unencryptedSize = int64(len(plaintext))
+		ciphertext, err := envelope.encrypt(plaintext)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		metadata, err := encryptionClient.metadata(envelope, &ObjectMetadata{ContentType: "text/plain"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opened, err := encryptionClient.openEnvelope(metadata)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(opened.dataKey, envelope.dataKey) || opened.unencryptedSize != envelope.unencryptedSize {
+			t.Error(util.FormatTest("EncryptionClient: openEnvelope "+algorithm,
+				hex.EncodeToString(opened.dataKey), hex.EncodeToString(envelope.dataKey)))
+		}
+
+		var start, end int64 = 21, 530
+		offset := start
+
+		if algorithm == ENCRYPTION_ALGORITHM_AES_CTR {
+			offset = start / aes.BlockSize * aes.BlockSize
+			ciphertext = ciphertext[offset : end+1]
+		} else {
+			offset = 0
+		}
+
+		object := &Object{ObjectMetadata: &ObjectMetadata{}, ObjectContent: ioutil.NopCloser(bytes.NewReader(ciphertext))}
+		object, err = opened.decryptObject(GetObjectRequest{}, object, offset, start-offset, end-start+1)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := ioutil.ReadAll(object.ObjectContent)
+
+		if err != nil || !bytes.Equal(result, plaintext[start:end+1]) {
+			t.Error(util.FormatTest("encryptionEnvelope: decryptObject "+algorithm, string(result),
+				string(plaintext[start:end+1])))
+		}
+	}
+
+	if metadata, _ := (&EncryptionClient{}).openEnvelope(&ObjectMetadata{}); metadata != nil {
+		t.Error(util.FormatTest("EncryptionClient: openEnvelope", "envelope", "nil"))
+	}
+}
+
+func TestAddCounter(t *testing.T) {
+	iv := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xfe}
+	expected := "00000000000000000000000000010001"
+
+	if counter := hex.EncodeToString(addCounter(iv, 3)); counter != expected {
+		t.Error(util.FormatTest("addCounter", counter, expected))
+	}
+
+	if iv[15] != 0xfe {
+		t.Error(util.FormatTest("addCounter", strconv.Itoa(int(iv[15])), "254"))
+	}
+}
+
+func TestParseByteRange(t *testing.T) {
+	cases := map[string][2]int64{
+		"0-99":     {0, 99},
+		"100-":     {100, 999},
+		"-500":     {500, 999},
+		"-5000":    {0, 999},
+		"900-2000": {900, 999},
+	}
+
+	for value, expected := range cases {
+		start, end, err := parseByteRange(value, 1000)
+
+		if err != nil || start != expected[0] || end != expected[1] {
+			t.Error(util.FormatTest("parseByteRange "+value, strconv.FormatInt(start, 10)+"-"+
+				strconv.FormatInt(end, 10), strconv.FormatInt(expected[0], 10)+"-"+strconv.FormatInt(expected[1], 10)))
+		}
+	}
+
+	for _, value := range []string{"1000-", "10-1", "a-b", "100", "-0"} {
+		if _, _, err := parseByteRange(value, 1000); err == nil {
+			t.Error(util.FormatTest("parseByteRange "+value, "nil", "error"))
+		}
+	}
+}
+
+func newFakeEncryptionClient(t *testing.T, client *Client, algorithm string) *EncryptionClient {
+	provider, err := NewAESMasterKeyProvider("test-key", bytes.Repeat([]byte("k"), 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptionClient, err := NewEncryptionClient(client, provider, algorithm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return encryptionClient
+}
+
+func readObject(object *Object, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+
+	defer object.ObjectContent.Close()
+
+	byteArray, err := ioutil.ReadAll(object.ObjectContent)
+
+	return string(byteArray), err
+}
+
+func TestEncryptionClientWithFakeServer(t *testing.T) {
+	method := "EncryptionClient"
+	bucketName := "encryption-client"
+	objectKey := "encryption-client.txt"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	str := strings.Repeat("Hello World 你好 ", 100)
+
+	for _, algorithm := range []string{ENCRYPTION_ALGORITHM_AES_GCM, ENCRYPTION_ALGORITHM_AES_CTR} {
+		name := method + " " + algorithm
+		encryptionClient := newFakeEncryptionClient(t, client, algorithm)
+
+		if _, err := encryptionClient.PutObject(bucketName, objectKey, str, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if content, err := readObject(client.GetObject(bucketName, objectKey, nil)); err != nil || content == str {
+			t.Error(util.FormatTest(name+" stored content", content, "ciphertext"))
+		}
+
+		if content, err := readObject(encryptionClient.GetObject(bucketName, objectKey, nil)); err != nil ||
+			content != str {
+
+			t.Error(util.FormatTest(name+" GetObject", content, str))
+		}
+
+		// the start of range is not aligned to the AES block
+		getObjectRequest := GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+		getObjectRequest.SetRange(21, 530)
+		getObjectRequest.IfUnmodifiedSince = time.Now().Add(time.Hour)
+		server.ClearRequests()
+
+		if content, err := readObject(encryptionClient.GetObjectFromRequest(getObjectRequest, nil)); err != nil ||
+			content != str[21:531] {
+
+			t.Error(util.FormatTest(name+" GetObjectFromRequest with range", content, str[21:531]))
+		}
+
+		// the conditions of caller are kept along with the ETag got by HEAD
+		requests := server.Requests()
+
+		if request := requests[len(requests)-1]; request.Header.Get("If-Match") == "" ||
+			request.Header.Get("If-Unmodified-Since") == "" {
+
+			t.Error(util.FormatTest(name+" GetObjectFromRequest conditions", request.Header.Get("If-Match")+" "+
+				request.Header.Get("If-Unmodified-Since"), "If-Match and If-Unmodified-Since"))
+		}
+
+		file, err := ioutil.TempFile("", "bos-encryption")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.Remove(file.Name())
+
+		getObjectRequest = GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+		getObjectRequest.SetRangeFrom(100)
+		metadata, err := encryptionClient.GetObjectToFile(&getObjectRequest, file, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if byteArray, _ := ioutil.ReadFile(file.Name()); string(byteArray) != str[100:] ||
+			metadata.ContentLength != int64(len(str)-100) {
+
+			t.Error(util.FormatTest(name+" GetObjectToFile", string(byteArray), str[100:]))
+		}
+	}
+}
+
+func TestEncryptionClientOverwrittenWhileReading(t *testing.T) {
+	method := "EncryptionClient"
+	bucketName := "encryption-client-overwritten"
+	objectKey := "encryption-client.txt"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	encryptionClient := newFakeEncryptionClient(t, client, ENCRYPTION_ALGORITHM_AES_CTR)
+
+	if _, err := encryptionClient.PutObject(bucketName, objectKey, "Hello World", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the response of HEAD is delayed, the BOS Object is overwritten before the ranged GET
+	server.AddRule(&bostest.Rule{Method: "HEAD", Times: 1, Delay: 200 * time.Millisecond})
+	server.ClearRequests()
+	overwritten := make(chan error, 1)
+
+	go func() {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			for _, request := range server.Requests() {
+				if request.Method == "HEAD" {
+					_, err := encryptionClient.PutObject(bucketName, objectKey, "Hello BOS", nil, nil)
+					overwritten <- err
+					return
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+
+		overwritten <- errors.New("HEAD is not sent")
+	}()
+
+	getObjectRequest := GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+	getObjectRequest.SetRangeFrom(6)
+	content, err := readObject(encryptionClient.GetObjectFromRequest(getObjectRequest, nil))
+
+	if err := <-overwritten; err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsPreconditionFailed(err) {
+		t.Error(util.FormatTest(method+" overwritten while reading", content, "precondition failed"))
+	}
+}
+
+func TestEncryptionMultipartUpload(t *testing.T) {
+	method := "EncryptionMultipartUpload"
+	bucketName := "encryption-multipart-upload"
+	objectKey := "encryption-multipart-upload.bin"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	encryptionClient := newFakeEncryptionClient(t, client, ENCRYPTION_ALGORITHM_AES_GCM)
+	data := bytes.Repeat([]byte("0123456789abcdef"), int(MIN_PART_SIZE/16*2)+100)
+
+	file, err := ioutil.TempFile("", "bos-encryption")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	file.Write(data)
+	file.Close()
+
+	if _, err := encryptionClient.MultipartUploadFromFile(bucketName, objectKey, file.Name(), MIN_PART_SIZE); err != nil {
+		t.Fatal(err)
+	}
+
+	if content, err := readObject(client.GetObject(bucketName, objectKey, nil)); err != nil ||
+		content == string(data) {
+
+		t.Error(util.FormatTest(method+" stored content", strconv.Itoa(len(content)), "ciphertext"))
+	}
+
+	if content, err := readObject(encryptionClient.GetObject(bucketName, objectKey, nil)); err != nil ||
+		content != string(data) {
+
+		t.Error(util.FormatTest(method+" GetObject", strconv.Itoa(len(content)), strconv.Itoa(len(data))))
+	}
+
+	// the range crosses the boundary of parts
+	start, end := MIN_PART_SIZE-5, MIN_PART_SIZE+20
+	getObjectRequest := GetObjectRequest{BucketName: bucketName, ObjectKey: objectKey}
+	getObjectRequest.SetRange(uint(start), uint(end))
+
+	if content, err := readObject(encryptionClient.GetObjectFromRequest(getObjectRequest, nil)); err != nil ||
+		content != string(data[start:end+1]) {
+
+		t.Error(util.FormatTest(method+" GetObjectFromRequest with range", content, string(data[start:end+1])))
+	}
+
+	if _, err := encryptionClient.InitiateEncryptionMultipartUpload(bucketName, objectKey, int64(len(data)),
+		MIN_PART_SIZE+1, nil); err == nil {
+
+		t.Error(util.FormatTest(method+" invalid part size", "nil", "error"))
+	}
+
+	upload, err := encryptionClient.InitiateEncryptionMultipartUpload(bucketName, objectKey, int64(len(data)),
+		MIN_PART_SIZE, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parts []PartSummary
+
+	// the parts are uploaded in reverse order, each part is encrypted by its own offset
+	for partNumber := 3; partNumber > 0; partNumber-- {
+		offset := MIN_PART_SIZE * int64(partNumber-1)
+		partData := data[offset:]
+
+		if int64(len(partData)) > MIN_PART_SIZE {
+			partData = partData[:MIN_PART_SIZE]
+		}
+
+		uploadPartResponse, err := upload.UploadPart(partNumber, bytes.NewReader(partData), int64(len(partData)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		parts = append([]PartSummary{{PartNumber: partNumber, ETag: uploadPartResponse.GetETag()}}, parts...)
+	}
+
+	if _, err := upload.Complete(parts); err != nil {
+		t.Fatal(err)
+	}
+
+	getObjectRequest.SetRangeFrom(MIN_PART_SIZE * 2)
+
+	if content, err := readObject(encryptionClient.GetObjectFromRequest(getObjectRequest, nil)); err != nil ||
+		content != string(data[MIN_PART_SIZE*2:]) {
+
+		t.Error(util.FormatTest(method+" UploadPart", content, string(data[MIN_PART_SIZE*2:])))
+	}
+
+	upload, err = encryptionClient.InitiateEncryptionMultipartUpload(bucketName, objectKey, int64(len(data)),
+		MIN_PART_SIZE, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := upload.UploadPart(0, bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error(util.FormatTest(method+" invalid part number", "nil", "error"))
+	}
+
+	if err := upload.Abort(); err != nil {
+		t.Fatal(err)
+	}
+
+	listMultipartUploadsResponse, err := client.ListMultipartUploads(bucketName, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if length := len(listMultipartUploadsResponse.Uploads); length != 0 {
+		t.Error(util.FormatTest(method+" Abort", strconv.Itoa(length), "0"))
+	}
+}
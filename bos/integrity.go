@@ -0,0 +1,178 @@
+package bos
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// CHECKSUM is the algorithm used by integrity verification.
+const CHECKSUM_MD5 = "MD5"
+const CHECKSUM_CRC64 = "CRC64"
+
+// OBJECT_TYPE_NORMAL is the type of BOS Object created by PutObject or CopyObject, its ETag is the MD5 of content.
+const OBJECT_TYPE_NORMAL = "Normal"
+
+var md5ETagRegexp = regexp.MustCompile("^[0-9a-fA-F]{32}$")
+
+// IntegrityError is returned when the checksum of the data sent or received
+// is not the same as the checksum returned by BOS.
+type IntegrityError struct {
+	BucketName, ObjectKey string
+	Checksum              string
+	Expected, Actual      string
+}
+
+// Error returns the formatted error message.
+func (err *IntegrityError) Error() string {
+	return fmt.Sprintf("Integrity check failed for %s/%s, %s expected %s, got %s.",
+		err.BucketName, err.ObjectKey, err.Checksum, err.Expected, err.Actual)
+}
+
+// wrapChecksumReader replaces the body of req with a util.ChecksumReader, the content length is kept.
+func wrapChecksumReader(req *bce.Request) *util.ChecksumReader {
+	if req.Body == nil {
+		return nil
+	}
+
+	checksumReader := util.NewChecksumReader(req.Body)
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{checksumReader, req.Body}
+
+	return checksumReader
+}
+
+// seekableChecksum reads the first size bytes of a seekable reader, or all bytes if size is not positive,
+// to compute the checksum before uploading, and then seeks the reader back to its beginning.
+func seekableChecksum(r io.ReadSeeker, size int64) (*util.ChecksumReader, error) {
+	if _, err := r.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = r
+
+	if size > 0 {
+		reader = io.LimitReader(r, size)
+	}
+
+	checksumReader := util.NewChecksumReader(reader)
+
+	if _, err := io.Copy(ioutil.Discard, checksumReader); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	return checksumReader, nil
+}
+
+// verifyUpload compares the checksum of the data sent with the ETag and CRC64 returned by BOS,
+// the ETag is only compared if verifyETag is true.
+func verifyUpload(bucketName, objectKey string, checksumReader *util.ChecksumReader, h http.Header,
+	verifyETag bool) error {
+
+	if checksumReader == nil {
+		return nil
+	}
+
+	// the ETag of BOS Object encrypted with key provided by customer is not the MD5 of content
+	if verifyETag && h.Get("x-bce-"+sseCustomerAlgorithmHeader) == "" {
+		if etag := NewObjectMetadataFromHeader(h).ETag; md5ETagRegexp.MatchString(etag) &&
+			etag != checksumReader.MD5() {
+
+			return &IntegrityError{bucketName, objectKey, CHECKSUM_MD5, etag, checksumReader.MD5()}
+		}
+	}
+
+	return verifyCRC64(bucketName, objectKey, h.Get("x-bce-content-crc64ecma"), checksumReader.CRC64())
+}
+
+func verifyCRC64(bucketName, objectKey, expected string, crc64 uint64) error {
+	if expected == "" {
+		return nil
+	}
+
+	if actual := strconv.FormatUint(crc64, 10); actual != expected {
+		return &IntegrityError{bucketName, objectKey, CHECKSUM_CRC64, expected, actual}
+	}
+
+	return nil
+}
+
+// integrityReadCloser verifies the content of a BOS Object while it is read,
+// an IntegrityError is returned instead of io.EOF if the content is corrupted.
+type integrityReadCloser struct {
+	io.Closer
+	checksumReader        *util.ChecksumReader
+	bucketName, objectKey string
+	md5, crc64            string
+	verified              bool
+}
+
+// newIntegrityReadCloser returns a reader which verifies the body of resp by Content-MD5, CRC64 and ETag of
+// normal BOS Object. The body is returned as is for partial content, or if there is no checksum to verify.
+func newIntegrityReadCloser(bucketName, objectKey string, resp *bce.Response) io.ReadCloser {
+	if resp.StatusCode != http.StatusOK || resp.Uncompressed {
+		return resp.Body
+	}
+
+	metadata := NewObjectMetadataFromHeader(resp.Header)
+	r := &integrityReadCloser{
+		Closer:     resp.Body,
+		bucketName: bucketName,
+		objectKey:  objectKey,
+		crc64:      metadata.ContentCrc64,
+	}
+
+	if metadata.ContentMD5 != "" {
+		if byteArray, err := base64.StdEncoding.DecodeString(metadata.ContentMD5); err == nil {
+			r.md5 = hex.EncodeToString(byteArray)
+		}
+	} else if metadata.ObjectType == OBJECT_TYPE_NORMAL && md5ETagRegexp.MatchString(metadata.ETag) &&
+		(metadata.ServerSideEncryption == nil || !metadata.ServerSideEncryption.IsCustomerKey()) {
+
+		r.md5 = metadata.ETag
+	}
+
+	if r.md5 == "" && r.crc64 == "" {
+		return resp.Body
+	}
+
+	r.checksumReader = util.NewChecksumReader(resp.Body)
+
+	return r
+}
+
+func (r *integrityReadCloser) Read(p []byte) (int, error) {
+	n, err := r.checksumReader.Read(p)
+
+	if err == io.EOF && !r.verified {
+		r.verified = true
+
+		if verifyError := r.verify(); verifyError != nil {
+			return n, verifyError
+		}
+	}
+
+	return n, err
+}
+
+func (r *integrityReadCloser) verify() error {
+	if r.md5 != "" && r.md5 != r.checksumReader.MD5() {
+		return &IntegrityError{r.bucketName, r.objectKey, CHECKSUM_MD5, r.md5, r.checksumReader.MD5()}
+	}
+
+	return verifyCRC64(r.bucketName, r.objectKey, r.crc64, r.checksumReader.CRC64())
+}
@@ -0,0 +1,301 @@
+package bos
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func newIntegrityTestResponse(statusCode int, header http.Header, body string) *bce.Response {
+	return bce.NewResponse(&http.Response{
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	})
+}
+
+func TestVerifyUpload(t *testing.T) {
+	str := "Hello World"
+	checksumReader := util.NewChecksumReader(strings.NewReader(str))
+	ioutil.ReadAll(checksumReader)
+
+	etag, _ := util.GetMD5(str, false)
+	header := http.Header{
+		"Etag":                    []string{"\"" + etag + "\""},
+		"X-Bce-Content-Crc64ecma": []string{strconv.FormatUint(util.GetCRC64([]byte(str)), 10)},
+	}
+
+	if err := verifyUpload("bucket-0", "a.txt", checksumReader, header, true); err != nil {
+		t.Error(util.FormatTest("verifyUpload", err.Error(), "nil"))
+	}
+
+	header.Set("Etag", "\"00000000000000000000000000000000\"")
+
+	if err, ok := verifyUpload("bucket-0", "a.txt", checksumReader, header, true).(*IntegrityError); !ok ||
+		err.Checksum != CHECKSUM_MD5 {
+
+		t.Error(util.FormatTest("verifyUpload", "not IntegrityError of MD5", "IntegrityError of MD5"))
+	}
+
+	if err := verifyUpload("bucket-0", "a.txt", checksumReader, header, false); err != nil {
+		t.Error(util.FormatTest("verifyUpload", err.Error(), "nil"))
+	}
+
+	header.Set("X-Bce-Content-Crc64ecma", "1")
+
+	if err, ok := verifyUpload("bucket-0", "a.txt", checksumReader, header, false).(*IntegrityError); !ok ||
+		err.Checksum != CHECKSUM_CRC64 {
+
+		t.Error(util.FormatTest("verifyUpload", "not IntegrityError of CRC64", "IntegrityError of CRC64"))
+	}
+}
+
+func TestIntegrityReadCloser(t *testing.T) {
+	str := "Hello World"
+	etag, _ := util.GetMD5(str, false)
+	header := http.Header{
+		"Etag":              []string{"\"" + etag + "\""},
+		"X-Bce-Object-Type": []string{OBJECT_TYPE_NORMAL},
+	}
+
+	byteArray, err := ioutil.ReadAll(newIntegrityReadCloser("bucket-0", "a.txt",
+		newIntegrityTestResponse(http.StatusOK, header, str)))
+
+	if err != nil || string(byteArray) != str {
+		t.Error(util.FormatTest("integrityReadCloser", string(byteArray), str))
+	}
+
+	_, err = ioutil.ReadAll(newIntegrityReadCloser("bucket-0", "a.txt",
+		newIntegrityTestResponse(http.StatusOK, header, "Hello world")))
+
+	if _, ok := err.(*IntegrityError); !ok {
+		t.Error(util.FormatTest("integrityReadCloser", "not IntegrityError", "IntegrityError"))
+	}
+
+	_, err = ioutil.ReadAll(newIntegrityReadCloser("bucket-0", "a.txt",
+		newIntegrityTestResponse(http.StatusPartialContent, header, "Hello")))
+
+	if err != nil {
+		t.Error(util.FormatTest("integrityReadCloser", err.Error(), "nil"))
+	}
+
+	header = http.Header{"X-Bce-Content-Crc64ecma": []string{"1"}}
+	_, err = ioutil.ReadAll(newIntegrityReadCloser("bucket-0", "a.txt",
+		newIntegrityTestResponse(http.StatusOK, header, str)))
+
+	if _, ok := err.(*IntegrityError); !ok {
+		t.Error(util.FormatTest("integrityReadCloser", "not IntegrityError", "IntegrityError"))
+	}
+}
+
+func TestUploadChecksumHeaders(t *testing.T) {
+	bucketName := "upload-checksum-headers"
+	objectKey := "part.bin"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	client.Checksum = true
+	data := "Hello World 你好"
+	expectedMD5, _ := util.GetMD5(data, true)
+	expectedSha256, _ := util.GetSha256(data)
+
+	lastRequest := func() *bostest.RecordedRequest {
+		requests := server.Requests()
+		return requests[len(requests)-1]
+	}
+
+	checkHeaders := func(name string, expected bool) {
+		request := lastRequest()
+		contentMD5, sha256Value := request.Header.Get("Content-MD5"), request.Header.Get("x-bce-content-sha256")
+
+		if expected && (contentMD5 != expectedMD5 || sha256Value != expectedSha256) {
+			t.Error(util.FormatTest(name, contentMD5+" "+sha256Value, expectedMD5+" "+expectedSha256))
+		} else if !expected && contentMD5 != "" {
+			t.Error(util.FormatTest(name, contentMD5, "no Content-MD5"))
+		}
+	}
+
+	// the reader which is not seekable is buffered to compute the checksum if Checksum is enabled
+	_, err := client.PutObject(bucketName, objectKey, ioutil.NopCloser(strings.NewReader(data)), nil, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders("PutObject not seekable", true)
+
+	// the MD5 and SHA-256 of the seekable reader are computed in the same pass
+	if _, err := client.PutObject(bucketName, objectKey, strings.NewReader(data), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders("PutObject seekable", true)
+
+	// the reader is sent as is and verified by the ETag if Checksum is disabled
+	client.Checksum = false
+
+	if _, err := client.PutObject(bucketName, objectKey, strings.NewReader(data), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders("PutObject without Checksum", false)
+
+	if _, err := client.PutObject(bucketName, objectKey, data, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if contentMD5 := lastRequest().Header.Get("Content-MD5"); contentMD5 != expectedMD5 {
+		t.Error(util.FormatTest("PutObject string without Checksum", contentMD5, expectedMD5))
+	}
+
+	client.Checksum = true
+
+	initiateMultipartUploadResponse, err := client.InitiateMultipartUpload(InitiateMultipartUploadRequest{
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+	}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploadPartRequest := UploadPartRequest{
+		BucketName: bucketName,
+		ObjectKey:  objectKey,
+		UploadId:   initiateMultipartUploadResponse.UploadId,
+		PartSize:   int64(len(data)),
+		PartNumber: 1,
+		PartData:   strings.NewReader(data),
+	}
+
+	if _, err := client.UploadPart(uploadPartRequest, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders("UploadPart seekable", true)
+
+	// the part which is not seekable is verified by its ETag after uploading
+	uploadPartRequest.PartNumber = 2
+	uploadPartRequest.PartData = ioutil.NopCloser(strings.NewReader(data))
+
+	if _, err := client.UploadPart(uploadPartRequest, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	checkHeaders("UploadPart not seekable", false)
+}
+
+func TestMultipartUploadFromFileCleanup(t *testing.T) {
+	method := "MultipartUploadFromFile"
+	bucketName := "multipart-upload-from-file"
+	objectKey := "large.bin"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	file, err := ioutil.TempFile("", "bos-multipart")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(file.Name())
+
+	data := strings.Repeat("0123456789", 100)
+	file.WriteString(data)
+	file.Close()
+
+	home, err := util.HomeDir()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the temp files of parts are created in the tmp directory of home
+	countTempFiles := func() int {
+		fileInfos, _ := ioutil.ReadDir(path.Join(home, "tmp"))
+		return len(fileInfos)
+	}
+
+	tempFiles := countTempFiles()
+
+	completeMultipartUploadResponse, err := client.MultipartUploadFromFile(bucketName, objectKey, file.Name(), 300)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := client.GetObject(bucketName, objectKey, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadAll(object.ObjectContent)
+	object.ObjectContent.Close()
+
+	if err != nil || string(content) != data || completeMultipartUploadResponse.ETag == "" {
+		t.Error(util.FormatTest(method, strconv.Itoa(len(content)), strconv.Itoa(len(data))))
+	}
+
+	// the parts are sent with their checksum
+	for _, request := range server.Requests() {
+		if request.Query.Get("partNumber") != "" && request.Header.Get("Content-MD5") == "" {
+			t.Error(util.FormatTest(method+" Content-MD5", "empty", "the MD5 of part"))
+		}
+	}
+
+	checkAborted := func(name string) {
+		listMultipartUploadsResponse, err := client.ListMultipartUploads(bucketName, nil)
+
+		if err != nil {
+			t.Error(util.FormatTest(name, err.Error(), "nil"))
+		} else if length := len(listMultipartUploadsResponse.Uploads); length != 0 {
+			t.Error(util.FormatTest(name+" uploads left", strconv.Itoa(length), "0"))
+		}
+
+		if count := countTempFiles(); count != tempFiles {
+			t.Error(util.FormatTest(name+" temp files left", strconv.Itoa(count-tempFiles), "0"))
+		}
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "PUT",
+		Param:      "partNumber",
+		Nth:        2,
+		StatusCode: http.StatusForbidden,
+		Code:       "AccessDenied",
+	})
+
+	if _, err := client.MultipartUploadFromFile(bucketName, objectKey, file.Name(), 300); err == nil {
+		t.Error(util.FormatTest(method+" part failed", "nil", "error"))
+	}
+
+	checkAborted(method + " part failed")
+
+	// a directory can be opened, but it fails to be read while the parts are copied
+	dir, err := ioutil.TempDir("", "bos-multipart")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	if fileInfo, err := os.Stat(dir); err != nil || fileInfo.Size() == 0 {
+		t.Skip("the size of directory is 0, the read error can not be tested")
+	}
+
+	if _, err := client.MultipartUploadFromFile(bucketName, objectKey, dir, 300); err == nil {
+		t.Error(util.FormatTest(method+" read failed", "nil", "error"))
+	}
+
+	checkAborted(method + " read failed")
+}
@@ -0,0 +1,367 @@
+package bos
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// LIFECYCLE_ACTION is the action name of a lifecycle rule.
+//
+// For details, please refer https://cloud.baidu.com/doc/BOS/API.html#PutBucketlifecycle
+const LIFECYCLE_ACTION_TRANSITION = "Transition"
+const LIFECYCLE_ACTION_DELETE_OBJECT = "DeleteObject"
+const LIFECYCLE_ACTION_ABORT_MULTIPART_UPLOAD = "AbortMultipartUpload"
+
+// LifecycleLastModifiedVariable is the variable of lifecycle condition which refers to
+// the last modified time of BOS Object, or the initiated time of multipart upload.
+const LifecycleLastModifiedVariable = "$(lastModified)"
+
+var lifecycleDaysPattern = regexp.MustCompile(`^\$\(lastModified\)\+P(\d+)D$`)
+
+// LifecycleDaysCondition returns a condition which matches the objects modified days ago,
+// such as "$(lastModified)+P30D".
+func LifecycleDaysCondition(days int) BucketLifecycleItemCondition {
+	return BucketLifecycleItemCondition{
+		Time: BucketLifecycleItemConditionTime{
+			DateGreaterThan: fmt.Sprintf("%s+P%dD", LifecycleLastModifiedVariable, days),
+		},
+	}
+}
+
+// LifecycleDateCondition returns a condition which matches all objects after the specified date,
+// only the date part (in UTC) of t is used.
+func LifecycleDateCondition(t time.Time) BucketLifecycleItemCondition {
+	return BucketLifecycleItemCondition{
+		Time: BucketLifecycleItemConditionTime{
+			DateGreaterThan: t.UTC().Format("2006-01-02") + "T00:00:00Z",
+		},
+	}
+}
+
+// Days returns the days of a condition like "$(lastModified)+P30D".
+func (conditionTime BucketLifecycleItemConditionTime) Days() (int, bool) {
+	matches := lifecycleDaysPattern.FindStringSubmatch(conditionTime.DateGreaterThan)
+
+	if matches == nil {
+		return 0, false
+	}
+
+	days, err := strconv.Atoi(matches[1])
+
+	if err != nil {
+		return 0, false
+	}
+
+	return days, true
+}
+
+// Date returns the date of a condition like "2017-01-30T00:00:00Z".
+func (conditionTime BucketLifecycleItemConditionTime) Date() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, conditionTime.DateGreaterThan)
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// LifecycleResource returns a resource of lifecycle rule which matches all objects under the prefix.
+func LifecycleResource(bucketName, prefix string) string {
+	return bucketName + "/" + prefix + "*"
+}
+
+// lifecycleResourcePrefix returns the key prefix of a resource like "bucket/prefix*",
+// and whether it is a prefix or an exact key.
+func lifecycleResourcePrefix(resource string) (string, bool) {
+	key := resource
+
+	if index := strings.Index(resource, "/"); index >= 0 {
+		key = resource[index+1:]
+	}
+
+	if strings.HasSuffix(key, "*") {
+		return strings.TrimSuffix(key, "*"), true
+	}
+
+	return key, false
+}
+
+// BucketLifecycleRuleBuilder builds a bos.BucketLifecycleItem fluently, for example:
+//
+//	rule, err := bos.NewBucketLifecycleRuleBuilder("archive-logs").
+//		Prefix(bucketName, "logs/").
+//		AfterDays(30).
+//		TransitionTo(bos.STORAGE_CLASS_COLD).
+//		Build()
+type BucketLifecycleRuleBuilder struct {
+	rule BucketLifecycleItem
+}
+
+// NewBucketLifecycleRuleBuilder returns a builder of an enabled rule.
+func NewBucketLifecycleRuleBuilder(id string) *BucketLifecycleRuleBuilder {
+	return &BucketLifecycleRuleBuilder{
+		rule: BucketLifecycleItem{Id: id, Status: STATUS_ENABLED},
+	}
+}
+
+// Disabled sets the status of rule to disabled.
+func (builder *BucketLifecycleRuleBuilder) Disabled() *BucketLifecycleRuleBuilder {
+	builder.rule.Status = STATUS_DISABLED
+	return builder
+}
+
+// Prefix adds a resource which matches all objects under the prefix of BOS Bucket.
+func (builder *BucketLifecycleRuleBuilder) Prefix(bucketName, prefix string) *BucketLifecycleRuleBuilder {
+	builder.rule.Resource = append(builder.rule.Resource, LifecycleResource(bucketName, prefix))
+	return builder
+}
+
+// AfterDays sets the condition to the days after last modified.
+func (builder *BucketLifecycleRuleBuilder) AfterDays(days int) *BucketLifecycleRuleBuilder {
+	builder.rule.Condition = LifecycleDaysCondition(days)
+	return builder
+}
+
+// AfterDate sets the condition to the specified date.
+func (builder *BucketLifecycleRuleBuilder) AfterDate(t time.Time) *BucketLifecycleRuleBuilder {
+	builder.rule.Condition = LifecycleDateCondition(t)
+	return builder
+}
+
+// TransitionTo sets the action to transition objects to the storage class.
+func (builder *BucketLifecycleRuleBuilder) TransitionTo(storageClass string) *BucketLifecycleRuleBuilder {
+	builder.rule.Action = BucketLifecycleItemAction{Name: LIFECYCLE_ACTION_TRANSITION, StorageClass: storageClass}
+	return builder
+}
+
+// DeleteObject sets the action to delete objects.
+func (builder *BucketLifecycleRuleBuilder) DeleteObject() *BucketLifecycleRuleBuilder {
+	builder.rule.Action = BucketLifecycleItemAction{Name: LIFECYCLE_ACTION_DELETE_OBJECT}
+	return builder
+}
+
+// AbortMultipartUpload sets the action to abort incomplete multipart uploads.
+func (builder *BucketLifecycleRuleBuilder) AbortMultipartUpload() *BucketLifecycleRuleBuilder {
+	builder.rule.Action = BucketLifecycleItemAction{Name: LIFECYCLE_ACTION_ABORT_MULTIPART_UPLOAD}
+	return builder
+}
+
+// Build returns the rule after validating it.
+func (builder *BucketLifecycleRuleBuilder) Build() (BucketLifecycleItem, error) {
+	return builder.rule, builder.rule.validate("", "rule")
+}
+
+// validate checks a single rule, the resources are checked against bucketName if it is not empty,
+// and field is the name of rule in the returned bce.ValidationError, such as "rule[0]".
+func (rule *BucketLifecycleItem) validate(bucketName, field string) error {
+	if rule.Status != STATUS_ENABLED && rule.Status != STATUS_DISABLED {
+		return bce.NewValidationError(field+".status", fmt.Sprintf("The status %s of rule %s is not supported.",
+			rule.Status, rule.Id))
+	}
+
+	if len(rule.Resource) == 0 {
+		return bce.NewValidationError(field+".resource", fmt.Sprintf("The resource of rule %s should not be empty.",
+			rule.Id))
+	}
+
+	for _, resource := range rule.Resource {
+		if bucketName != "" && !strings.HasPrefix(resource, bucketName+"/") {
+			return bce.NewValidationError(field+".resource", fmt.Sprintf(
+				"The resource %s of rule %s should start with %s/.", resource, rule.Id, bucketName))
+		}
+
+		if bucketName == "" && !strings.Contains(resource, "/") {
+			return bce.NewValidationError(field+".resource", fmt.Sprintf(
+				"The resource %s of rule %s should be in the form of bucket/prefix*.", resource, rule.Id))
+		}
+	}
+
+	conditionTime := rule.Condition.Time
+
+	if days, ok := conditionTime.Days(); ok {
+		if days <= 0 {
+			return bce.NewValidationError(field+".condition", fmt.Sprintf(
+				"The days of condition of rule %s should be positive.", rule.Id))
+		}
+	} else if date, ok := conditionTime.Date(); ok {
+		if date.UTC().Hour() != 0 || date.UTC().Minute() != 0 || date.UTC().Second() != 0 {
+			return bce.NewValidationError(field+".condition", fmt.Sprintf(
+				"The date of condition of rule %s should be at midnight of UTC.", rule.Id))
+		}
+	} else {
+		return bce.NewValidationError(field+".condition", fmt.Sprintf("The condition %s of rule %s is malformed.",
+			conditionTime.DateGreaterThan, rule.Id))
+	}
+
+	switch rule.Action.Name {
+	case LIFECYCLE_ACTION_TRANSITION:
+		if rule.Action.StorageClass == STORAGE_CLASS_STANDARD || !isStorageClass(rule.Action.StorageClass) {
+			return bce.NewValidationError(field+".action", fmt.Sprintf(
+				"The storage class %s of rule %s is not supported for transition.", rule.Action.StorageClass, rule.Id))
+		}
+	case LIFECYCLE_ACTION_DELETE_OBJECT, LIFECYCLE_ACTION_ABORT_MULTIPART_UPLOAD:
+		if rule.Action.StorageClass != "" {
+			return bce.NewValidationError(field+".action", fmt.Sprintf(
+				"The storage class of rule %s should be empty for %s.", rule.Id, rule.Action.Name))
+		}
+	default:
+		return bce.NewValidationError(field+".action", fmt.Sprintf("The action %s of rule %s is not supported.",
+			rule.Action.Name, rule.Id))
+	}
+
+	return nil
+}
+
+// Validate checks all rules of lifecycle configuration of a BOS Bucket, and the conflicts between
+// enabled rules, including duplicate actions on the same resource, and transitions after deletion or
+// transitions to a warmer storage class after a colder one on overlapping resources.
+//
+// It returns a bce.ValidationError whose field is the invalid part of a rule, such as "rule[0].resource".
+func (lifecycle *BucketLifecycle) Validate(bucketName string) error {
+	if len(lifecycle.Rule) == 0 {
+		return bce.NewValidationError("rule", "The lifecycle rule should not be empty.")
+	}
+
+	ids := make(map[string]bool, len(lifecycle.Rule))
+
+	for index := range lifecycle.Rule {
+		rule := &lifecycle.Rule[index]
+		field := fmt.Sprintf("rule[%d]", index)
+
+		if rule.Id != "" {
+			if ids[rule.Id] {
+				return bce.NewValidationError(field+".id", fmt.Sprintf("The rule id %s is duplicate.", rule.Id))
+			}
+
+			ids[rule.Id] = true
+		}
+
+		if err := rule.validate(bucketName, field); err != nil {
+			return err
+		}
+	}
+
+	for i := range lifecycle.Rule {
+		for j := i + 1; j < len(lifecycle.Rule); j++ {
+			if err := checkLifecycleConflict(&lifecycle.Rule[i], &lifecycle.Rule[j],
+				fmt.Sprintf("rule[%d].action", i), fmt.Sprintf("rule[%d].action", j)); err != nil {
+
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkLifecycleConflict checks the conflict between two rules, aField and bField are the names of
+// their actions in the returned bce.ValidationError.
+func checkLifecycleConflict(a, b *BucketLifecycleItem, aField, bField string) error {
+	if a.Status != STATUS_ENABLED || b.Status != STATUS_ENABLED {
+		return nil
+	}
+
+	overlap, identical := lifecycleResourcesOverlap(a, b)
+
+	if !overlap {
+		return nil
+	}
+
+	if identical && a.Action.Name == b.Action.Name && a.Action.StorageClass == b.Action.StorageClass {
+		return bce.NewValidationError(bField, fmt.Sprintf(
+			"The rule %s and rule %s have the same action %s on the same resource.", a.Id, b.Id, a.Action.Name))
+	}
+
+	aDays, aOk := a.Condition.Time.Days()
+	bDays, bOk := b.Condition.Time.Days()
+
+	if !aOk || !bOk {
+		return nil
+	}
+
+	if err := checkLifecycleOrder(a, aDays, b, bDays, bField); err != nil {
+		return err
+	}
+
+	return checkLifecycleOrder(b, bDays, a, aDays, aField)
+}
+
+// checkLifecycleOrder checks whether the later rule is meaningless after the earlier rule takes effect
+// on all objects matched by the later rule, field is the name of action of the later rule.
+func checkLifecycleOrder(earlier *BucketLifecycleItem, earlierDays int, later *BucketLifecycleItem, laterDays int,
+	field string) error {
+
+	if laterDays < earlierDays || later.Action.Name != LIFECYCLE_ACTION_TRANSITION ||
+		!lifecycleResourcesCover(earlier, later) {
+		return nil
+	}
+
+	if earlier.Action.Name == LIFECYCLE_ACTION_DELETE_OBJECT {
+		return bce.NewValidationError(field, fmt.Sprintf("The rule %s transitions objects after rule %s deletes them.",
+			later.Id, earlier.Id))
+	}
+
+	if earlier.Action.Name == LIFECYCLE_ACTION_TRANSITION &&
+		storageClassRank(later.Action.StorageClass) < storageClassRank(earlier.Action.StorageClass) {
+		return bce.NewValidationError(field, fmt.Sprintf(
+			"The rule %s transitions objects to %s after rule %s transitions them to %s.",
+			later.Id, later.Action.StorageClass, earlier.Id, earlier.Action.StorageClass))
+	}
+
+	return nil
+}
+
+// lifecycleResourcesOverlap reports whether two rules match some common objects,
+// and whether they have an identical resource.
+func lifecycleResourcesOverlap(a, b *BucketLifecycleItem) (bool, bool) {
+	overlap := false
+
+	for _, resourceA := range a.Resource {
+		prefixA, wildcardA := lifecycleResourcePrefix(resourceA)
+
+		for _, resourceB := range b.Resource {
+			prefixB, wildcardB := lifecycleResourcePrefix(resourceB)
+
+			if prefixA == prefixB && wildcardA == wildcardB {
+				return true, true
+			}
+
+			if prefixA == prefixB ||
+				(wildcardA && strings.HasPrefix(prefixB, prefixA)) ||
+				(wildcardB && strings.HasPrefix(prefixA, prefixB)) {
+				overlap = true
+			}
+		}
+	}
+
+	return overlap, false
+}
+
+// lifecycleResourcesCover reports whether the objects matched by rule b are all matched by rule a.
+func lifecycleResourcesCover(a, b *BucketLifecycleItem) bool {
+	for _, resourceB := range b.Resource {
+		prefixB, wildcardB := lifecycleResourcePrefix(resourceB)
+		covered := false
+
+		for _, resourceA := range a.Resource {
+			prefixA, wildcardA := lifecycleResourcePrefix(resourceA)
+
+			if (prefixA == prefixB && (wildcardA || !wildcardB)) || (wildcardA && strings.HasPrefix(prefixB, prefixA)) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
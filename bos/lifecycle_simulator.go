@@ -0,0 +1,236 @@
+package bos
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// LifecycleSimulationAction defined a struct for the action which a lifecycle rule would take on a BOS Object.
+type LifecycleSimulationAction struct {
+	Key          string
+	Size         int64
+	RuleId       string
+	Action       string
+	StorageClass string
+}
+
+// Target returns the target storage class of a transition, or the action name for other actions.
+func (action *LifecycleSimulationAction) Target() string {
+	if action.Action == LIFECYCLE_ACTION_TRANSITION {
+		return action.StorageClass
+	}
+
+	return action.Action
+}
+
+// LifecycleSimulationReport defined a struct for the summary of a lifecycle simulation.
+//
+// Counts and Bytes are keyed by bos.LifecycleSimulationAction.Target, that is the target storage class
+// of transitions or LIFECYCLE_ACTION_DELETE_OBJECT. RuleCounts is keyed by rule id.
+type LifecycleSimulationReport struct {
+	Date       time.Time
+	Total      int
+	TotalBytes int64
+	Unaffected int
+	Counts     map[string]int
+	Bytes      map[string]int64
+	RuleCounts map[string]int
+}
+
+// LifecycleSimulator evaluates the rules of a bos.BucketLifecycle against BOS Objects locally,
+// to show what the lifecycle configuration would do at a given date before it is set.
+type LifecycleSimulator struct {
+	bucketName string
+	lifecycle  BucketLifecycle
+	report     *LifecycleSimulationReport
+}
+
+// NewLifecycleSimulator returns a simulator for the date after validating the lifecycle configuration.
+func NewLifecycleSimulator(bucketName string, lifecycle BucketLifecycle, date time.Time) (*LifecycleSimulator, error) {
+	if err := lifecycle.Validate(bucketName); err != nil {
+		return nil, err
+	}
+
+	return &LifecycleSimulator{
+		bucketName: bucketName,
+		lifecycle:  lifecycle,
+		report: &LifecycleSimulationReport{
+			Date:       date,
+			Counts:     make(map[string]int),
+			Bytes:      make(map[string]int64),
+			RuleCounts: make(map[string]int),
+		},
+	}, nil
+}
+
+// Evaluate returns the action would be taken on the BOS Object, or nil if no rule takes effect,
+// and adds the result to the report.
+//
+// Deletion takes precedence over transitions, and the coldest storage class wins among transitions.
+// Transitions to the current or a warmer storage class of the object are ignored.
+func (simulator *LifecycleSimulator) Evaluate(objectSummary ObjectSummary) *LifecycleSimulationAction {
+	var result *LifecycleSimulationAction
+
+	for index := range simulator.lifecycle.Rule {
+		rule := &simulator.lifecycle.Rule[index]
+
+		if rule.Status != STATUS_ENABLED || rule.Action.Name == LIFECYCLE_ACTION_ABORT_MULTIPART_UPLOAD {
+			continue
+		}
+
+		if !lifecycleRuleMatches(rule, objectSummary.Key) || !simulator.isConditionMet(rule, objectSummary) {
+			continue
+		}
+
+		if rule.Action.Name == LIFECYCLE_ACTION_TRANSITION &&
+			storageClassRank(rule.Action.StorageClass) <= storageClassRank(objectSummary.StorageClass) {
+			continue
+		}
+
+		action := &LifecycleSimulationAction{
+			Key:          objectSummary.Key,
+			Size:         objectSummary.Size,
+			RuleId:       rule.Id,
+			Action:       rule.Action.Name,
+			StorageClass: rule.Action.StorageClass,
+		}
+
+		if result == nil || lifecycleActionRank(action) > lifecycleActionRank(result) {
+			result = action
+		}
+	}
+
+	report := simulator.report
+	report.Total++
+	report.TotalBytes += objectSummary.Size
+
+	if result == nil {
+		report.Unaffected++
+	} else {
+		report.Counts[result.Target()]++
+		report.Bytes[result.Target()] += result.Size
+		report.RuleCounts[result.RuleId]++
+	}
+
+	return result
+}
+
+// Report returns the summary of all evaluated BOS Objects.
+func (simulator *LifecycleSimulator) Report() *LifecycleSimulationReport {
+	return simulator.report
+}
+
+func (simulator *LifecycleSimulator) isConditionMet(rule *BucketLifecycleItem, objectSummary ObjectSummary) bool {
+	date := simulator.report.Date
+
+	if days, ok := rule.Condition.Time.Days(); ok {
+		lastModified, err := time.Parse(time.RFC3339, objectSummary.LastModified)
+
+		if err != nil {
+			return false
+		}
+
+		return !date.Before(lastModified.AddDate(0, 0, days))
+	}
+
+	if conditionDate, ok := rule.Condition.Time.Date(); ok {
+		return date.After(conditionDate)
+	}
+
+	return false
+}
+
+func lifecycleRuleMatches(rule *BucketLifecycleItem, objectKey string) bool {
+	for _, resource := range rule.Resource {
+		prefix, wildcard := lifecycleResourcePrefix(resource)
+
+		if (wildcard && strings.HasPrefix(objectKey, prefix)) || (!wildcard && objectKey == prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lifecycleActionRank(action *LifecycleSimulationAction) int {
+	if action.Action == LIFECYCLE_ACTION_DELETE_OBJECT {
+		return 100
+	}
+
+	return storageClassRank(action.StorageClass)
+}
+
+// SimulateBucketLifecycle evaluates the lifecycle configuration against all BOS Objects under
+// the prefix of a BOS Bucket at the given date, f is called for each affected BOS Object if it is not nil.
+func (c *Client) SimulateBucketLifecycle(bucketName, prefix string, lifecycle BucketLifecycle, date time.Time,
+	f func(*LifecycleSimulationAction)) (*LifecycleSimulationReport, error) {
+
+	simulator, err := NewLifecycleSimulator(bucketName, lifecycle, date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	listObjectsRequest := ListObjectsRequest{BucketName: bucketName, Prefix: prefix}
+
+	for {
+		listObjectsResponse, err := c.ListObjectsFromRequest(listObjectsRequest, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, objectSummary := range listObjectsResponse.Contents {
+			if action := simulator.Evaluate(objectSummary); action != nil && f != nil {
+				f(action)
+			}
+		}
+
+		if !listObjectsResponse.IsTruncated || len(listObjectsResponse.Contents) == 0 {
+			break
+		}
+
+		listObjectsRequest.Marker = listObjectsResponse.NextMarker
+
+		if listObjectsRequest.Marker == "" {
+			listObjectsRequest.Marker = listObjectsResponse.Contents[len(listObjectsResponse.Contents)-1].Key
+		}
+	}
+
+	return simulator.Report(), nil
+}
+
+// ReadObjectSummaries reads a stream of JSON values from r, and calls f for each bos.ObjectSummary.
+//
+// Each value can be either a single object summary, or a whole bos.ListObjectsResponse which
+// is saved from the response of ListObjects, so JSON lines and concatenated listings are both supported.
+func ReadObjectSummaries(r io.Reader, f func(ObjectSummary) error) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		var value struct {
+			ObjectSummary
+			Contents []ObjectSummary
+		}
+
+		if err := decoder.Decode(&value); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		summaries := value.Contents
+
+		if value.Key != "" {
+			summaries = append(summaries, value.ObjectSummary)
+		}
+
+		for _, objectSummary := range summaries {
+			if err := f(objectSummary); err != nil {
+				return err
+			}
+		}
+	}
+}
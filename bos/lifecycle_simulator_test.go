@@ -0,0 +1,186 @@
+package bos
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestLifecycleSimulator(t *testing.T) {
+	bucketName := "bucket-0"
+	rule := func(id, prefix string, days int) *BucketLifecycleRuleBuilder {
+		return NewBucketLifecycleRuleBuilder(id).Prefix(bucketName, prefix).AfterDays(days)
+	}
+
+	lifecycle := BucketLifecycle{
+		Rule: []BucketLifecycleItem{
+			rule("ia", "logs/", 30).TransitionTo(STORAGE_CLASS_STANDARD_IA).rule,
+			rule("cold", "logs/", 90).TransitionTo(STORAGE_CLASS_COLD).rule,
+			rule("delete", "logs/", 365).DeleteObject().rule,
+			rule("abort", "", 7).AbortMultipartUpload().rule,
+		},
+	}
+
+	date := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	simulator, err := NewLifecycleSimulator(bucketName, lifecycle, date)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summaries := []ObjectSummary{
+		{Key: "logs/new.log", Size: 1, LastModified: "2017-05-20T00:00:00Z"},
+		{Key: "logs/ia.log", Size: 10, LastModified: "2017-04-01T00:00:00Z"},
+		{Key: "logs/cold.log", Size: 100, LastModified: "2017-01-01T00:00:00Z"},
+		{Key: "logs/cold-already.log", Size: 100, LastModified: "2017-01-01T00:00:00Z", StorageClass: STORAGE_CLASS_COLD},
+		{Key: "logs/old.log", Size: 1000, LastModified: "2016-01-01T00:00:00Z"},
+		{Key: "data/old.dat", Size: 1000, LastModified: "2016-01-01T00:00:00Z"},
+	}
+
+	expected := []string{"", "ia", "cold", "", "delete", ""}
+
+	for i, objectSummary := range summaries {
+		action := simulator.Evaluate(objectSummary)
+		ruleId := ""
+
+		if action != nil {
+			ruleId = action.RuleId
+		}
+
+		if ruleId != expected[i] {
+			t.Error(util.FormatTest("LifecycleSimulator: Evaluate "+objectSummary.Key, ruleId, expected[i]))
+		}
+	}
+
+	report := simulator.Report()
+
+	if report.Total != 6 || report.Unaffected != 3 {
+		t.Error(util.FormatTest("LifecycleSimulator: Report", strconv.Itoa(report.Unaffected), "3"))
+	}
+
+	if report.Counts[STORAGE_CLASS_COLD] != 1 || report.Bytes[STORAGE_CLASS_COLD] != 100 {
+		t.Error(util.FormatTest("LifecycleSimulator: Report",
+			strconv.FormatInt(report.Bytes[STORAGE_CLASS_COLD], 10), "100"))
+	}
+
+	if report.Bytes[LIFECYCLE_ACTION_DELETE_OBJECT] != 1000 {
+		t.Error(util.FormatTest("LifecycleSimulator: Report",
+			strconv.FormatInt(report.Bytes[LIFECYCLE_ACTION_DELETE_OBJECT], 10), "1000"))
+	}
+
+	if _, err := NewLifecycleSimulator(bucketName, BucketLifecycle{}, date); err == nil {
+		t.Error(util.FormatTest("NewLifecycleSimulator", "nil", "error"))
+	}
+}
+
+func TestSimulateBucketLifecycle(t *testing.T) {
+	method := "SimulateBucketLifecycle"
+	bucketName := "simulate-bucket-lifecycle"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	objects := map[string]string{"logs/a.log": "aaa", "logs/b.log": "bbbbb", "data/c.dat": "c"}
+
+	for objectKey, data := range objects {
+		if _, err := client.PutObject(bucketName, objectKey, data, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lifecycle := BucketLifecycle{
+		Rule: []BucketLifecycleItem{
+			NewBucketLifecycleRuleBuilder("ia").Prefix(bucketName, "logs/").AfterDays(30).
+				TransitionTo(STORAGE_CLASS_STANDARD_IA).rule,
+			NewBucketLifecycleRuleBuilder("cold").Prefix(bucketName, "logs/").AfterDays(90).
+				TransitionTo(STORAGE_CLASS_COLD).rule,
+		},
+	}
+
+	// the objects are just put, so they are 100 days old at the date
+	date := time.Now().AddDate(0, 0, 100)
+	var keys []string
+
+	report, err := client.SimulateBucketLifecycle(bucketName, "", lifecycle, date,
+		func(action *LifecycleSimulationAction) {
+			keys = append(keys, action.Key+":"+action.Target())
+		})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(keys)
+	expected := "logs/a.log:COLD,logs/b.log:COLD"
+
+	if strings.Join(keys, ",") != expected {
+		t.Error(util.FormatTest(method, strings.Join(keys, ","), expected))
+	}
+
+	if report.Total != 3 || report.Unaffected != 1 || report.Bytes[STORAGE_CLASS_COLD] != 8 ||
+		report.RuleCounts["cold"] != 2 {
+
+		t.Error(util.FormatTest(method+": Report", fmt.Sprintf("%+v", report),
+			"3 objects, 1 unaffected, 8 bytes by rule cold"))
+	}
+
+	report, err = client.SimulateBucketLifecycle(bucketName, "data/", lifecycle, date, nil)
+
+	if err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "nil"))
+	} else if report.Total != 1 || report.Unaffected != 1 {
+		t.Error(util.FormatTest(method+": prefix", strconv.Itoa(report.Unaffected), "1"))
+	}
+
+	if _, err := client.SimulateBucketLifecycle(bucketName, "", BucketLifecycle{}, date, nil); err == nil {
+		t.Error(util.FormatTest(method+": invalid lifecycle", "nil", "error"))
+	}
+
+	server.AddRule(&bostest.Rule{
+		Method:     "GET",
+		Path:       "/" + bucketName,
+		StatusCode: http.StatusForbidden,
+		Code:       "AccessDenied",
+	})
+
+	if _, err := client.SimulateBucketLifecycle(bucketName, "", lifecycle, date, nil); err == nil {
+		t.Error(util.FormatTest(method+": list error", "nil", "error"))
+	}
+}
+
+func TestReadObjectSummaries(t *testing.T) {
+	input := `{"key": "a.txt", "size": 1}
+{"key": "b.txt", "size": 2}
+{"name": "bucket-0", "contents": [{"key": "c.txt", "size": 3}, {"key": "d.txt", "size": 4}]}`
+
+	var keys []string
+	var size int64
+
+	err := ReadObjectSummaries(strings.NewReader(input), func(objectSummary ObjectSummary) error {
+		keys = append(keys, objectSummary.Key)
+		size += objectSummary.Size
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(keys, ",") != "a.txt,b.txt,c.txt,d.txt" {
+		t.Error(util.FormatTest("ReadObjectSummaries", strings.Join(keys, ","), "a.txt,b.txt,c.txt,d.txt"))
+	}
+
+	if size != 10 {
+		t.Error(util.FormatTest("ReadObjectSummaries", strconv.FormatInt(size, 10), "10"))
+	}
+
+	if err := ReadObjectSummaries(strings.NewReader("{"), func(ObjectSummary) error { return nil }); err == nil {
+		t.Error(util.FormatTest("ReadObjectSummaries", "nil", "error"))
+	}
+}
@@ -0,0 +1,159 @@
+package bos
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestLifecycleConditionTime(t *testing.T) {
+	condition := LifecycleDaysCondition(30)
+	expected := "$(lastModified)+P30D"
+
+	if condition.Time.DateGreaterThan != expected {
+		t.Error(util.FormatTest("LifecycleDaysCondition", condition.Time.DateGreaterThan, expected))
+	}
+
+	if days, ok := condition.Time.Days(); !ok || days != 30 {
+		t.Error(util.FormatTest("BucketLifecycleItemConditionTime: Days", strconv.Itoa(days), "30"))
+	}
+
+	if _, ok := condition.Time.Date(); ok {
+		t.Error(util.FormatTest("BucketLifecycleItemConditionTime: Date", "true", "false"))
+	}
+
+	condition = LifecycleDateCondition(time.Date(2017, 1, 30, 15, 4, 5, 0, time.UTC))
+	expected = "2017-01-30T00:00:00Z"
+
+	if condition.Time.DateGreaterThan != expected {
+		t.Error(util.FormatTest("LifecycleDateCondition", condition.Time.DateGreaterThan, expected))
+	}
+
+	if date, ok := condition.Time.Date(); !ok || date.Day() != 30 {
+		t.Error(util.FormatTest("BucketLifecycleItemConditionTime: Date", date.String(), expected))
+	}
+}
+
+func TestBucketLifecycleRuleBuilder(t *testing.T) {
+	rule, err := NewBucketLifecycleRuleBuilder("archive-logs").
+		Prefix("bucket-0", "logs/").
+		AfterDays(30).
+		TransitionTo(STORAGE_CLASS_COLD).
+		Build()
+
+	if err != nil {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder", err.Error(), "nil"))
+	}
+
+	if rule.Resource[0] != "bucket-0/logs/*" {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder", rule.Resource[0], "bucket-0/logs/*"))
+	}
+
+	if rule.Status != STATUS_ENABLED || rule.Action.Name != LIFECYCLE_ACTION_TRANSITION {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder", rule.Action.Name, LIFECYCLE_ACTION_TRANSITION))
+	}
+
+	date := time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC)
+	rule, err = NewBucketLifecycleRuleBuilder("delete-tmp").Prefix("bucket-0", "tmp/").AfterDate(date).
+		DeleteObject().Build()
+
+	if err != nil {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder: AfterDate", err.Error(), "nil"))
+	} else if ruleDate, ok := rule.Condition.Time.Date(); !ok || !ruleDate.Equal(date) {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder: AfterDate", rule.Condition.Time.DateGreaterThan,
+			"2017-06-01T00:00:00Z"))
+	}
+
+	_, err = NewBucketLifecycleRuleBuilder("invalid").Prefix("bucket-0", "").AfterDays(7).
+		TransitionTo(STORAGE_CLASS_STANDARD).Build()
+
+	if err == nil {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder", "nil", "error"))
+	}
+
+	_, err = NewBucketLifecycleRuleBuilder("invalid").Prefix("bucket-0", "").AfterDays(0).DeleteObject().Build()
+
+	if err == nil {
+		t.Error(util.FormatTest("BucketLifecycleRuleBuilder", "nil", "error"))
+	}
+}
+
+func TestValidateBucketLifecycle(t *testing.T) {
+	bucketName := "bucket-0"
+	rule := func(id, prefix string, days int) *BucketLifecycleRuleBuilder {
+		return NewBucketLifecycleRuleBuilder(id).Prefix(bucketName, prefix).AfterDays(days)
+	}
+
+	valid := BucketLifecycle{
+		Rule: []BucketLifecycleItem{
+			rule("1", "logs/", 30).TransitionTo(STORAGE_CLASS_STANDARD_IA).rule,
+			rule("2", "logs/", 90).TransitionTo(STORAGE_CLASS_COLD).rule,
+			rule("3", "logs/", 365).DeleteObject().rule,
+			rule("4", "logs/tmp/", 7).DeleteObject().rule,
+			rule("5", "", 7).AbortMultipartUpload().rule,
+		},
+	}
+
+	if err := valid.Validate(bucketName); err != nil {
+		t.Error(util.FormatTest("BucketLifecycle: Validate", err.Error(), "nil"))
+	}
+
+	notMidnight := rule("1", "", 7).DeleteObject().rule
+	notMidnight.Condition.Time.DateGreaterThan = "2017-06-01T08:00:00Z"
+
+	invalids := map[string]BucketLifecycle{
+		"empty": BucketLifecycle{},
+		"duplicate id": BucketLifecycle{Rule: []BucketLifecycleItem{
+			rule("1", "logs/", 30).DeleteObject().rule,
+			rule("1", "data/", 30).DeleteObject().rule,
+		}},
+		"other bucket": BucketLifecycle{Rule: []BucketLifecycleItem{
+			NewBucketLifecycleRuleBuilder("1").Prefix("bucket-1", "").AfterDays(7).DeleteObject().rule,
+		}},
+		"same action": BucketLifecycle{Rule: []BucketLifecycleItem{
+			rule("1", "logs/", 30).DeleteObject().rule,
+			rule("2", "logs/", 60).DeleteObject().rule,
+		}},
+		"transition after delete": BucketLifecycle{Rule: []BucketLifecycleItem{
+			rule("1", "", 30).DeleteObject().rule,
+			rule("2", "logs/", 60).TransitionTo(STORAGE_CLASS_COLD).rule,
+		}},
+		"date not at midnight": BucketLifecycle{Rule: []BucketLifecycleItem{notMidnight}},
+		"warmer after colder": BucketLifecycle{Rule: []BucketLifecycleItem{
+			rule("1", "logs/", 60).TransitionTo(STORAGE_CLASS_STANDARD_IA).rule,
+			rule("2", "logs/", 30).TransitionTo(STORAGE_CLASS_COLD).rule,
+		}},
+	}
+
+	// the field of bce.ValidationError returned for each invalid lifecycle
+	fields := map[string]string{
+		"empty":                   "rule",
+		"duplicate id":            "rule[1].id",
+		"other bucket":            "rule[0].resource",
+		"same action":             "rule[1].action",
+		"transition after delete": "rule[1].action",
+		"date not at midnight":    "rule[0].condition",
+		"warmer after colder":     "rule[0].action",
+	}
+
+	for name, lifecycle := range invalids {
+		err := lifecycle.Validate(bucketName)
+
+		if validationError, ok := err.(*bce.ValidationError); !ok || validationError.Field != fields[name] {
+			t.Error(util.FormatTest("BucketLifecycle: Validate "+name, fmt.Sprintf("%v", err), fields[name]))
+		}
+	}
+
+	disabled := BucketLifecycle{Rule: []BucketLifecycleItem{
+		rule("1", "", 30).DeleteObject().rule,
+		rule("2", "logs/", 60).TransitionTo(STORAGE_CLASS_COLD).Disabled().rule,
+	}}
+
+	if err := disabled.Validate(bucketName); err != nil {
+		t.Error(util.FormatTest("BucketLifecycle: Validate", err.Error(), "nil"))
+	}
+}
@@ -0,0 +1,180 @@
+package bos
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// SSE_CUSTOMER_KEY_SIZE is the size of key provided by customer, in bytes.
+const SSE_CUSTOMER_KEY_SIZE = 32
+
+// the headers of source BOS Object are prefixed with "x-bce-copy-source-" instead of "x-bce-".
+const sseHeader = "server-side-encryption"
+const sseCustomerAlgorithmHeader = "server-side-encryption-customer-algorithm"
+const sseCustomerKeyHeader = "server-side-encryption-customer-key"
+const sseCustomerKeyMD5Header = "server-side-encryption-customer-key-md5"
+
+// ServerSideEncryption defined a struct for the server side encryption of BOS Object,
+// the BOS Object is encrypted at rest either with keys managed by BOS (SSE-BOS) or with a key provided by customer.
+//
+// BOS does not store the key provided by customer, the same key must be provided to read or copy the BOS Object.
+type ServerSideEncryption struct {
+	// Algorithm is SERVER_SIDE_ENCRYPTION_AES256 or SERVER_SIDE_ENCRYPTION_SM4 for SSE-BOS,
+	// it is always SERVER_SIDE_ENCRYPTION_AES256 if CustomerKey is specified.
+	Algorithm string
+
+	// CustomerKey is the 256 bits key provided by customer.
+	CustomerKey []byte
+
+	// CustomerKeyMD5 is the base64 encoded MD5 of CustomerKey, it is computed if empty.
+	// It is the only field of customer key returned by BOS.
+	CustomerKeyMD5 string
+}
+
+// NewServerSideEncryption returns a bos.ServerSideEncryption instance which encrypts BOS Object with keys managed by BOS.
+func NewServerSideEncryption(algorithm string) *ServerSideEncryption {
+	return &ServerSideEncryption{Algorithm: algorithm}
+}
+
+// NewCustomerKeyEncryption returns a bos.ServerSideEncryption instance which encrypts BOS Object with key
+// provided by customer.
+func NewCustomerKeyEncryption(key []byte) *ServerSideEncryption {
+	return &ServerSideEncryption{Algorithm: SERVER_SIDE_ENCRYPTION_AES256, CustomerKey: key}
+}
+
+// IsCustomerKey checks whether the BOS Object is encrypted with a key provided by customer.
+func (sse *ServerSideEncryption) IsCustomerKey() bool {
+	return sse.CustomerKey != nil || sse.CustomerKeyMD5 != ""
+}
+
+func (sse *ServerSideEncryption) customerKeyMD5() string {
+	if sse.CustomerKeyMD5 != "" {
+		return sse.CustomerKeyMD5
+	}
+
+	sum := md5.Sum(sse.CustomerKey)
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func (sse *ServerSideEncryption) validate() error {
+	if sse == nil {
+		return nil
+	}
+
+	if sse.CustomerKey == nil && sse.CustomerKeyMD5 != "" {
+		// the server side encryption parsed from the response of BOS has no customer key
+		return bce.NewValidationError("customerKey",
+			"The BOS Object is encrypted with customer key, the key should be provided.")
+	}
+
+	if sse.CustomerKey == nil {
+		if sse.Algorithm != SERVER_SIDE_ENCRYPTION_AES256 && sse.Algorithm != SERVER_SIDE_ENCRYPTION_SM4 {
+			return bce.NewValidationError("algorithm", fmt.Sprintf(
+				"The server side encryption algorithm %s is not supported.", sse.Algorithm))
+		}
+
+		return nil
+	}
+
+	if sse.Algorithm != "" && sse.Algorithm != SERVER_SIDE_ENCRYPTION_AES256 {
+		return bce.NewValidationError("algorithm", fmt.Sprintf(
+			"The server side encryption algorithm %s is not supported, customer key only supports %s.",
+			sse.Algorithm, SERVER_SIDE_ENCRYPTION_AES256))
+	}
+
+	if len(sse.CustomerKey) != SSE_CUSTOMER_KEY_SIZE {
+		return bce.NewValidationError("customerKey", fmt.Sprintf("The customer key size %d should be %d bytes.",
+			len(sse.CustomerKey), SSE_CUSTOMER_KEY_SIZE))
+	}
+
+	if sse.CustomerKeyMD5 != "" {
+		sum := md5.Sum(sse.CustomerKey)
+
+		if sse.CustomerKeyMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+			return bce.NewValidationError("customerKeyMD5", fmt.Sprintf(
+				"The customer key md5 %s does not match the customer key.", sse.CustomerKeyMD5))
+		}
+	}
+
+	return nil
+}
+
+// mergeToSignOption adds the headers of server side encryption to option,
+// the headers of source BOS Object are added if copySource is true, which only works with customer key.
+func (sse *ServerSideEncryption) mergeToSignOption(option *bce.SignOption, copySource bool) {
+	if sse == nil {
+		return
+	}
+
+	prefix := "x-bce-"
+
+	if copySource {
+		prefix = "x-bce-copy-source-"
+	}
+
+	if sse.CustomerKey == nil {
+		if !copySource && !sse.IsCustomerKey() {
+			option.AddHeader(prefix+sseHeader, sse.Algorithm)
+		}
+
+		return
+	}
+
+	algorithm := sse.Algorithm
+
+	if algorithm == "" {
+		algorithm = SERVER_SIDE_ENCRYPTION_AES256
+	}
+
+	option.AddHeaders(map[string]string{
+		prefix + sseCustomerAlgorithmHeader: algorithm,
+		prefix + sseCustomerKeyHeader:       base64.StdEncoding.EncodeToString(sse.CustomerKey),
+		prefix + sseCustomerKeyMD5Header:    sse.customerKeyMD5(),
+	})
+}
+
+// parseServerSideEncryption parses the headers of server side encryption returned by BOS,
+// it returns nil if the BOS Object is not encrypted.
+func parseServerSideEncryption(h http.Header) *ServerSideEncryption {
+	algorithm := h.Get("x-bce-" + sseHeader)
+	customerAlgorithm := h.Get("x-bce-" + sseCustomerAlgorithmHeader)
+
+	if algorithm == "" && customerAlgorithm == "" {
+		return nil
+	}
+
+	if customerAlgorithm != "" {
+		return &ServerSideEncryption{
+			Algorithm:      customerAlgorithm,
+			CustomerKeyMD5: h.Get("x-bce-" + sseCustomerKeyMD5Header),
+		}
+	}
+
+	return &ServerSideEncryption{Algorithm: algorithm}
+}
+
+// serverSideEncryption returns the server side encryption of metadata, it returns nil if metadata is nil.
+func (metadata *ObjectMetadata) serverSideEncryption() *ServerSideEncryption {
+	if metadata == nil {
+		return nil
+	}
+
+	return metadata.ServerSideEncryption
+}
+
+func (copyObjectRequest *CopyObjectRequest) validateServerSideEncryption() error {
+	if err := copyObjectRequest.ObjectMetadata.serverSideEncryption().validate(); err != nil {
+		return err
+	}
+
+	if err := copyObjectRequest.ServerSideEncryption.validate(); err != nil {
+		return err
+	}
+
+	return copyObjectRequest.SourceServerSideEncryption.validate()
+}
@@ -0,0 +1,126 @@
+package bos
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestValidateServerSideEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), SSE_CUSTOMER_KEY_SIZE)
+
+	valid := []*ServerSideEncryption{
+		nil,
+		NewServerSideEncryption(SERVER_SIDE_ENCRYPTION_AES256),
+		NewServerSideEncryption(SERVER_SIDE_ENCRYPTION_SM4),
+		NewCustomerKeyEncryption(key),
+		{CustomerKey: key, CustomerKeyMD5: NewCustomerKeyEncryption(key).customerKeyMD5()},
+	}
+
+	for _, sse := range valid {
+		if err := sse.validate(); err != nil {
+			t.Error(util.FormatTest("ServerSideEncryption: validate", err.Error(), "nil"))
+		}
+	}
+
+	invalid := []*ServerSideEncryption{
+		NewServerSideEncryption(""),
+		NewServerSideEncryption("AES128"),
+		NewCustomerKeyEncryption(key[1:]),
+		{Algorithm: SERVER_SIDE_ENCRYPTION_SM4, CustomerKey: key},
+		{CustomerKey: key, CustomerKeyMD5: "abc"},
+		{Algorithm: SERVER_SIDE_ENCRYPTION_AES256, CustomerKeyMD5: NewCustomerKeyEncryption(key).customerKeyMD5()},
+	}
+
+	for _, sse := range invalid {
+		if err := sse.validate(); err == nil {
+			t.Error(util.FormatTest("ServerSideEncryption: validate", "nil", "error"))
+		}
+	}
+}
+
+func TestMergeToSignOptionForServerSideEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), SSE_CUSTOMER_KEY_SIZE)
+	keyMD5 := "mT2HRsMGJ5IX5C+0rreZ8Q=="
+
+	copyObjectRequest := CopyObjectRequest{
+		ServerSideEncryption:       NewServerSideEncryption(SERVER_SIDE_ENCRYPTION_AES256),
+		SourceServerSideEncryption: NewCustomerKeyEncryption(key),
+	}
+
+	option := &bce.SignOption{}
+	copyObjectRequest.mergeToSignOption(option)
+
+	expected := map[string]string{
+		"x-bce-server-side-encryption":                                SERVER_SIDE_ENCRYPTION_AES256,
+		"x-bce-copy-source-server-side-encryption-customer-algorithm": SERVER_SIDE_ENCRYPTION_AES256,
+		"x-bce-copy-source-server-side-encryption-customer-key":       "a2tra2tra2tra2tra2tra2tra2tra2tra2tra2tra2s=",
+		"x-bce-copy-source-server-side-encryption-customer-key-md5":   keyMD5,
+	}
+
+	for key, value := range expected {
+		if option.Headers[key] != value {
+			t.Error(util.FormatTest("CopyObjectRequest: mergeToSignOption "+key, option.Headers[key], value))
+		}
+	}
+
+	option = &bce.SignOption{}
+	getObjectRequest := GetObjectRequest{ServerSideEncryption: NewCustomerKeyEncryption(key)}
+	getObjectRequest.MergeToSignOption(option)
+
+	if value := option.Headers["x-bce-server-side-encryption-customer-key-md5"]; value != keyMD5 {
+		t.Error(util.FormatTest("GetObjectRequest: MergeToSignOption", value, keyMD5))
+	}
+
+	if _, ok := option.Headers["x-bce-server-side-encryption"]; ok {
+		t.Error(util.FormatTest("GetObjectRequest: MergeToSignOption", "x-bce-server-side-encryption", "not exists"))
+	}
+}
+
+func TestParseServerSideEncryption(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-bce-server-side-encryption", SERVER_SIDE_ENCRYPTION_AES256)
+
+	metadata := NewObjectMetadataFromHeader(h)
+
+	if sse := metadata.ServerSideEncryption; sse == nil || sse.Algorithm != SERVER_SIDE_ENCRYPTION_AES256 || sse.IsCustomerKey() {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", "nil", SERVER_SIDE_ENCRYPTION_AES256))
+	}
+
+	if sse := metadata.copyable().ServerSideEncryption; sse == nil || sse.Algorithm != SERVER_SIDE_ENCRYPTION_AES256 {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", "nil", SERVER_SIDE_ENCRYPTION_AES256))
+	}
+
+	h = http.Header{}
+	h.Set("x-bce-server-side-encryption-customer-algorithm", SERVER_SIDE_ENCRYPTION_AES256)
+	h.Set("x-bce-server-side-encryption-customer-key-md5", "abc")
+
+	metadata = NewObjectMetadataFromHeader(h)
+
+	if sse := metadata.ServerSideEncryption; sse == nil || !sse.IsCustomerKey() || sse.CustomerKeyMD5 != "abc" {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", "nil", "abc"))
+	}
+
+	if sse := metadata.copyable().ServerSideEncryption; sse != nil {
+		t.Error(util.FormatTest("ObjectMetadata: copyable", sse.Algorithm, "nil"))
+	}
+
+	// the parsed server side encryption has no customer key, it should not be sent back as SSE-BOS
+	if err := metadata.ServerSideEncryption.validate(); err == nil {
+		t.Error(util.FormatTest("ServerSideEncryption: validate", "nil", "error"))
+	}
+
+	option := &bce.SignOption{}
+	metadata.ServerSideEncryption.mergeToSignOption(option, false)
+
+	if value, ok := option.Headers["x-bce-server-side-encryption"]; ok {
+		t.Error(util.FormatTest("ServerSideEncryption: mergeToSignOption", value, "not exists"))
+	}
+
+	if sse := NewObjectMetadataFromHeader(http.Header{}).ServerSideEncryption; sse != nil {
+		t.Error(util.FormatTest("NewObjectMetadataFromHeader", sse.Algorithm, "nil"))
+	}
+}
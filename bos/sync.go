@@ -0,0 +1,679 @@
+package bos
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// SYNC_COMPARE is the way to decide whether a file differs from its counterpart.
+//
+// SYNC_COMPARE_SIZE compares the size only, SYNC_COMPARE_MTIME compares the size and the modification
+// time stored in user metadata, SYNC_COMPARE_ETAG compares the MD5 of local file with the ETag of BOS Object,
+// or with the MD5 stored in user metadata if the object is uploaded by multipart, whose ETag is not the MD5.
+const SYNC_COMPARE_SIZE = "size"
+const SYNC_COMPARE_MTIME = "mtime"
+const SYNC_COMPARE_ETAG = "etag"
+
+// SYNC_ACTION is the type of a bos.SyncAction.
+const SYNC_ACTION_UPLOAD = "upload"
+const SYNC_ACTION_DOWNLOAD = "download"
+const SYNC_ACTION_DELETE = "delete"
+
+// SyncMtimeMetadata is the user metadata which stores the modification time (unix seconds) of local file.
+const SyncMtimeMetadata = "mtime"
+
+// SyncMd5Metadata is the user metadata which stores the hex MD5 of local file uploaded by multipart
+// in SYNC_COMPARE_ETAG mode.
+const SyncMd5Metadata = "md5"
+
+// DefaultSyncParallel is the default count of concurrent transfers.
+const DefaultSyncParallel = 4
+
+// DefaultSyncMultipartThreshold is the default file size to switch to multipart upload.
+const DefaultSyncMultipartThreshold int64 = 1024 * 1024 * 32
+
+// DefaultSyncPartSize is the default part size of multipart upload.
+const DefaultSyncPartSize int64 = 1024 * 1024 * 8
+
+// SyncRequest contains all options for bos.SyncToBucket and bos.SyncFromBucket methods.
+type SyncRequest struct {
+	LocalDir, BucketName, Prefix string
+
+	// CompareMode is one of SYNC_COMPARE_SIZE, SYNC_COMPARE_MTIME and SYNC_COMPARE_ETAG,
+	// SYNC_COMPARE_MTIME will be used if not specified.
+	CompareMode string
+
+	// Include and Exclude are glob patterns matched against the relative slash separated path,
+	// patterns without '/' are also matched against the base name.
+	Include []string
+	Exclude []string
+
+	// Delete removes the files which not exist in source.
+	Delete bool
+
+	// DryRun only reports the actions without transferring anything.
+	DryRun bool
+
+	Parallel           int
+	MultipartThreshold int64
+	PartSize           int64
+
+	// Output receives one line for each action if specified.
+	Output io.Writer
+}
+
+// SyncAction defined a struct for one action of bos.SyncToBucket and bos.SyncFromBucket methods.
+type SyncAction struct {
+	Type      string
+	LocalPath string
+	Key       string
+	Size      int64
+	Error     error
+}
+
+// String returns the formatted description of bos.SyncAction.
+func (action SyncAction) String() string {
+	switch action.Type {
+	case SYNC_ACTION_UPLOAD:
+		return fmt.Sprintf("upload: %s -> %s", action.LocalPath, action.Key)
+	case SYNC_ACTION_DOWNLOAD:
+		return fmt.Sprintf("download: %s -> %s", action.Key, action.LocalPath)
+	}
+
+	if action.Key != "" {
+		return fmt.Sprintf("delete: %s", action.Key)
+	}
+
+	return fmt.Sprintf("delete: %s", action.LocalPath)
+}
+
+// SyncResult defined a struct for bos.SyncToBucket and bos.SyncFromBucket methods' response.
+type SyncResult struct {
+	Actions []SyncAction
+	Skipped int
+}
+
+// Errors returns all failed actions.
+func (result *SyncResult) Errors() []SyncAction {
+	failed := make([]SyncAction, 0)
+
+	for _, action := range result.Actions {
+		if action.Error != nil {
+			failed = append(failed, action)
+		}
+	}
+
+	return failed
+}
+
+// Count returns the count of successful actions of the specified type.
+func (result *SyncResult) Count(actionType string) int {
+	count := 0
+
+	for _, action := range result.Actions {
+		if action.Type == actionType && action.Error == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+type syncFile struct {
+	path         string
+	size         int64
+	modTime      time.Time
+	etag         string
+	lastModified string
+}
+
+func (syncRequest *SyncRequest) init() error {
+	if syncRequest.LocalDir == "" {
+		return bce.NewValidationError("localDir", "The local dir should not be empty.")
+	}
+
+	if err := checkBucketName(syncRequest.BucketName); err != nil {
+		return err
+	}
+
+	if syncRequest.Prefix != "" && !strings.HasSuffix(syncRequest.Prefix, "/") {
+		syncRequest.Prefix += "/"
+	}
+
+	switch syncRequest.CompareMode {
+	case "":
+		syncRequest.CompareMode = SYNC_COMPARE_MTIME
+	case SYNC_COMPARE_SIZE, SYNC_COMPARE_MTIME, SYNC_COMPARE_ETAG:
+	default:
+		return bce.NewValidationError("compareMode", fmt.Sprintf("The compare mode %s is not supported.",
+			syncRequest.CompareMode))
+	}
+
+	for _, pattern := range append(syncRequest.Include, syncRequest.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return bce.NewValidationError("pattern", fmt.Sprintf("The pattern %s is malformed: %v", pattern, err))
+		}
+	}
+
+	if syncRequest.Parallel <= 0 {
+		syncRequest.Parallel = DefaultSyncParallel
+	}
+
+	if syncRequest.MultipartThreshold <= 0 {
+		syncRequest.MultipartThreshold = DefaultSyncMultipartThreshold
+	}
+
+	if syncRequest.PartSize <= 0 {
+		syncRequest.PartSize = DefaultSyncPartSize
+	}
+
+	return nil
+}
+
+// isIncluded checks the relative path against the include and exclude patterns.
+func (syncRequest *SyncRequest) isIncluded(relativePath string) bool {
+	if len(syncRequest.Include) > 0 && !matchSyncPatterns(syncRequest.Include, relativePath) {
+		return false
+	}
+
+	return !matchSyncPatterns(syncRequest.Exclude, relativePath)
+}
+
+func matchSyncPatterns(patterns []string, relativePath string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, relativePath); matched {
+			return true
+		}
+
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := path.Match(pattern, path.Base(relativePath)); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (syncRequest *SyncRequest) listLocalFiles() (map[string]*syncFile, error) {
+	files := make(map[string]*syncFile)
+
+	if !util.CheckFileExists(syncRequest.LocalDir) {
+		return files, nil
+	}
+
+	err := filepath.Walk(syncRequest.LocalDir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(syncRequest.LocalDir, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		relativePath = filepath.ToSlash(relativePath)
+
+		if syncRequest.isIncluded(relativePath) {
+			files[relativePath] = &syncFile{path: filePath, size: info.Size(), modTime: info.ModTime()}
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+func (c *Client) listSyncObjects(syncRequest *SyncRequest) (map[string]*syncFile, error) {
+	objects := make(map[string]*syncFile)
+	listObjectsRequest := ListObjectsRequest{
+		BucketName: syncRequest.BucketName,
+		Prefix:     syncRequest.Prefix,
+	}
+
+	for {
+		listObjectsResponse, err := c.ListObjectsFromRequest(listObjectsRequest, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, objectSummary := range listObjectsResponse.Contents {
+			if strings.HasSuffix(objectSummary.Key, "/") {
+				continue
+			}
+
+			relativePath := strings.TrimPrefix(objectSummary.Key, syncRequest.Prefix)
+
+			if syncRequest.isIncluded(relativePath) {
+				objects[relativePath] = &syncFile{
+					path:         objectSummary.Key,
+					size:         objectSummary.Size,
+					etag:         objectSummary.ETag,
+					lastModified: objectSummary.LastModified,
+				}
+			}
+		}
+
+		if !listObjectsResponse.IsTruncated {
+			break
+		}
+
+		listObjectsRequest.Marker = listObjectsResponse.NextMarker
+
+		if listObjectsRequest.Marker == "" && len(listObjectsResponse.Contents) > 0 {
+			listObjectsRequest.Marker = listObjectsResponse.Contents[len(listObjectsResponse.Contents)-1].Key
+		}
+	}
+
+	return objects, nil
+}
+
+// isSyncNeeded reports whether the source file should be transferred to the destination.
+//
+// In SYNC_COMPARE_MTIME mode, objects without stored modification time are compared by their
+// LastModified, and only transferred when the source is newer.
+func (c *Client) isSyncNeeded(syncRequest *SyncRequest, localFile, object *syncFile, upload bool) (bool, error) {
+	if localFile == nil || object == nil {
+		return true, nil
+	}
+
+	if localFile.size != object.size {
+		return true, nil
+	}
+
+	switch syncRequest.CompareMode {
+	case SYNC_COMPARE_MTIME:
+		objectMetadata, err := c.GetObjectMetadata(syncRequest.BucketName, object.path, nil)
+
+		if err != nil {
+			return false, err
+		}
+
+		if mtime := getSyncMtime(objectMetadata); mtime >= 0 {
+			return mtime != localFile.modTime.Unix(), nil
+		}
+
+		lastModified, err := time.Parse(time.RFC3339, object.lastModified)
+
+		if err != nil {
+			return true, nil
+		}
+
+		if upload {
+			return localFile.modTime.After(lastModified), nil
+		}
+
+		return lastModified.After(localFile.modTime), nil
+	case SYNC_COMPARE_ETAG:
+		md5Value, err := fileMD5(localFile.path)
+
+		if err != nil {
+			return false, err
+		}
+
+		// the object may be uploaded by multipart, only such an object has the MD5 in user metadata
+		if md5Value == object.etag || object.size < syncRequest.MultipartThreshold {
+			return md5Value != object.etag, nil
+		}
+
+		objectMetadata, err := c.GetObjectMetadata(syncRequest.BucketName, object.path, nil)
+
+		if err != nil {
+			return false, err
+		}
+
+		return md5Value != getSyncMetadata(objectMetadata, SyncMd5Metadata), nil
+	}
+
+	return false, nil
+}
+
+// getSyncMtime gets the modification time stored by sync, returns -1 if not exists.
+func getSyncMtime(objectMetadata *ObjectMetadata) int64 {
+	value := getSyncMetadata(objectMetadata, SyncMtimeMetadata)
+
+	if value == "" {
+		return -1
+	}
+
+	mtime, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return -1
+	}
+
+	return mtime
+}
+
+// getSyncMetadata gets the user metadata stored by sync, returns "" if not exists.
+func getSyncMetadata(objectMetadata *ObjectMetadata, name string) string {
+	return util.GetMapValue(objectMetadata.UserMetadata, ToUserDefinedMetadata(name), true)
+}
+
+func fileMD5(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	hash := md5.New()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SyncToBucket mirrors a local directory to the prefix of a BOS Bucket.
+func (c *Client) SyncToBucket(syncRequest SyncRequest) (*SyncResult, error) {
+	if err := syncRequest.init(); err != nil {
+		return nil, err
+	}
+
+	localFiles, err := syncRequest.listLocalFiles()
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := c.listSyncObjects(&syncRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	actions := make([]SyncAction, 0)
+	relativePaths := sortedSyncPaths(localFiles)
+	needed, errs := c.compareSyncFiles(&syncRequest, relativePaths, localFiles, objects, true)
+
+	for i, relativePath := range relativePaths {
+		localFile := localFiles[relativePath]
+
+		if errs[i] != nil || needed[i] {
+			actions = append(actions, SyncAction{
+				Type:      SYNC_ACTION_UPLOAD,
+				LocalPath: localFile.path,
+				Key:       syncRequest.Prefix + relativePath,
+				Size:      localFile.size,
+				Error:     errs[i],
+			})
+		} else {
+			result.Skipped++
+		}
+	}
+
+	if syncRequest.Delete {
+		for _, relativePath := range sortedSyncPaths(objects) {
+			if _, ok := localFiles[relativePath]; !ok {
+				actions = append(actions, SyncAction{
+					Type: SYNC_ACTION_DELETE,
+					Key:  objects[relativePath].path,
+					Size: objects[relativePath].size,
+				})
+			}
+		}
+	}
+
+	result.Actions = c.runSyncActions(&syncRequest, actions, func(action *SyncAction) error {
+		if action.Type == SYNC_ACTION_DELETE {
+			return c.DeleteObject(syncRequest.BucketName, action.Key, nil)
+		}
+
+		return c.syncUpload(&syncRequest, action, localFiles)
+	})
+
+	return result, nil
+}
+
+func (c *Client) syncUpload(syncRequest *SyncRequest, action *SyncAction, localFiles map[string]*syncFile) error {
+	localFile := localFiles[strings.TrimPrefix(action.Key, syncRequest.Prefix)]
+	metadata := &ObjectMetadata{}
+	metadata.AddUserMetadata(SyncMtimeMetadata, strconv.FormatInt(localFile.modTime.Unix(), 10))
+
+	if localFile.size >= syncRequest.MultipartThreshold {
+		// the ETag of a multipart object is not the MD5 of its content
+		if syncRequest.CompareMode == SYNC_COMPARE_ETAG {
+			md5Value, err := fileMD5(localFile.path)
+
+			if err != nil {
+				return err
+			}
+
+			metadata.AddUserMetadata(SyncMd5Metadata, md5Value)
+		}
+
+		_, err := c.multipartUploadFromFile(syncRequest.BucketName, action.Key, localFile.path,
+			syncRequest.PartSize, metadata)
+
+		return err
+	}
+
+	file, err := os.Open(localFile.path)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = c.PutObject(syncRequest.BucketName, action.Key, file, metadata, nil)
+
+	return err
+}
+
+// SyncFromBucket mirrors the prefix of a BOS Bucket to a local directory.
+func (c *Client) SyncFromBucket(syncRequest SyncRequest) (*SyncResult, error) {
+	if err := syncRequest.init(); err != nil {
+		return nil, err
+	}
+
+	objects, err := c.listSyncObjects(&syncRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	localFiles, err := syncRequest.listLocalFiles()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	actions := make([]SyncAction, 0)
+	relativePaths := sortedSyncPaths(objects)
+	needed, errs := c.compareSyncFiles(&syncRequest, relativePaths, localFiles, objects, false)
+
+	for i, relativePath := range relativePaths {
+		object := objects[relativePath]
+
+		// the object key is from remote data, the keys which leave the local dir are reported as failed
+		localPath, err := util.JoinLocalPath(syncRequest.LocalDir, relativePath)
+
+		if err != nil {
+			errs[i] = err
+		}
+
+		if errs[i] != nil || needed[i] {
+			actions = append(actions, SyncAction{
+				Type:      SYNC_ACTION_DOWNLOAD,
+				LocalPath: localPath,
+				Key:       object.path,
+				Size:      object.size,
+				Error:     errs[i],
+			})
+		} else {
+			result.Skipped++
+		}
+	}
+
+	if syncRequest.Delete {
+		for _, relativePath := range sortedSyncPaths(localFiles) {
+			if _, ok := objects[relativePath]; !ok {
+				actions = append(actions, SyncAction{
+					Type:      SYNC_ACTION_DELETE,
+					LocalPath: localFiles[relativePath].path,
+					Size:      localFiles[relativePath].size,
+				})
+			}
+		}
+	}
+
+	result.Actions = c.runSyncActions(&syncRequest, actions, func(action *SyncAction) error {
+		if action.Type == SYNC_ACTION_DELETE {
+			return os.Remove(action.LocalPath)
+		}
+
+		return c.syncDownload(syncRequest.BucketName, action)
+	})
+
+	return result, nil
+}
+
+func (c *Client) syncDownload(bucketName string, action *SyncAction) error {
+	if err := os.MkdirAll(filepath.Dir(action.LocalPath), 0755); err != nil {
+		return err
+	}
+
+	tempPath := action.LocalPath + ".bossync"
+	file, err := os.Create(tempPath)
+
+	if err != nil {
+		return err
+	}
+
+	getObjectRequest := &GetObjectRequest{BucketName: bucketName, ObjectKey: action.Key}
+	objectMetadata, err := c.GetObjectToFile(getObjectRequest, file, nil)
+
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := os.Rename(tempPath, action.LocalPath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if mtime := getSyncMtime(objectMetadata); mtime >= 0 {
+		modTime := time.Unix(mtime, 0)
+		return os.Chtimes(action.LocalPath, modTime, modTime)
+	}
+
+	return nil
+}
+
+// compareSyncFiles checks concurrently whether each file of relativePaths should be transferred,
+// because the comparing may send a HEAD request or read the whole local file.
+func (c *Client) compareSyncFiles(syncRequest *SyncRequest, relativePaths []string, localFiles,
+	objects map[string]*syncFile, upload bool) ([]bool, []error) {
+
+	needed := make([]bool, len(relativePaths))
+	errs := make([]error, len(relativePaths))
+
+	runSyncParallel(syncRequest.Parallel, len(relativePaths), func(index int) {
+		relativePath := relativePaths[index]
+		needed[index], errs[index] = c.isSyncNeeded(syncRequest, localFiles[relativePath], objects[relativePath],
+			upload)
+	})
+
+	return needed, errs
+}
+
+// runSyncActions executes the actions concurrently, failed actions in planning phase are kept as they are.
+func (c *Client) runSyncActions(syncRequest *SyncRequest, actions []SyncAction,
+	f func(action *SyncAction) error) []SyncAction {
+
+	var mutex sync.Mutex
+
+	runSyncParallel(syncRequest.Parallel, len(actions), func(index int) {
+		action := &actions[index]
+
+		if action.Error == nil && !syncRequest.DryRun {
+			action.Error = f(action)
+		}
+
+		if syncRequest.Output != nil {
+			mutex.Lock()
+			printSyncAction(syncRequest, action)
+			mutex.Unlock()
+		}
+	})
+
+	return actions
+}
+
+// runSyncParallel calls f with each index in [0, count) by parallel goroutines, and waits for all of them.
+func runSyncParallel(parallel, count int, f func(index int)) {
+	var waitGroup sync.WaitGroup
+	queue := make(chan int)
+
+	for i := 0; i < parallel; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for index := range queue {
+				f(index)
+			}
+		}()
+	}
+
+	for index := 0; index < count; index++ {
+		queue <- index
+	}
+
+	close(queue)
+	waitGroup.Wait()
+}
+
+func printSyncAction(syncRequest *SyncRequest, action *SyncAction) {
+	prefix := ""
+
+	if syncRequest.DryRun {
+		prefix = "(dryrun) "
+	}
+
+	if action.Error != nil {
+		fmt.Fprintf(syncRequest.Output, "%sfailed %s: %v\n", prefix, action, action.Error)
+	} else {
+		fmt.Fprintf(syncRequest.Output, "%s%s\n", prefix, action)
+	}
+}
+
+func sortedSyncPaths(files map[string]*syncFile) []string {
+	paths := make([]string, 0, len(files))
+
+	for relativePath := range files {
+		paths = append(paths, relativePath)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
@@ -0,0 +1,388 @@
+package bos
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+func TestSyncRequestInit(t *testing.T) {
+	syncRequest := SyncRequest{LocalDir: "local", BucketName: "bucket", Prefix: "backup"}
+
+	if err := syncRequest.init(); err != nil {
+		t.Error(util.FormatTest("SyncRequest: init", err.Error(), "nil"))
+	}
+
+	if syncRequest.Prefix != "backup/" {
+		t.Error(util.FormatTest("SyncRequest: init", syncRequest.Prefix, "backup/"))
+	}
+
+	if syncRequest.CompareMode != SYNC_COMPARE_MTIME {
+		t.Error(util.FormatTest("SyncRequest: init", syncRequest.CompareMode, SYNC_COMPARE_MTIME))
+	}
+
+	if syncRequest.Parallel != DefaultSyncParallel {
+		t.Error(util.FormatTest("SyncRequest: init", strconv.Itoa(syncRequest.Parallel), strconv.Itoa(DefaultSyncParallel)))
+	}
+
+	syncRequest = SyncRequest{LocalDir: "local", BucketName: "bucket", CompareMode: "crc"}
+
+	if err := syncRequest.init(); err == nil {
+		t.Error(util.FormatTest("SyncRequest: init", "nil", "error"))
+	}
+
+	syncRequest = SyncRequest{LocalDir: "local", BucketName: "bucket", Include: []string{"[a-"}}
+
+	if err := syncRequest.init(); err == nil {
+		t.Error(util.FormatTest("SyncRequest: init", "nil", "error"))
+	}
+}
+
+func TestSyncRequestIsIncluded(t *testing.T) {
+	syncRequest := SyncRequest{
+		Include: []string{"*.txt", "docs/*"},
+		Exclude: []string{"tmp-*"},
+	}
+
+	cases := map[string]bool{
+		"a.txt":          true,
+		"dir/b.txt":      true,
+		"docs/readme.md": true,
+		"dir/tmp-c.txt":  false,
+		"image.png":      false,
+	}
+
+	for relativePath, expected := range cases {
+		if result := syncRequest.isIncluded(relativePath); result != expected {
+			t.Error(util.FormatTest("SyncRequest: isIncluded "+relativePath,
+				strconv.FormatBool(result), strconv.FormatBool(expected)))
+		}
+	}
+}
+
+func TestSyncActionString(t *testing.T) {
+	action := SyncAction{Type: SYNC_ACTION_UPLOAD, LocalPath: "a.txt", Key: "backup/a.txt"}
+	expected := "upload: a.txt -> backup/a.txt"
+
+	if action.String() != expected {
+		t.Error(util.FormatTest("SyncAction: String", action.String(), expected))
+	}
+
+	action = SyncAction{Type: SYNC_ACTION_DELETE, LocalPath: "a.txt"}
+	expected = "delete: a.txt"
+
+	if action.String() != expected {
+		t.Error(util.FormatTest("SyncAction: String", action.String(), expected))
+	}
+}
+
+func TestGetSyncMtime(t *testing.T) {
+	metadata := &ObjectMetadata{}
+
+	if mtime := getSyncMtime(metadata); mtime != -1 {
+		t.Error(util.FormatTest("getSyncMtime", strconv.FormatInt(mtime, 10), "-1"))
+	}
+
+	metadata.AddUserMetadata("X-Bce-Meta-Mtime", "1500000000")
+
+	if mtime := getSyncMtime(metadata); mtime != 1500000000 {
+		t.Error(util.FormatTest("getSyncMtime", strconv.FormatInt(mtime, 10), "1500000000"))
+	}
+}
+
+func TestListLocalFiles(t *testing.T) {
+	home, err := util.HomeDir()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(home, "tmp", "baidubce-sdk-go-test-for-sync-"+strconv.Itoa(os.Getpid()))
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.txt", "sub/b.txt", "sub/c.log"} {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+
+		if f, err := os.Create(filePath); err != nil {
+			t.Fatal(err)
+		} else {
+			f.Close()
+		}
+	}
+
+	syncRequest := SyncRequest{LocalDir: dir, Exclude: []string{"*.log"}}
+	files, err := syncRequest.listLocalFiles()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		t.Error(util.FormatTest("listLocalFiles", strconv.Itoa(len(files)), "2"))
+	}
+
+	if _, ok := files["sub/b.txt"]; !ok {
+		t.Error(util.FormatTest("listLocalFiles", "sub/b.txt not exists", "sub/b.txt"))
+	}
+}
+
+// writeSyncFiles writes the files to the directory, the keys are slash separated relative paths.
+func writeSyncFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkSyncResult(t *testing.T, name string, result *SyncResult, err error, actionType string,
+	count, skipped int) {
+
+	if err != nil {
+		t.Fatal(util.FormatTest(name, err.Error(), "nil"))
+	}
+
+	if len(result.Errors()) > 0 {
+		t.Error(util.FormatTest(name, fmt.Sprintf("%v", result.Errors()), "no errors"))
+	}
+
+	if result.Count(actionType) != count || result.Skipped != skipped {
+		t.Error(util.FormatTest(name, fmt.Sprintf("%s %d, skipped %d", actionType, result.Count(actionType),
+			result.Skipped), fmt.Sprintf("%s %d, skipped %d", actionType, count, skipped)))
+	}
+}
+
+func TestSyncToBucket(t *testing.T) {
+	bucketName := "sync-to-bucket"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "bos-sync")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// big.bin is larger than MultipartThreshold, it is uploaded by multipart
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "aaa", "sub/b.txt": "bbbb", "big.bin": "0123456789abcdefghij"})
+
+	var output bytes.Buffer
+	syncRequest := SyncRequest{
+		LocalDir:           dir,
+		BucketName:         bucketName,
+		Prefix:             "backup",
+		CompareMode:        SYNC_COMPARE_ETAG,
+		DryRun:             true,
+		MultipartThreshold: 16,
+		PartSize:           8,
+		Output:             &output,
+	}
+
+	result, err := client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket dry run", result, err, SYNC_ACTION_UPLOAD, 3, 0)
+
+	if listObjectsResponse, _ := client.ListObjects(bucketName, nil); len(listObjectsResponse.Contents) != 0 ||
+		!strings.Contains(output.String(), "(dryrun) upload: ") {
+
+		t.Error(util.FormatTest("SyncToBucket dry run", output.String(), "nothing uploaded"))
+	}
+
+	syncRequest.DryRun = false
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket", result, err, SYNC_ACTION_UPLOAD, 3, 0)
+
+	object, err := client.GetObject(bucketName, "backup/big.bin", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := ioutil.ReadAll(object.ObjectContent)
+	object.ObjectContent.Close()
+
+	if string(content) != "0123456789abcdefghij" {
+		t.Error(util.FormatTest("SyncToBucket multipart", string(content), "0123456789abcdefghij"))
+	}
+
+	// the multipart object is compared with the MD5 stored in user metadata instead of its ETag
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket etag unchanged", result, err, SYNC_ACTION_UPLOAD, 0, 3)
+
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "aab"})
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket etag changed", result, err, SYNC_ACTION_UPLOAD, 1, 2)
+
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "aac"})
+	syncRequest.CompareMode = SYNC_COMPARE_SIZE
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket size", result, err, SYNC_ACTION_UPLOAD, 0, 3)
+
+	modTime := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, "a.txt"), modTime, modTime)
+	syncRequest.CompareMode = SYNC_COMPARE_MTIME
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket mtime changed", result, err, SYNC_ACTION_UPLOAD, 1, 2)
+
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket mtime unchanged", result, err, SYNC_ACTION_UPLOAD, 0, 3)
+
+	os.Remove(filepath.Join(dir, "sub", "b.txt"))
+	syncRequest.Delete = true
+	result, err = client.SyncToBucket(syncRequest)
+	checkSyncResult(t, "SyncToBucket delete", result, err, SYNC_ACTION_DELETE, 1, 2)
+
+	if _, err := client.GetObjectMetadata(bucketName, "backup/sub/b.txt", nil); err == nil {
+		t.Error(util.FormatTest("SyncToBucket delete", "backup/sub/b.txt exists", "deleted"))
+	}
+}
+
+func TestSyncFromBucket(t *testing.T) {
+	bucketName := "sync-from-bucket"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	srcDir, err := ioutil.TempDir("", "bos-sync-src")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(srcDir)
+
+	dir, err := ioutil.TempDir("", "bos-sync-dest")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"a.txt": "aaa", "sub/b.txt": "bbbb", "big.bin": "0123456789abcdefghij"}
+	writeSyncFiles(t, srcDir, files)
+	modTime := time.Unix(1500000000, 0)
+	os.Chtimes(filepath.Join(srcDir, "a.txt"), modTime, modTime)
+
+	syncRequest := SyncRequest{
+		LocalDir:           srcDir,
+		BucketName:         bucketName,
+		CompareMode:        SYNC_COMPARE_ETAG,
+		MultipartThreshold: 16,
+		PartSize:           8,
+	}
+
+	if _, err := client.SyncToBucket(syncRequest); err != nil {
+		t.Fatal(err)
+	}
+
+	syncRequest.LocalDir = dir
+	syncRequest.DryRun = true
+	result, err := client.SyncFromBucket(syncRequest)
+	checkSyncResult(t, "SyncFromBucket dry run", result, err, SYNC_ACTION_DOWNLOAD, 3, 0)
+
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err == nil {
+		t.Error(util.FormatTest("SyncFromBucket dry run", "a.txt exists", "nothing downloaded"))
+	}
+
+	syncRequest.DryRun = false
+	result, err = client.SyncFromBucket(syncRequest)
+	checkSyncResult(t, "SyncFromBucket", result, err, SYNC_ACTION_DOWNLOAD, 3, 0)
+
+	for name, content := range files {
+		if byteArray, _ := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name))); string(byteArray) != content {
+			t.Error(util.FormatTest("SyncFromBucket "+name, string(byteArray), content))
+		}
+	}
+
+	if fileInfo, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil || !fileInfo.ModTime().Equal(modTime) {
+		t.Error(util.FormatTest("SyncFromBucket mtime", fmt.Sprintf("%v", fileInfo), modTime.String()))
+	}
+
+	for _, compareMode := range []string{SYNC_COMPARE_ETAG, SYNC_COMPARE_SIZE, SYNC_COMPARE_MTIME} {
+		syncRequest.CompareMode = compareMode
+		result, err = client.SyncFromBucket(syncRequest)
+		checkSyncResult(t, "SyncFromBucket unchanged "+compareMode, result, err, SYNC_ACTION_DOWNLOAD, 0, 3)
+	}
+
+	writeSyncFiles(t, dir, map[string]string{"a.txt": "xyz", "extra.txt": "extra"})
+	os.Chtimes(filepath.Join(dir, "a.txt"), modTime, modTime)
+
+	syncRequest.CompareMode = SYNC_COMPARE_ETAG
+	syncRequest.Delete = true
+	result, err = client.SyncFromBucket(syncRequest)
+	checkSyncResult(t, "SyncFromBucket changed", result, err, SYNC_ACTION_DOWNLOAD, 1, 2)
+
+	if result.Count(SYNC_ACTION_DELETE) != 1 {
+		t.Error(util.FormatTest("SyncFromBucket delete", strconv.Itoa(result.Count(SYNC_ACTION_DELETE)), "1"))
+	}
+
+	if byteArray, _ := ioutil.ReadFile(filepath.Join(dir, "a.txt")); string(byteArray) != "aaa" {
+		t.Error(util.FormatTest("SyncFromBucket changed", string(byteArray), "aaa"))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "extra.txt")); err == nil {
+		t.Error(util.FormatTest("SyncFromBucket delete", "extra.txt exists", "deleted"))
+	}
+}
+
+func TestSyncFromBucketOutsideLocalDir(t *testing.T) {
+	method := "SyncFromBucket"
+	bucketName := "sync-from-bucket-outside"
+	server, client := newFakeClient(t, bucketName)
+	defer server.Close()
+
+	root, err := ioutil.TempDir("", "bos-sync-root")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(root)
+
+	dir := filepath.Join(root, "dest")
+	keys := []string{"backup/a.txt", "backup/../escaped.txt", "backup/sub/../../escaped.txt"}
+
+	for _, key := range keys {
+		if _, err := client.PutObject(bucketName, key, "Hello World", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := client.SyncFromBucket(SyncRequest{LocalDir: dir, BucketName: bucketName, Prefix: "backup"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count, failed := result.Count(SYNC_ACTION_DOWNLOAD), len(result.Errors()); count != 1 || failed != 2 {
+		t.Error(util.FormatTest(method, fmt.Sprintf("downloaded %d, failed %d", count, failed),
+			"downloaded 1, failed 2"))
+	}
+
+	for _, action := range result.Errors() {
+		if action.Key == keys[0] {
+			t.Error(util.FormatTest(method, action.Error.Error(), "nil"))
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Error(util.FormatTest(method, err.Error(), "a.txt downloaded"))
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "escaped.txt")); err == nil {
+		t.Error(util.FormatTest(method, "escaped.txt exists", "not written outside local dir"))
+	}
+}
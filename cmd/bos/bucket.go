@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+type bucketResult struct {
+	Bucket         string `json:"bucket"`
+	DeletedObjects int    `json:"deletedObjects,omitempty"`
+}
+
+func runMb(c *cli, args []string) error {
+	args, err := c.parseArgs("mb", args, 1, 1, nil)
+
+	if err != nil {
+		return err
+	}
+
+	l, err := parseBosLocation(args[0], false)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	if err := bosClient.CreateBucket(l.bucketName, nil); err != nil {
+		return err
+	}
+
+	return c.output(bucketResult{Bucket: l.bucketName}, func(w io.Writer) {
+		fmt.Fprintf(w, "make bucket: %s\n", BOS_SCHEME+l.bucketName)
+	})
+}
+
+func runRb(c *cli, args []string) error {
+	var force bool
+
+	args, err := c.parseArgs("rb", args, 1, 1, func(flags *flag.FlagSet) {
+		flags.BoolVar(&force, "f", false, "remove all BOS Objects and multipart uploads in the BOS Bucket first")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l, err := parseBosLocation(args[0], false)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	result := bucketResult{Bucket: l.bucketName}
+
+	if force {
+		deletePrefixResult, err := bosClient.DeletePrefix(l.bucketName, "", &bos.DeletePrefixOptions{
+			AbortMultipartUploads: true,
+			AllowEmptyPrefix:      true,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if err := deletePrefixResult.Err(); err != nil {
+			return err
+		}
+
+		result.DeletedObjects = deletePrefixResult.Deleted
+	}
+
+	if err := bosClient.DeleteBucket(l.bucketName, nil); err != nil {
+		return err
+	}
+
+	return c.output(result, func(w io.Writer) {
+		fmt.Fprintf(w, "remove bucket: %s\n", BOS_SCHEME+l.bucketName)
+	})
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+// BUCKET_CONFIG is the action of "bos bucket" command.
+const BUCKET_CONFIG_GET = "get"
+const BUCKET_CONFIG_SET = "set"
+const BUCKET_CONFIG_DELETE = "delete"
+const BUCKET_CONFIG_DIFF = "diff"
+
+// bucketConfig is a configuration of BOS Bucket managed as a JSON document, the document has the same
+// JSON shape as the struct of bos package, such as bos.BucketCors.
+type bucketConfig struct {
+	// newDocument returns a pointer to an empty document.
+	newDocument func() interface{}
+
+	get    func(bosClient *bos.Client, bucketName string) (interface{}, error)
+	set    func(bosClient *bos.Client, bucketName string, document interface{}) error
+	delete func(bosClient *bos.Client, bucketName string) error
+
+	// validate checks the desired document before diffing and setting, it can be nil.
+	validate func(bucketName string, document interface{}) error
+
+	// normalize clears the fields which can not be set, so the documents can be compared.
+	normalize func(document interface{})
+}
+
+var bucketConfigs = map[string]*bucketConfig{
+	"acl": &bucketConfig{
+		newDocument: func() interface{} { return new(bos.BucketAcl) },
+		get: func(bosClient *bos.Client, bucketName string) (interface{}, error) {
+			return bosClient.GetBucketAcl(bucketName, nil)
+		},
+		set: func(bosClient *bos.Client, bucketName string, document interface{}) error {
+			return bosClient.SetBucketAcl(bucketName, *document.(*bos.BucketAcl), nil)
+		},
+		// the ACL can not be deleted, it is reset to the canned ACL private
+		delete: func(bosClient *bos.Client, bucketName string) error {
+			return bosClient.SetBucketPrivate(bucketName, nil)
+		},
+		normalize: func(document interface{}) {
+			document.(*bos.BucketAcl).Owner = bos.BucketOwner{}
+		},
+	},
+	"cors": &bucketConfig{
+		newDocument: func() interface{} { return new(bos.BucketCors) },
+		get: func(bosClient *bos.Client, bucketName string) (interface{}, error) {
+			return bosClient.GetBucketCors(bucketName, nil)
+		},
+		set: func(bosClient *bos.Client, bucketName string, document interface{}) error {
+			return bosClient.SetBucketCors(bucketName, *document.(*bos.BucketCors), nil)
+		},
+		delete: func(bosClient *bos.Client, bucketName string) error {
+			return bosClient.DeleteBucketCors(bucketName, nil)
+		},
+	},
+	"logging": &bucketConfig{
+		newDocument: func() interface{} { return new(bos.BucketLogging) },
+		get: func(bosClient *bos.Client, bucketName string) (interface{}, error) {
+			return bosClient.GetBucketLogging(bucketName, nil)
+		},
+		// the logging is deleted if the status of document is disabled
+		set: func(bosClient *bos.Client, bucketName string, document interface{}) error {
+			bucketLogging := document.(*bos.BucketLogging)
+
+			if bucketLogging.Status == "disabled" {
+				return bosClient.DeleteBucketLogging(bucketName, nil)
+			}
+
+			return bosClient.SetBucketLogging(bucketName, bucketLogging.TargetBucket, bucketLogging.TargetPrefix, nil)
+		},
+		delete: func(bosClient *bos.Client, bucketName string) error {
+			return bosClient.DeleteBucketLogging(bucketName, nil)
+		},
+		normalize: func(document interface{}) {
+			bucketLogging := document.(*bos.BucketLogging)
+
+			if bucketLogging.Status == "" {
+				bucketLogging.Status = "enabled"
+			}
+
+			if bucketLogging.Status == "disabled" {
+				bucketLogging.TargetBucket, bucketLogging.TargetPrefix = "", ""
+			}
+		},
+	},
+	"lifecycle": &bucketConfig{
+		newDocument: func() interface{} { return new(bos.BucketLifecycle) },
+		get: func(bosClient *bos.Client, bucketName string) (interface{}, error) {
+			return bosClient.GetBucketLifecycle(bucketName, nil)
+		},
+		set: func(bosClient *bos.Client, bucketName string, document interface{}) error {
+			return bosClient.SetBucketLifecycle(bucketName, *document.(*bos.BucketLifecycle), nil)
+		},
+		delete: func(bosClient *bos.Client, bucketName string) error {
+			return bosClient.DeleteBucketLifecycle(bucketName, nil)
+		},
+		// SetBucketLifecycle sends the rules as is, so the rules and the conflicts between them are checked here
+		validate: func(bucketName string, document interface{}) error {
+			return document.(*bos.BucketLifecycle).Validate(bucketName)
+		},
+	},
+}
+
+type bucketConfigResult struct {
+	Bucket  string   `json:"bucket"`
+	Config  string   `json:"config"`
+	Changed bool     `json:"changed"`
+	Diff    []string `json:"diff,omitempty"`
+}
+
+func runBucket(c *cli, args []string) error {
+	if len(args) < 2 {
+		c.commandUsage("bucket", nil)
+		return errUsage
+	}
+
+	name, action := args[0], args[1]
+	config, ok := bucketConfigs[name]
+
+	switch action {
+	case BUCKET_CONFIG_GET, BUCKET_CONFIG_SET, BUCKET_CONFIG_DELETE, BUCKET_CONFIG_DIFF:
+	default:
+		ok = false
+	}
+
+	if !ok {
+		c.commandUsage("bucket", nil)
+		return errUsage
+	}
+
+	var file string
+
+	args, err := c.parseArgs("bucket", args[2:], 1, 1, func(flags *flag.FlagSet) {
+		if action == BUCKET_CONFIG_SET || action == BUCKET_CONFIG_DIFF {
+			flags.StringVar(&file, "f", "-", "the JSON document of configuration, \"-\" reads from stdin")
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l, err := parseBosLocation(args[0], false)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	current, err := currentDocument(bosClient, config, l.bucketName)
+
+	if err != nil {
+		return err
+	}
+
+	result := bucketConfigResult{Bucket: l.bucketName, Config: name}
+	url := BOS_SCHEME + l.bucketName
+
+	switch action {
+	case BUCKET_CONFIG_GET:
+		if current == nil {
+			return fmt.Errorf("The %s of %s is not configured.", name, url)
+		}
+
+		return c.output(current, func(w io.Writer) {
+			byteArray, _ := json.MarshalIndent(current, "", "  ")
+			fmt.Fprintf(w, "%s\n", byteArray)
+		})
+	case BUCKET_CONFIG_DELETE:
+		if err := config.delete(bosClient, l.bucketName); err != nil {
+			return err
+		}
+
+		result.Changed = current != nil
+
+		return c.output(result, func(w io.Writer) {
+			fmt.Fprintf(w, "delete %s: %s\n", name, url)
+		})
+	}
+
+	desired, err := c.readDocument(config, file)
+
+	if err != nil {
+		return err
+	}
+
+	if config.validate != nil {
+		if err := config.validate(l.bucketName, desired); err != nil {
+			return err
+		}
+	}
+
+	result.Diff = diffDocuments(current, desired)
+
+	for _, line := range result.Diff {
+		if !strings.HasPrefix(line, " ") {
+			result.Changed = true
+		}
+	}
+
+	if action == BUCKET_CONFIG_DIFF {
+		return c.output(result, func(w io.Writer) {
+			if !result.Changed {
+				fmt.Fprintf(w, "no changes to %s: %s\n", name, url)
+				return
+			}
+
+			fmt.Fprintf(w, "--- %s %s (current)\n+++ %s (desired)\n", url, name, file)
+
+			for _, line := range result.Diff {
+				fmt.Fprintln(w, line)
+			}
+		})
+	}
+
+	// the configuration is set only if it is changed, so a document can be applied many times
+	if result.Changed {
+		if err := config.set(bosClient, l.bucketName, desired); err != nil {
+			return err
+		}
+	}
+
+	result.Diff = nil
+
+	return c.output(result, func(w io.Writer) {
+		if result.Changed {
+			fmt.Fprintf(w, "set %s: %s\n", name, url)
+		} else {
+			fmt.Fprintf(w, "no changes to %s: %s\n", name, url)
+		}
+	})
+}
+
+// currentDocument gets the configuration of BOS Bucket, nil is returned if it is not configured.
+func currentDocument(bosClient *bos.Client, config *bucketConfig, bucketName string) (interface{}, error) {
+	document, err := config.get(bosClient, bucketName)
+
+	if bceError, ok := err.(*bce.Error); ok && bceError.StatusCode == http.StatusNotFound &&
+		bceError.Code != "NoSuchBucket" {
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.normalize != nil {
+		config.normalize(document)
+	}
+
+	return document, nil
+}
+
+// readDocument reads the JSON document of configuration from the file, or stdin if the file is "-".
+func (c *cli) readDocument(config *bucketConfig, file string) (interface{}, error) {
+	var byteArray []byte
+	var err error
+
+	if file == "-" {
+		byteArray, err = ioutil.ReadAll(c.stdin)
+	} else {
+		byteArray, err = ioutil.ReadFile(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	document := config.newDocument()
+
+	if err := json.Unmarshal(byteArray, document); err != nil {
+		return nil, fmt.Errorf("Invalid JSON document %s: %v", file, err)
+	}
+
+	if config.normalize != nil {
+		config.normalize(document)
+	}
+
+	return document, nil
+}
+
+// diffDocuments compares the indented JSON of two documents, a nil document has no lines.
+func diffDocuments(current, desired interface{}) []string {
+	documentLines := func(document interface{}) []string {
+		if document == nil {
+			return nil
+		}
+
+		byteArray, _ := json.MarshalIndent(document, "", "  ")
+
+		return strings.Split(string(byteArray), "\n")
+	}
+
+	return diffLines(documentLines(current), documentLines(desired))
+}
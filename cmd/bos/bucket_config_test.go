@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+const testCorsDocument = `{
+  "corsConfiguration": [
+    {
+      "allowedOrigins": ["http://www.example.com"],
+      "allowedMethods": ["GET", "PUT"],
+      "allowedHeaders": ["*"],
+      "allowedExposeHeaders": [],
+      "maxAgeSeconds": 3600
+    }
+  ]
+}`
+
+// countRequests returns the count of recorded requests with the method and the query param.
+func (tc *testCLI) countRequests(method, param string) int {
+	count := 0
+
+	for _, req := range tc.server.Requests() {
+		if _, ok := req.Query[param]; ok && req.Method == method {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestBucketCors(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "cors.json")
+
+	if err := ioutil.WriteFile(file, []byte(testCorsDocument), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var result bucketConfigResult
+
+	if err := json.Unmarshal([]byte(tc.run(t, "-json bucket cors diff -f "+file+" bos://bostest-cli")), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Changed || len(result.Diff) == 0 || !strings.HasPrefix(result.Diff[0], "+") {
+		t.Error(util.FormatTest("bucket cors diff", tc.stdout.String(), "all lines added"))
+	}
+
+	if count := tc.countRequests("PUT", "cors"); count != 0 {
+		t.Error(util.FormatTest("bucket cors diff", strconv.Itoa(count), "0"))
+	}
+
+	for i := 0; i < 2; i++ {
+		tc.run(t, "bucket cors set -f "+file+" bos://bostest-cli")
+	}
+
+	if count := tc.countRequests("PUT", "cors"); count != 1 {
+		t.Error(util.FormatTest("bucket cors set twice", strconv.Itoa(count), "1"))
+	}
+
+	var bucketCors bos.BucketCors
+
+	if err := json.Unmarshal([]byte(tc.run(t, "bucket cors get bos://bostest-cli")), &bucketCors); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bucketCors.CorsConfiguration) != 1 || bucketCors.CorsConfiguration[0].MaxAgeSeconds != 3600 {
+		t.Error(util.FormatTest("bucket cors get", tc.stdout.String(), testCorsDocument))
+	}
+
+	tc.stdin = strings.Replace(testCorsDocument, "3600", "60", 1)
+	output := tc.run(t, "bucket cors diff bos://bostest-cli")
+	tc.stdin = ""
+
+	if !strings.Contains(output, `-      "maxAgeSeconds": 3600`) || !strings.Contains(output, `+      "maxAgeSeconds": 60`) {
+		t.Error(util.FormatTest("bucket cors diff stdin", output, "maxAgeSeconds changed"))
+	}
+
+	tc.run(t, "bucket cors delete bos://bostest-cli")
+
+	c := &cli{stdout: ioutil.Discard, stderr: ioutil.Discard, config: tc.server.NewConfig()}
+
+	if err := c.run(strings.Fields("bucket cors get bos://bostest-cli")); err == nil {
+		t.Error(util.FormatTest("bucket cors get after delete", "nil", "error"))
+	}
+}
+
+func TestBucketLifecycle(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	rule := func(id string, days int) bos.BucketLifecycleItem {
+		rule, err := bos.NewBucketLifecycleRuleBuilder(id).Prefix("bostest-cli", "logs/").AfterDays(days).
+			DeleteObject().Build()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return rule
+	}
+
+	// the two rules delete the same objects
+	conflicted, _ := json.Marshal(bos.BucketLifecycle{Rule: []bos.BucketLifecycleItem{rule("1", 30), rule("2", 60)}})
+	c := &cli{stdout: ioutil.Discard, stderr: ioutil.Discard, config: tc.server.NewConfig()}
+
+	for _, action := range []string{"diff", "set"} {
+		c.stdin = strings.NewReader(string(conflicted))
+
+		if err := c.run(strings.Fields("bucket lifecycle " + action + " bos://bostest-cli")); err == nil {
+			t.Error(util.FormatTest("bucket lifecycle "+action+" conflicted", "nil", "error"))
+		}
+	}
+
+	if count := tc.countRequests("PUT", "lifecycle"); count != 0 {
+		t.Error(util.FormatTest("bucket lifecycle set conflicted", strconv.Itoa(count), "0"))
+	}
+
+	valid, _ := json.Marshal(bos.BucketLifecycle{Rule: []bos.BucketLifecycleItem{rule("1", 30)}})
+	tc.stdin = string(valid)
+	tc.run(t, "bucket lifecycle set bos://bostest-cli")
+	tc.stdin = ""
+
+	if count := tc.countRequests("PUT", "lifecycle"); count != 1 {
+		t.Error(util.FormatTest("bucket lifecycle set", strconv.Itoa(count), "1"))
+	}
+}
+
+func TestBucketLogging(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	tc.stdin = `{"status": "disabled"}`
+
+	if output := tc.run(t, "bucket logging set bos://bostest-cli"); !strings.HasPrefix(output, "no changes") {
+		t.Error(util.FormatTest("bucket logging set disabled", output, "no changes"))
+	}
+
+	tc.stdin = `{"targetBucket": "bostest-cli", "targetPrefix": "logs/"}`
+	tc.run(t, "bucket logging set bos://bostest-cli")
+
+	if output := tc.run(t, "bucket logging diff bos://bostest-cli"); !strings.HasPrefix(output, "no changes") {
+		t.Error(util.FormatTest("bucket logging diff", output, "no changes"))
+	}
+
+	var bucketLogging bos.BucketLogging
+
+	if err := json.Unmarshal([]byte(tc.run(t, "bucket logging get bos://bostest-cli")), &bucketLogging); err != nil {
+		t.Fatal(err)
+	}
+
+	if bucketLogging.Status != "enabled" || bucketLogging.TargetPrefix != "logs/" {
+		t.Error(util.FormatTest("bucket logging get", tc.stdout.String(), "enabled"))
+	}
+}
+
+func TestBucketAcl(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	tc.stdin = tc.run(t, "bucket acl get bos://bostest-cli")
+
+	if output := tc.run(t, "bucket acl set bos://bostest-cli"); !strings.HasPrefix(output, "no changes") {
+		t.Error(util.FormatTest("bucket acl set current", output, "no changes"))
+	}
+
+	tc.stdin = `{"accessControlList": [{"grantee": [{"id": "*"}], "permission": ["READ"]}]}`
+	tc.run(t, "bucket acl set bos://bostest-cli")
+	tc.run(t, "bucket acl delete bos://bostest-cli")
+
+	if count := tc.countRequests("PUT", "acl"); count != 2 {
+		t.Error(util.FormatTest("bucket acl", strconv.Itoa(count), "2"))
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		a, b, expected string
+	}{
+		{"", "", ""},
+		{"a,b,c", "a,b,c", " a, b, c"},
+		{"", "a,b", "+a,+b"},
+		{"a,b", "", "-a,-b"},
+		{"a,b,c", "a,x,c", " a,-b,+x, c"},
+		{"a,b,c,d", "b,d,e", "-a, b,-c, d,+e"},
+	}
+
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+
+		return strings.Split(s, ",")
+	}
+
+	for _, c := range cases {
+		if got := strings.Join(diffLines(split(c.a), split(c.b)), ","); got != c.expected {
+			t.Error(util.FormatTest("diffLines "+c.a+" "+c.b, got, c.expected))
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/guoyao/baidubce-sdk-go/bce"
+)
+
+// loadConfig loads the config of the profile by bce.LoadConfig, the checksum is always enabled.
+func loadConfig(profile string) (*bce.Config, error) {
+	return bce.LoadConfig(&bce.LoadConfigOption{
+		Profile:   profile,
+		Overrides: &bce.Config{Checksum: true},
+	})
+}
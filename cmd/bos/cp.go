@@ -0,0 +1,339 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+// TRANSFER is the type of a transfer between local and BOS.
+const TRANSFER_UPLOAD = "upload"
+const TRANSFER_DOWNLOAD = "download"
+const TRANSFER_COPY = "copy"
+
+// transfer is a file or BOS Object to copy or move.
+type transfer struct {
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Size        int64  `json:"size"`
+	Error       string `json:"error,omitempty"`
+
+	src, dst location
+}
+
+func (t *transfer) String() string {
+	return fmt.Sprintf("%s: %s -> %s", t.Type, t.Source, t.Destination)
+}
+
+type transferOptions struct {
+	recursive          bool
+	parallel           int
+	multipartThreshold int64
+	partSize           int64
+}
+
+func runCp(c *cli, args []string) error {
+	return runCopyOrMove(c, "cp", args, false)
+}
+
+func runMv(c *cli, args []string) error {
+	return runCopyOrMove(c, "mv", args, true)
+}
+
+func runCopyOrMove(c *cli, name string, args []string, move bool) error {
+	options := transferOptions{}
+
+	args, err := c.parseArgs(name, args, 2, 2, func(flags *flag.FlagSet) {
+		flags.BoolVar(&options.recursive, "r", false, "transfer all files of a directory or BOS Objects under a prefix")
+		flags.IntVar(&options.parallel, "parallel", bos.DefaultSyncParallel, "the count of concurrent transfers")
+		flags.Int64Var(&options.multipartThreshold, "multipart-threshold", bos.DefaultSyncMultipartThreshold,
+			"the file size to switch to multipart upload or copy")
+		flags.Int64Var(&options.partSize, "part-size", bos.DefaultSyncPartSize, "the part size of multipart upload or copy")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if options.parallel <= 0 || options.multipartThreshold <= 0 || options.partSize <= 0 {
+		return fmt.Errorf("The parallel, multipart-threshold and part-size should be positive.")
+	}
+
+	src, err := parseLocation(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseLocation(args[1])
+
+	if err != nil {
+		return err
+	}
+
+	if !src.isBos() && !dst.isBos() {
+		return fmt.Errorf("Either source or destination should be a %s URL.", BOS_SCHEME)
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	transfers, err := planTransfers(bosClient, src, dst, options.recursive)
+
+	if err != nil {
+		return err
+	}
+
+	runTransfers(bosClient, transfers, options, move)
+
+	failed := 0
+
+	for _, t := range transfers {
+		if t.Error != "" {
+			failed++
+		}
+	}
+
+	err = c.output(transfers, func(w io.Writer) {
+		for _, t := range transfers {
+			if t.Error != "" {
+				fmt.Fprintf(w, "failed %s: %s\n", t, t.Error)
+			} else {
+				fmt.Fprintln(w, t)
+			}
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transfers failed.", failed, len(transfers))
+	}
+
+	return nil
+}
+
+func newTransfer(src, dst location, size int64) *transfer {
+	t := &transfer{Source: src.String(), Destination: dst.String(), Size: size, src: src, dst: dst}
+
+	switch {
+	case src.isBos() && dst.isBos():
+		t.Type = TRANSFER_COPY
+	case src.isBos():
+		t.Type = TRANSFER_DOWNLOAD
+	default:
+		t.Type = TRANSFER_UPLOAD
+	}
+
+	return t
+}
+
+// planTransfers lists the files or BOS Objects of source, and maps them to destination.
+func planTransfers(bosClient *bos.Client, src, dst location, recursive bool) ([]*transfer, error) {
+	transfers := make([]*transfer, 0)
+
+	if !recursive {
+		var size int64
+
+		if src.isBos() {
+			if src.objectKey == "" || strings.HasSuffix(src.objectKey, "/") {
+				return nil, fmt.Errorf("%s is a prefix, use -r to transfer all BOS Objects under it.", src)
+			}
+
+			objectMetadata, err := bosClient.GetObjectMetadata(src.bucketName, src.objectKey, nil)
+
+			if err != nil {
+				return nil, err
+			}
+
+			size = objectMetadata.ContentLength
+		} else {
+			fileInfo, err := os.Stat(src.localPath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if fileInfo.IsDir() {
+				return nil, fmt.Errorf("%s is a directory, use -r to transfer all files of it.", src)
+			}
+
+			size = fileInfo.Size()
+		}
+
+		if dst.isDir() {
+			var err error
+
+			if dst, err = dst.checkedChild(src.baseName()); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(transfers, newTransfer(src, dst, size)), nil
+	}
+
+	if src.isBos() {
+		prefix := src.dirPrefix()
+
+		err := listObjects(bosClient, src.bucketName, prefix, "", func(objects []bos.ObjectSummary, _ []string) error {
+			for _, objectSummary := range objects {
+				// the keys ending with "/" are placeholders of directories
+				if strings.HasSuffix(objectSummary.Key, "/") {
+					continue
+				}
+
+				// the keys which leave the local directory are reported as failed transfers
+				child, err := dst.checkedChild(strings.TrimPrefix(objectSummary.Key, prefix))
+				t := newTransfer(location{bucketName: src.bucketName, objectKey: objectSummary.Key}, child,
+					objectSummary.Size)
+
+				if err != nil {
+					t.Error = err.Error()
+				}
+
+				transfers = append(transfers, t)
+			}
+
+			return nil
+		})
+
+		return transfers, err
+	}
+
+	err := filepath.Walk(src.localPath, func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fileInfo.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(src.localPath, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		transfers = append(transfers, newTransfer(location{localPath: filePath},
+			dst.child(filepath.ToSlash(relativePath)), fileInfo.Size()))
+
+		return nil
+	})
+
+	return transfers, err
+}
+
+// runTransfers runs the transfers concurrently, the source is removed after it is transferred if move is true.
+func runTransfers(bosClient *bos.Client, transfers []*transfer, options transferOptions, move bool) {
+	queue := make(chan *transfer)
+
+	var waitGroup sync.WaitGroup
+
+	for i := 0; i < options.parallel; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for t := range queue {
+				// the transfers failed in planning are not run
+				if t.Error != "" {
+					continue
+				}
+
+				err := t.run(bosClient, options)
+
+				if err == nil && move {
+					if t.src.isBos() {
+						err = bosClient.DeleteObject(t.src.bucketName, t.src.objectKey, nil)
+					} else {
+						err = os.Remove(t.src.localPath)
+					}
+				}
+
+				if err != nil {
+					t.Error = err.Error()
+				}
+			}
+		}()
+	}
+
+	for _, t := range transfers {
+		queue <- t
+	}
+
+	close(queue)
+	waitGroup.Wait()
+}
+
+func (t *transfer) run(bosClient *bos.Client, options transferOptions) error {
+	switch t.Type {
+	case TRANSFER_UPLOAD:
+		if t.Size >= options.multipartThreshold {
+			_, err := bosClient.MultipartUploadFromFile(t.dst.bucketName, t.dst.objectKey, t.src.localPath,
+				options.partSize)
+
+			return err
+		}
+
+		file, err := os.Open(t.src.localPath)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		_, err = bosClient.PutObject(t.dst.bucketName, t.dst.objectKey, file, nil, nil)
+
+		return err
+	case TRANSFER_DOWNLOAD:
+		if err := os.MkdirAll(filepath.Dir(t.dst.localPath), 0755); err != nil {
+			return err
+		}
+
+		file, err := os.Create(t.dst.localPath)
+
+		if err != nil {
+			return err
+		}
+
+		getObjectRequest := &bos.GetObjectRequest{BucketName: t.src.bucketName, ObjectKey: t.src.objectKey}
+
+		if _, err := bosClient.GetObjectToFile(getObjectRequest, file, nil); err != nil {
+			os.Remove(t.dst.localPath)
+			return err
+		}
+
+		return nil
+	}
+
+	copyObjectRequest := bos.CopyObjectRequest{
+		SrcBucketName:  t.src.bucketName,
+		SrcKey:         t.src.objectKey,
+		DestBucketName: t.dst.bucketName,
+		DestKey:        t.dst.objectKey,
+	}
+
+	if t.Size >= options.multipartThreshold {
+		_, err := bosClient.MultipartCopyObjectFromRequest(copyObjectRequest, options.partSize, 0, nil)
+		return err
+	}
+
+	_, err := bosClient.CopyObjectFromRequest(copyObjectRequest, nil)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+// BOS_SCHEME is the scheme of the URLs of BOS Objects, such as "bos://bucket/key".
+const BOS_SCHEME = "bos://"
+
+// location is a BOS Object (or prefix) referred by a bos:// URL, or a local path.
+type location struct {
+	bucketName, objectKey string
+	localPath             string
+}
+
+func parseLocation(s string) (location, error) {
+	if !strings.HasPrefix(s, BOS_SCHEME) {
+		if s == "" {
+			return location{}, fmt.Errorf("The local path should not be empty.")
+		}
+
+		return location{localPath: s}, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(s, BOS_SCHEME), "/", 2)
+	l := location{bucketName: parts[0]}
+
+	if len(parts) == 2 {
+		l.objectKey = parts[1]
+	}
+
+	if l.bucketName == "" {
+		return l, fmt.Errorf("Invalid URL %s, the bucket name should not be empty.", s)
+	}
+
+	if strings.HasPrefix(l.objectKey, "/") {
+		return l, fmt.Errorf("Invalid URL %s, the object key should not start with '/'.", s)
+	}
+
+	return l, nil
+}
+
+// parseBosLocation parses a bos:// URL, the object key is required if needKey is true.
+func parseBosLocation(s string, needKey bool) (location, error) {
+	l, err := parseLocation(s)
+
+	if err != nil {
+		return l, err
+	}
+
+	if !l.isBos() {
+		return l, fmt.Errorf("Invalid URL %s, it should start with %s.", s, BOS_SCHEME)
+	}
+
+	if needKey && l.objectKey == "" {
+		return l, fmt.Errorf("Invalid URL %s, the object key should not be empty.", s)
+	}
+
+	return l, nil
+}
+
+func (l location) isBos() bool {
+	return l.bucketName != ""
+}
+
+func (l location) String() string {
+	if l.isBos() {
+		return BOS_SCHEME + l.bucketName + "/" + l.objectKey
+	}
+
+	return l.localPath
+}
+
+// child returns the location of the relative slash separated path under l as a directory.
+func (l location) child(relativePath string) location {
+	if l.isBos() {
+		return location{bucketName: l.bucketName, objectKey: l.dirPrefix() + relativePath}
+	}
+
+	return location{localPath: filepath.Join(l.localPath, filepath.FromSlash(relativePath))}
+}
+
+// checkedChild returns the same location as child, but it returns an error along with the location if l is
+// a local directory and the relative path, which may be from an object key, leaves it.
+func (l location) checkedChild(relativePath string) (location, error) {
+	if l.isBos() {
+		return l.child(relativePath), nil
+	}
+
+	localPath, err := util.JoinLocalPath(l.localPath, relativePath)
+
+	if err != nil {
+		return l.child(relativePath), err
+	}
+
+	return location{localPath: localPath}, nil
+}
+
+// dirPrefix returns the object key as a prefix of directory, which ends with "/" if it is not empty.
+func (l location) dirPrefix() string {
+	if l.objectKey == "" || strings.HasSuffix(l.objectKey, "/") {
+		return l.objectKey
+	}
+
+	return l.objectKey + "/"
+}
+
+// isDir checks whether l refers to a directory: a bos:// URL ending with "/" or without key,
+// or an existing local directory, or a local path ending with the separator.
+func (l location) isDir() bool {
+	if l.isBos() {
+		return l.objectKey == "" || strings.HasSuffix(l.objectKey, "/")
+	}
+
+	if strings.HasSuffix(l.localPath, "/") || strings.HasSuffix(l.localPath, string(filepath.Separator)) {
+		return true
+	}
+
+	fileInfo, err := os.Stat(l.localPath)
+
+	return err == nil && fileInfo.IsDir()
+}
+
+// baseName returns the last element of the object key or local path.
+func (l location) baseName() string {
+	if l.isBos() {
+		return path.Base(l.objectKey)
+	}
+
+	return filepath.Base(l.localPath)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+type bucketEntry struct {
+	Name         string `json:"name"`
+	Location     string `json:"location"`
+	CreationDate string `json:"creationDate"`
+}
+
+type objectEntry struct {
+	Key          string `json:"key"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified"`
+	ETag         string `json:"eTag"`
+	StorageClass string `json:"storageClass"`
+}
+
+type listResult struct {
+	Bucket   string        `json:"bucket"`
+	Prefix   string        `json:"prefix"`
+	Prefixes []string      `json:"prefixes"`
+	Objects  []objectEntry `json:"objects"`
+}
+
+// listObjects lists all BOS Objects under the prefix page by page, the common prefixes are
+// rolled up by the delimiter if it is not empty.
+func listObjects(bosClient *bos.Client, bucketName, prefix, delimiter string,
+	handle func(objects []bos.ObjectSummary, prefixes []string) error) error {
+
+	listObjectsRequest := bos.ListObjectsRequest{
+		BucketName: bucketName,
+		Prefix:     prefix,
+		Delimiter:  delimiter,
+	}
+
+	for {
+		listObjectsResponse, err := bosClient.ListObjectsFromRequest(listObjectsRequest, nil)
+
+		if err != nil {
+			return err
+		}
+
+		prefixes := listObjectsResponse.GetCommonPrefixes()
+
+		if err := handle(listObjectsResponse.Contents, prefixes); err != nil {
+			return err
+		}
+
+		if !listObjectsResponse.IsTruncated {
+			return nil
+		}
+
+		listObjectsRequest.Marker = listObjectsResponse.NextMarker
+
+		if listObjectsRequest.Marker == "" {
+			if len(listObjectsResponse.Contents) == 0 {
+				return nil
+			}
+
+			listObjectsRequest.Marker = listObjectsResponse.Contents[len(listObjectsResponse.Contents)-1].Key
+		}
+	}
+}
+
+func runLs(c *cli, args []string) error {
+	var recursive, human bool
+
+	args, err := c.parseArgs("ls", args, 0, 1, func(flags *flag.FlagSet) {
+		flags.BoolVar(&recursive, "r", false, "list all BOS Objects under the prefix recursively")
+		flags.BoolVar(&human, "h", false, "show sizes in human readable format")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return listBuckets(c, bosClient)
+	}
+
+	l, err := parseBosLocation(args[0], false)
+
+	if err != nil {
+		return err
+	}
+
+	delimiter := "/"
+
+	if recursive {
+		delimiter = ""
+	}
+
+	result := listResult{
+		Bucket:   l.bucketName,
+		Prefix:   l.objectKey,
+		Prefixes: make([]string, 0),
+		Objects:  make([]objectEntry, 0),
+	}
+
+	err = listObjects(bosClient, l.bucketName, l.objectKey, delimiter,
+		func(objects []bos.ObjectSummary, prefixes []string) error {
+			result.Prefixes = append(result.Prefixes, prefixes...)
+
+			for _, objectSummary := range objects {
+				result.Objects = append(result.Objects, objectEntry{
+					Key:          objectSummary.Key,
+					Size:         objectSummary.Size,
+					LastModified: objectSummary.LastModified,
+					ETag:         objectSummary.ETag,
+					StorageClass: objectSummary.StorageClass,
+				})
+			}
+
+			return nil
+		})
+
+	if err != nil {
+		return err
+	}
+
+	return c.output(result, func(w io.Writer) {
+		for _, prefix := range result.Prefixes {
+			fmt.Fprintf(w, "%20s %10s  %s\n", "", "PRE", BOS_SCHEME+l.bucketName+"/"+prefix)
+		}
+
+		for _, object := range result.Objects {
+			size := strconv.FormatInt(object.Size, 10)
+
+			if human {
+				size = humanSize(object.Size)
+			}
+
+			fmt.Fprintf(w, "%20s %10s  %s\n", object.LastModified, size, BOS_SCHEME+l.bucketName+"/"+object.Key)
+		}
+	})
+}
+
+func listBuckets(c *cli, bosClient *bos.Client) error {
+	bucketSummary, err := bosClient.ListBuckets(nil)
+
+	if err != nil {
+		return err
+	}
+
+	buckets := make([]bucketEntry, 0, len(bucketSummary.Buckets))
+
+	for _, bucket := range bucketSummary.Buckets {
+		buckets = append(buckets, bucketEntry{
+			Name:         bucket.Name,
+			Location:     bucket.Location,
+			CreationDate: bucket.CreationDate.UTC().Format("2006-01-02T15:04:05Z"),
+		})
+	}
+
+	return c.output(buckets, func(w io.Writer) {
+		for _, bucket := range buckets {
+			fmt.Fprintf(w, "%20s %10s  %s\n", bucket.CreationDate, bucket.Location, BOS_SCHEME+bucket.Name)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+// Command bos is a command line tool for BOS built on bos.Client.
+//
+// Usage:
+//
+//	bos [-profile name] [-region region] [-endpoint endpoint] [-json] <command> [arguments]
+//
+// The commands are:
+//
+//	ls       list BOS Buckets, or BOS Objects under a prefix
+//	cp       copy files and BOS Objects between local and bos:// URLs
+//	mv       move files and BOS Objects between local and bos:// URLs
+//	rm       remove a BOS Object, or all BOS Objects under a prefix
+//	sync     sync a local directory with a prefix of BOS Bucket
+//	mb       make a BOS Bucket
+//	rb       remove a BOS Bucket
+//	stat     show the metadata of a BOS Object
+//	presign  generate a presigned URL of a BOS Object
+//	bucket   get, set, delete or diff the acl, cors, logging or lifecycle of a BOS Bucket
+//
+// A BOS Object is referred by the URL "bos://<bucket>/<key>", and the other arguments are local paths.
+// Run "bos <command> -h" for the options of a command.
+//
+// The config is loaded by bce.LoadConfig from the profile in "~/.bce/config" or "~/.bce/credentials" (the
+// "default" profile if -profile is not specified), and the environment variables such as BAIDU_BCE_AK and
+// BAIDU_BCE_SK override the profile:
+//
+//	[default]
+//	access_key_id = <access key id>
+//	secret_access_key = <secret access key>
+//	region = bj
+//	endpoint =
+//
+// The configurations of BOS Bucket are JSON documents of the same shape as bos.BucketAcl, bos.BucketCors,
+// bos.BucketLogging and bos.BucketLifecycle. "bos bucket <config> set" only calls BOS if the document is
+// different from the current configuration, and "bos bucket <config> diff" shows the changes without setting:
+//
+//	bos bucket cors get bos://mybucket > cors.json
+//	bos bucket cors diff -f cors.json bos://mybucket
+//	bos bucket cors set -f cors.json bos://mybucket
+//
+// With -json, the result of each command is written as a JSON document for scripting.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+// errUsage is returned if the arguments are invalid, the usage has been written to stderr.
+var errUsage = errors.New("invalid arguments")
+
+type command struct {
+	name, usage, description string
+	run                      func(c *cli, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{"ls", "ls [-r] [-h] [bos://<bucket>[/<prefix>]]", "list BOS Buckets, or BOS Objects under a prefix", runLs},
+		{"cp", "cp [-r] [-parallel n] [-multipart-threshold bytes] [-part-size bytes] <source> <destination>",
+			"copy files and BOS Objects between local and bos:// URLs", runCp},
+		{"mv", "mv [-r] [-parallel n] [-multipart-threshold bytes] [-part-size bytes] <source> <destination>",
+			"move files and BOS Objects between local and bos:// URLs", runMv},
+		{"rm", "rm [-r] [-dryrun] [-parallel n] bos://<bucket>/<key or prefix>",
+			"remove a BOS Object, or all BOS Objects under a prefix", runRm},
+		{"sync", "sync [-delete] [-dryrun] [-compare size|mtime|etag] [-include pattern] [-exclude pattern] " +
+			"[-parallel n] <source> <destination>", "sync a local directory with a prefix of BOS Bucket", runSync},
+		{"mb", "mb bos://<bucket>", "make a BOS Bucket", runMb},
+		{"rb", "rb [-f] bos://<bucket>", "remove a BOS Bucket, -f removes all BOS Objects in it first", runRb},
+		{"stat", "stat bos://<bucket>/<key>", "show the metadata of a BOS Object", runStat},
+		{"presign", "presign [-expires seconds] bos://<bucket>/<key>", "generate a presigned URL of a BOS Object",
+			runPresign},
+		{"bucket", "bucket acl|cors|logging|lifecycle get|set|delete|diff [-f file] bos://<bucket>",
+			"get, set, delete or diff the acl, cors, logging or lifecycle of a BOS Bucket", runBucket},
+	}
+}
+
+// cli is the state of a command line, the client is created on demand by the global options.
+type cli struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+
+	// config is loaded from the environment variables or the profile if it is nil.
+	config *bce.Config
+	client *bos.Client
+
+	profile, region, endpoint string
+	jsonOutput                bool
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+
+	if err := c.run(os.Args[1:]); err != nil {
+		if err == errUsage {
+			os.Exit(2)
+		}
+
+		fmt.Fprintf(os.Stderr, "bos: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "Usage: bos [-profile name] [-region region] [-endpoint endpoint] [-json] <command> [arguments]")
+	fmt.Fprintln(c.stderr, "\nThe commands are:")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(c.stderr, "  %-8s %s\n", cmd.name, cmd.description)
+	}
+}
+
+func (c *cli) run(args []string) error {
+	flags := flag.NewFlagSet("bos", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.StringVar(&c.profile, "profile", "", "the profile in ~/.bce/config or ~/.bce/credentials")
+	flags.StringVar(&c.region, "region", "", "the region of BOS, such as bj and gz")
+	flags.StringVar(&c.endpoint, "endpoint", "", "the endpoint of BOS, such as bj.bcebos.com")
+	flags.BoolVar(&c.jsonOutput, "json", false, "write the result as JSON")
+
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		c.usage()
+		return errUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == flags.Arg(0) {
+			return cmd.run(c, flags.Args()[1:])
+		}
+	}
+
+	fmt.Fprintf(c.stderr, "bos: unknown command %s\n", flags.Arg(0))
+	c.usage()
+
+	return errUsage
+}
+
+// parseArgs parses the options of a command by setup, and checks the count of the other arguments.
+func (c *cli) parseArgs(name string, args []string, minArgs, maxArgs int, setup func(flags *flag.FlagSet)) ([]string, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		c.commandUsage(name, flags)
+	}
+
+	if setup != nil {
+		setup(flags)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, errUsage
+	}
+
+	if flags.NArg() < minArgs || flags.NArg() > maxArgs {
+		flags.Usage()
+		return nil, errUsage
+	}
+
+	return flags.Args(), nil
+}
+
+// commandUsage writes the usage of a command, and the defaults of its options if flags is not nil.
+func (c *cli) commandUsage(name string, flags *flag.FlagSet) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			fmt.Fprintf(c.stderr, "Usage: bos %s\n\n%s\n", cmd.usage, strings.ToUpper(cmd.description[:1])+cmd.description[1:])
+		}
+	}
+
+	if flags != nil {
+		flags.PrintDefaults()
+	}
+}
+
+// bosClient returns the client created by the config and the global options.
+func (c *cli) bosClient() (*bos.Client, error) {
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	config := c.config
+
+	if config == nil {
+		var err error
+
+		if config, err = loadConfig(c.profile); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.region != "" {
+		config.Region = c.region
+	}
+
+	if c.endpoint != "" {
+		config.Endpoint = c.endpoint
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	c.client = bos.NewClient(bos.NewConfig(config))
+
+	return c.client, nil
+}
+
+// output writes v as JSON if -json is specified, otherwise the text is written by printText.
+func (c *cli) output(v interface{}, printText func(w io.Writer)) error {
+	if !c.jsonOutput {
+		printText(c.stdout)
+		return nil
+	}
+
+	byteArray, err := json.MarshalIndent(v, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.stdout, "%s\n", byteArray)
+
+	return err
+}
+
+// humanSize formats the size in bytes by units of 1024, such as "1.5K".
+func humanSize(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	value := float64(size)
+	units := []string{"K", "M", "G", "T", "P"}
+	unit := ""
+
+	for _, u := range units {
+		if value < 1024 {
+			break
+		}
+
+		value /= 1024
+		unit = u
+	}
+
+	return fmt.Sprintf("%.1f%s", value, unit)
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+	"github.com/guoyao/baidubce-sdk-go/bos/bostest"
+	"github.com/guoyao/baidubce-sdk-go/util"
+)
+
+type testCLI struct {
+	server *bostest.Server
+	stdin  string
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+// run runs a command line against the fake server, and returns the stdout.
+func (tc *testCLI) run(t *testing.T, line string) string {
+	tc.stdout.Reset()
+	tc.stderr.Reset()
+
+	config := tc.server.NewConfig()
+	config.Checksum = true
+	c := &cli{stdin: strings.NewReader(tc.stdin), stdout: &tc.stdout, stderr: &tc.stderr, config: config}
+
+	if err := c.run(strings.Fields(line)); err != nil {
+		t.Fatalf("bos %s failed: %v\n%s", line, err, tc.stderr.String())
+	}
+
+	return tc.stdout.String()
+}
+
+func newTestCLI(t *testing.T) (*testCLI, string) {
+	dir, err := ioutil.TempDir("", "bos-cli")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"a.txt":       "a",
+		"dir/b.txt":   "bb",
+		"dir/c/d.txt": "ddd",
+	}
+
+	for name, content := range files {
+		filePath := filepath.Join(dir, "src", filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tc := &testCLI{server: bostest.NewServer()}
+	tc.run(t, "mb bos://bostest-cli")
+
+	return tc, dir
+}
+
+// listKeys returns the keys under the prefix by "ls -r -json".
+func (tc *testCLI) listKeys(t *testing.T, url string) string {
+	var result listResult
+
+	if err := json.Unmarshal([]byte(tc.run(t, "-json ls -r "+url)), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, 0, len(result.Objects))
+
+	for _, object := range result.Objects {
+		keys = append(keys, object.Key)
+	}
+
+	return strings.Join(keys, ",")
+}
+
+func TestCopy(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	tc.run(t, "cp "+filepath.Join(dir, "src", "a.txt")+" bos://bostest-cli/")
+	tc.run(t, "cp -r -multipart-threshold 2 -part-size 1 "+filepath.Join(dir, "src")+" bos://bostest-cli/backup")
+
+	expected := "a.txt,backup/a.txt,backup/dir/b.txt,backup/dir/c/d.txt"
+
+	if got := tc.listKeys(t, "bos://bostest-cli"); got != expected {
+		t.Error(util.FormatTest("cp upload", got, expected))
+	}
+
+	output := tc.run(t, "ls bos://bostest-cli/backup/")
+	expected = "PRE  bos://bostest-cli/backup/dir/"
+
+	if !strings.Contains(output, expected) || strings.Contains(output, "d.txt") {
+		t.Error(util.FormatTest("ls", output, expected))
+	}
+
+	tc.run(t, "cp -r bos://bostest-cli/backup/dir bos://bostest-cli/copy")
+	tc.run(t, "cp -r bos://bostest-cli/copy "+filepath.Join(dir, "dst"))
+
+	for name, expected := range map[string]string{"b.txt": "bb", "c/d.txt": "ddd"} {
+		byteArray, err := ioutil.ReadFile(filepath.Join(dir, "dst", filepath.FromSlash(name)))
+
+		if err != nil || string(byteArray) != expected {
+			t.Error(util.FormatTest("cp download "+name, string(byteArray), expected))
+		}
+	}
+
+	tc.run(t, "mv bos://bostest-cli/a.txt "+filepath.Join(dir, "moved.txt"))
+
+	if byteArray, err := ioutil.ReadFile(filepath.Join(dir, "moved.txt")); err != nil || string(byteArray) != "a" {
+		t.Error(util.FormatTest("mv", string(byteArray), "a"))
+	}
+
+	tc.run(t, "mv "+filepath.Join(dir, "moved.txt")+" bos://bostest-cli/moved/")
+
+	if util.CheckFileExists(filepath.Join(dir, "moved.txt")) {
+		t.Error(util.FormatTest("mv", "source exists", "source removed"))
+	}
+
+	expected = "backup/a.txt,backup/dir/b.txt,backup/dir/c/d.txt,copy/b.txt,copy/c/d.txt,moved/moved.txt"
+
+	if got := tc.listKeys(t, "bos://bostest-cli"); got != expected {
+		t.Error(util.FormatTest("mv", got, expected))
+	}
+
+	tc.run(t, "rm bos://bostest-cli/moved/moved.txt")
+	tc.run(t, "rm -r -dryrun bos://bostest-cli/backup/")
+	tc.run(t, "rm -r bos://bostest-cli/copy/")
+
+	expected = "backup/a.txt,backup/dir/b.txt,backup/dir/c/d.txt"
+
+	if got := tc.listKeys(t, "bos://bostest-cli"); got != expected {
+		t.Error(util.FormatTest("rm", got, expected))
+	}
+
+	tc.run(t, "rb -f bos://bostest-cli")
+
+	if output := tc.run(t, "ls"); strings.Contains(output, "bostest-cli") {
+		t.Error(util.FormatTest("rb", output, "no bucket"))
+	}
+}
+
+func TestCopyOutsideLocalDir(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	bosClient := bos.NewClient(bos.NewConfig(tc.server.NewConfig()))
+
+	for _, key := range []string{"backup/a.txt", "backup/../escaped.txt", "backup/sub/../../escaped.txt"} {
+		if _, err := bosClient.PutObject("bostest-cli", key, "Hello World", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := &cli{stdout: &stdout, stderr: &stderr, config: tc.server.NewConfig()}
+	err := c.run([]string{"cp", "-r", "bos://bostest-cli/backup", filepath.Join(dir, "dst")})
+
+	if err == nil || strings.Count(stdout.String(), "failed") != 2 {
+		t.Error(util.FormatTest("cp outside local dir", stdout.String(), "2 failed transfers"))
+	}
+
+	if !util.CheckFileExists(filepath.Join(dir, "dst", "a.txt")) {
+		t.Error(util.FormatTest("cp outside local dir", "a.txt not exists", "a.txt downloaded"))
+	}
+
+	if util.CheckFileExists(filepath.Join(dir, "escaped.txt")) {
+		t.Error(util.FormatTest("cp outside local dir", "escaped.txt exists", "not written outside local dir"))
+	}
+}
+
+func TestSync(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	tc.run(t, "sync -exclude *.txt "+filepath.Join(dir, "src")+" bos://bostest-cli/sync")
+
+	if got := tc.listKeys(t, "bos://bostest-cli/sync"); got != "" {
+		t.Error(util.FormatTest("sync exclude", got, ""))
+	}
+
+	var result syncResult
+	output := tc.run(t, "-json sync "+filepath.Join(dir, "src")+" bos://bostest-cli/sync")
+
+	if err := json.Unmarshal([]byte(output), &result); err != nil || len(result.Actions) != 3 {
+		t.Error(util.FormatTest("sync -json", output, "3 actions"))
+	}
+
+	tc.run(t, "sync -delete bos://bostest-cli/sync/dir "+filepath.Join(dir, "src"))
+
+	var files []string
+
+	filepath.Walk(filepath.Join(dir, "src"), func(filePath string, fileInfo os.FileInfo, err error) error {
+		if err == nil && !fileInfo.IsDir() {
+			relativePath, _ := filepath.Rel(filepath.Join(dir, "src"), filePath)
+			files = append(files, filepath.ToSlash(relativePath))
+		}
+
+		return nil
+	})
+
+	sort.Strings(files)
+	expected := "b.txt,c/d.txt"
+
+	if got := strings.Join(files, ","); got != expected {
+		t.Error(util.FormatTest("sync download", got, expected))
+	}
+}
+
+func TestStatAndPresign(t *testing.T) {
+	tc, dir := newTestCLI(t)
+	defer tc.server.Close()
+	defer os.RemoveAll(dir)
+
+	tc.run(t, "cp "+filepath.Join(dir, "src", "dir", "b.txt")+" bos://bostest-cli/b.txt")
+
+	var result statResult
+
+	if err := json.Unmarshal([]byte(tc.run(t, "-json stat bos://bostest-cli/b.txt")), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Metadata == nil || result.Metadata.ContentLength != 2 {
+		t.Error(util.FormatTest("stat", tc.stdout.String(), "Content-Length 2"))
+	}
+
+	if output := tc.run(t, "stat bos://bostest-cli/b.txt"); !strings.Contains(output, "Content-Length:      2 (2B)") {
+		t.Error(util.FormatTest("stat", output, "Content-Length:      2 (2B)"))
+	}
+
+	url := strings.TrimSpace(tc.run(t, "presign -expires 60 bos://bostest-cli/b.txt"))
+	resp, err := tc.server.HTTPClient().Get(url)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	if byteArray, _ := ioutil.ReadAll(resp.Body); string(byteArray) != "bb" {
+		t.Error(util.FormatTest("presign", string(byteArray), "bb"))
+	}
+}
+
+func TestUsage(t *testing.T) {
+	server := bostest.NewServer()
+	defer server.Close()
+
+	cases := []string{"", "unknown", "cp a", "ls bos://a bos://b", "stat -unknown bos://a/b", "bucket cors",
+		"bucket website get bos://a", "bucket cors put bos://a", "bucket acl get -f a.json bos://a"}
+
+	for _, line := range cases {
+		var stdout, stderr bytes.Buffer
+		c := &cli{stdout: &stdout, stderr: &stderr, config: server.NewConfig()}
+
+		if err := c.run(strings.Fields(line)); err != errUsage || stderr.Len() == 0 {
+			t.Error(util.FormatTest("usage "+line, stderr.String(), "usage"))
+		}
+	}
+
+	c := &cli{stdout: ioutil.Discard, stderr: ioutil.Discard, config: server.NewConfig()}
+
+	for _, line := range []string{"cp a b", "stat bos://", "stat bos://a", "rm bos://a//b", "-region mars ls"} {
+		if err := c.run(strings.Fields(line)); err == nil || err == errUsage {
+			t.Error(util.FormatTest("invalid "+line, "nil", "error"))
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	cases := []struct {
+		s, bucketName, objectKey, localPath string
+	}{
+		{"bos://bucket", "bucket", "", ""},
+		{"bos://bucket/", "bucket", "", ""},
+		{"bos://bucket/a/b.txt", "bucket", "a/b.txt", ""},
+		{"a/b.txt", "", "", "a/b.txt"},
+	}
+
+	for _, c := range cases {
+		l, err := parseLocation(c.s)
+
+		if err != nil || l.bucketName != c.bucketName || l.objectKey != c.objectKey || l.localPath != c.localPath {
+			t.Error(util.FormatTest("parseLocation "+c.s, l.String(), c.bucketName+c.objectKey+c.localPath))
+		}
+	}
+
+	child := location{bucketName: "bucket", objectKey: "a"}.child("b/c.txt")
+
+	if child.String() != "bos://bucket/a/b/c.txt" {
+		t.Error(util.FormatTest("child", child.String(), "bos://bucket/a/b/c.txt"))
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	cases := map[int64]string{
+		0:                      "0B",
+		1023:                   "1023B",
+		1536:                   "1.5K",
+		1024 * 1024 * 5:        "5.0M",
+		1024 * 1024 * 1024 * 3: "3.0G",
+	}
+
+	for size, expected := range cases {
+		if got := humanSize(size); got != expected {
+			t.Error(util.FormatTest("humanSize", got, expected))
+		}
+	}
+}
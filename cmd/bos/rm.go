@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+type removeResult struct {
+	Bucket  string                           `json:"bucket"`
+	Prefix  string                           `json:"prefix"`
+	Deleted int                              `json:"deleted"`
+	DryRun  bool                             `json:"dryRun"`
+	Keys    []string                         `json:"keys,omitempty"`
+	Errors  []bos.DeleteMultipleObjectsError `json:"errors,omitempty"`
+}
+
+func runRm(c *cli, args []string) error {
+	var recursive, dryRun bool
+	var parallel int
+
+	args, err := c.parseArgs("rm", args, 1, 1, func(flags *flag.FlagSet) {
+		flags.BoolVar(&recursive, "r", false, "remove all BOS Objects under the prefix")
+		flags.BoolVar(&dryRun, "dryrun", false, "only list the BOS Objects which would be removed")
+		flags.IntVar(&parallel, "parallel", 1, "the count of concurrent batches to remove")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	l, err := parseBosLocation(args[0], !recursive)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	if !recursive {
+		result := removeResult{Bucket: l.bucketName, Prefix: l.objectKey, DryRun: dryRun, Keys: []string{l.objectKey}}
+
+		if !dryRun {
+			if err := bosClient.DeleteObject(l.bucketName, l.objectKey, nil); err != nil {
+				return err
+			}
+		}
+
+		result.Deleted = 1
+
+		return c.output(result, func(w io.Writer) {
+			printRemoved(w, l.bucketName, result.Keys, dryRun)
+		})
+	}
+
+	deletePrefixResult, err := bosClient.DeletePrefix(l.bucketName, l.objectKey, &bos.DeletePrefixOptions{
+		Parallel:         parallel,
+		DryRun:           dryRun,
+		AllowEmptyPrefix: true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	result := removeResult{
+		Bucket:  l.bucketName,
+		Prefix:  l.objectKey,
+		Deleted: deletePrefixResult.Deleted,
+		DryRun:  dryRun,
+		Keys:    deletePrefixResult.Keys,
+		Errors:  deletePrefixResult.Errors,
+	}
+
+	err = c.output(result, func(w io.Writer) {
+		printRemoved(w, l.bucketName, result.Keys, dryRun)
+
+		for _, deleteError := range result.Errors {
+			fmt.Fprintf(w, "failed delete: %s: %s\n", BOS_SCHEME+l.bucketName+"/"+deleteError.Key, deleteError.Error())
+		}
+
+		if !dryRun {
+			fmt.Fprintf(w, "%d objects deleted\n", result.Deleted)
+		}
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return deletePrefixResult.Err()
+}
+
+func printRemoved(w io.Writer, bucketName string, keys []string, dryRun bool) {
+	prefix := ""
+
+	if dryRun {
+		prefix = "(dryrun) "
+	}
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%sdelete: %s\n", prefix, BOS_SCHEME+bucketName+"/"+key)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/guoyao/baidubce-sdk-go/bce"
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+type statResult struct {
+	Bucket   string              `json:"bucket"`
+	Key      string              `json:"key"`
+	Metadata *bos.ObjectMetadata `json:"metadata"`
+}
+
+func runStat(c *cli, args []string) error {
+	args, err := c.parseArgs("stat", args, 1, 1, nil)
+
+	if err != nil {
+		return err
+	}
+
+	l, err := parseBosLocation(args[0], true)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	objectMetadata, err := bosClient.GetObjectMetadata(l.bucketName, l.objectKey, nil)
+
+	if err != nil {
+		return err
+	}
+
+	result := statResult{Bucket: l.bucketName, Key: l.objectKey, Metadata: objectMetadata}
+
+	return c.output(result, func(w io.Writer) {
+		fields := [][2]string{
+			{"URL", l.String()},
+			{"Content-Length", fmt.Sprintf("%d (%s)", objectMetadata.ContentLength, humanSize(objectMetadata.ContentLength))},
+			{"Content-Type", objectMetadata.ContentType},
+			{"Content-MD5", objectMetadata.ContentMD5},
+			{"Content-CRC64", objectMetadata.ContentCrc64},
+			{"ETag", objectMetadata.ETag},
+			{"Object-Type", objectMetadata.ObjectType},
+			{"Storage-Class", objectMetadata.StorageClass},
+			{"Version-Id", objectMetadata.VersionId},
+			{"Cache-Control", objectMetadata.CacheControl},
+			{"Content-Disposition", objectMetadata.ContentDisposition},
+			{"Expires", objectMetadata.Expires},
+		}
+
+		keys := make([]string, 0, len(objectMetadata.UserMetadata))
+
+		for key := range objectMetadata.UserMetadata {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fields = append(fields, [2]string{"x-bce-meta-" + key, objectMetadata.UserMetadata[key]})
+		}
+
+		for _, field := range fields {
+			if field[1] != "" {
+				fmt.Fprintf(w, "%-20s %s\n", field[0]+":", field[1])
+			}
+		}
+	})
+}
+
+func runPresign(c *cli, args []string) error {
+	var expires int
+
+	args, err := c.parseArgs("presign", args, 1, 1, func(flags *flag.FlagSet) {
+		flags.IntVar(&expires, "expires", bce.ExpirationPeriodInSeconds, "the expiration period of the URL in seconds")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if expires <= 0 {
+		return fmt.Errorf("The expires should be positive.")
+	}
+
+	l, err := parseBosLocation(args[0], true)
+
+	if err != nil {
+		return err
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	url, err := bosClient.GeneratePresignedUrl(l.bucketName, l.objectKey, &bce.SignOption{
+		ExpirationPeriodInSeconds: expires,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return c.output(map[string]interface{}{"url": url, "expires": expires}, func(w io.Writer) {
+		fmt.Fprintln(w, url)
+	})
+}
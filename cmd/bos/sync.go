@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/guoyao/baidubce-sdk-go/bos"
+)
+
+// stringsFlag is a flag which can be specified many times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+type syncActionEntry struct {
+	Type      string `json:"type"`
+	LocalPath string `json:"localPath"`
+	Key       string `json:"key,omitempty"`
+	Size      int64  `json:"size"`
+	Error     string `json:"error,omitempty"`
+}
+
+type syncResult struct {
+	Actions []syncActionEntry `json:"actions"`
+	Skipped int               `json:"skipped"`
+	DryRun  bool              `json:"dryRun"`
+}
+
+func runSync(c *cli, args []string) error {
+	var include, exclude stringsFlag
+	syncRequest := bos.SyncRequest{}
+
+	args, err := c.parseArgs("sync", args, 2, 2, func(flags *flag.FlagSet) {
+		flags.BoolVar(&syncRequest.Delete, "delete", false, "remove the files which do not exist in source")
+		flags.BoolVar(&syncRequest.DryRun, "dryrun", false, "only show the actions without transferring anything")
+		flags.StringVar(&syncRequest.CompareMode, "compare", bos.SYNC_COMPARE_MTIME,
+			"the way to find changed files: size, mtime or etag")
+		flags.Var(&include, "include", "only sync the paths matching the glob pattern, can be repeated")
+		flags.Var(&exclude, "exclude", "skip the paths matching the glob pattern, can be repeated")
+		flags.IntVar(&syncRequest.Parallel, "parallel", bos.DefaultSyncParallel, "the count of concurrent transfers")
+	})
+
+	if err != nil {
+		return err
+	}
+
+	src, err := parseLocation(args[0])
+
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseLocation(args[1])
+
+	if err != nil {
+		return err
+	}
+
+	if src.isBos() == dst.isBos() {
+		return fmt.Errorf("One of source and destination should be a %s URL, and the other a local directory.",
+			BOS_SCHEME)
+	}
+
+	bosClient, err := c.bosClient()
+
+	if err != nil {
+		return err
+	}
+
+	syncRequest.Include = include
+	syncRequest.Exclude = exclude
+	syncRequest.Output = c.stdout
+
+	if c.jsonOutput {
+		syncRequest.Output = ioutil.Discard
+	}
+
+	var result *bos.SyncResult
+
+	if src.isBos() {
+		syncRequest.LocalDir, syncRequest.BucketName, syncRequest.Prefix = dst.localPath, src.bucketName, src.objectKey
+		result, err = bosClient.SyncFromBucket(syncRequest)
+	} else {
+		syncRequest.LocalDir, syncRequest.BucketName, syncRequest.Prefix = src.localPath, dst.bucketName, dst.objectKey
+		result, err = bosClient.SyncToBucket(syncRequest)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	output := syncResult{Actions: make([]syncActionEntry, 0, len(result.Actions)), Skipped: result.Skipped,
+		DryRun: syncRequest.DryRun}
+
+	for _, action := range result.Actions {
+		entry := syncActionEntry{Type: action.Type, LocalPath: action.LocalPath, Key: action.Key, Size: action.Size}
+
+		if action.Error != nil {
+			entry.Error = action.Error.Error()
+		}
+
+		output.Actions = append(output.Actions, entry)
+	}
+
+	// the actions have been written by bos.SyncRequest.Output in text mode
+	err = c.output(output, func(w io.Writer) {
+		fmt.Fprintf(w, "%d actions, %d skipped\n", len(output.Actions), output.Skipped)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if failed := result.Errors(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d actions failed.", len(failed), len(result.Actions))
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package util
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"hash/crc64"
+	"io"
+)
+
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// ChecksumReader computes MD5, SHA-256 and CRC64 (ECMA) of the data while it is read, so the data is not read twice.
+type ChecksumReader struct {
+	reader io.Reader
+	md5    hash.Hash
+	sha256 hash.Hash
+	crc64  hash.Hash64
+	size   int64
+}
+
+// NewChecksumReader returns a util.ChecksumReader which reads from r.
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{reader: r, md5: md5.New(), sha256: sha256.New(), crc64: crc64.New(crc64Table)}
+}
+
+func (r *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	if n > 0 {
+		r.md5.Write(p[:n])
+		r.sha256.Write(p[:n])
+		r.crc64.Write(p[:n])
+		r.size += int64(n)
+	}
+
+	return n, err
+}
+
+// MD5 returns the hex encoded MD5 of the data read so far.
+func (r *ChecksumReader) MD5() string {
+	return hex.EncodeToString(r.md5.Sum(nil))
+}
+
+// ContentMD5 returns the base64 encoded MD5 of the data read so far, which is the value of Content-MD5 header.
+func (r *ChecksumReader) ContentMD5() string {
+	return base64.StdEncoding.EncodeToString(r.md5.Sum(nil))
+}
+
+// SHA256 returns the hex encoded SHA-256 of the data read so far.
+func (r *ChecksumReader) SHA256() string {
+	return hex.EncodeToString(r.sha256.Sum(nil))
+}
+
+// CRC64 returns the CRC64 (ECMA) of the data read so far.
+func (r *ChecksumReader) CRC64() uint64 {
+	return r.crc64.Sum64()
+}
+
+// Size returns the count of bytes read so far.
+func (r *ChecksumReader) Size() int64 {
+	return r.size
+}
+
+// GetCRC64 gets the CRC64 (ECMA) value from data.
+func GetCRC64(data []byte) uint64 {
+	return crc64.Checksum(data, crc64Table)
+}
+
+// CRC64Combine returns the CRC64 (ECMA) of the concatenation of two blocks of data,
+// crc1 is the CRC64 of the first block, crc2 and len2 are the CRC64 and length of the second block.
+//
+// It is ported from crc32_combine of zlib.
+func CRC64Combine(crc1, crc2 uint64, len2 int64) uint64 {
+	if len2 <= 0 {
+		return crc1
+	}
+
+	even := make([]uint64, 64)
+	odd := make([]uint64, 64)
+
+	// the operator for one zero bit
+	odd[0] = crc64.ECMA
+	var row uint64 = 1
+
+	for n := 1; n < 64; n++ {
+		odd[n] = row
+		row <<= 1
+	}
+
+	// the operator for two and four zero bits
+	gf2MatrixSquare(even, odd)
+	gf2MatrixSquare(odd, even)
+
+	// apply len2 zeros to crc1, the first square puts the operator for one zero byte in even
+	for {
+		gf2MatrixSquare(even, odd)
+
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(even, crc1)
+		}
+
+		len2 >>= 1
+
+		if len2 == 0 {
+			break
+		}
+
+		gf2MatrixSquare(odd, even)
+
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(odd, crc1)
+		}
+
+		len2 >>= 1
+
+		if len2 == 0 {
+			break
+		}
+	}
+
+	return crc1 ^ crc2
+}
+
+func gf2MatrixTimes(matrix []uint64, vector uint64) uint64 {
+	var sum uint64
+
+	for i := 0; vector != 0; i, vector = i+1, vector>>1 {
+		if vector&1 != 0 {
+			sum ^= matrix[i]
+		}
+	}
+
+	return sum
+}
+
+func gf2MatrixSquare(square, matrix []uint64) {
+	for n := 0; n < 64; n++ {
+		square[n] = gf2MatrixTimes(matrix, matrix[n])
+	}
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestChecksumReader(t *testing.T) {
+	str := "Hello World 你好"
+	r := NewChecksumReader(strings.NewReader(str))
+
+	if _, err := ioutil.ReadAll(r); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := GetMD5(str, false)
+
+	if r.MD5() != expected {
+		t.Error(FormatTest("ChecksumReader: MD5", r.MD5(), expected))
+	}
+
+	if expected, _ := GetMD5(str, true); r.ContentMD5() != expected {
+		t.Error(FormatTest("ChecksumReader: ContentMD5", r.ContentMD5(), expected))
+	}
+
+	if expected, _ := GetSha256(str); r.SHA256() != expected {
+		t.Error(FormatTest("ChecksumReader: SHA256", r.SHA256(), expected))
+	}
+
+	if r.CRC64() != GetCRC64([]byte(str)) {
+		t.Error(FormatTest("ChecksumReader: CRC64", strconv.FormatUint(r.CRC64(), 10),
+			strconv.FormatUint(GetCRC64([]byte(str)), 10)))
+	}
+
+	if r.Size() != int64(len(str)) {
+		t.Error(FormatTest("ChecksumReader: Size", strconv.FormatInt(r.Size(), 10), strconv.Itoa(len(str))))
+	}
+}
+
+func TestCRC64Combine(t *testing.T) {
+	data := []byte(strings.Repeat("baidubce-sdk-go ", 1000))
+
+	for _, index := range []int{0, 1, 7, 100, len(data) - 1, len(data)} {
+		first, second := data[:index], data[index:]
+		result := CRC64Combine(GetCRC64(first), GetCRC64(second), int64(len(second)))
+		expected := GetCRC64(data)
+
+		if result != expected {
+			t.Error(FormatTest("CRC64Combine", strconv.FormatUint(result, 10), strconv.FormatUint(expected, 10)))
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"log"
@@ -17,6 +18,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
@@ -72,53 +74,58 @@ func HmacSha256Hex(key, message string) string {
 
 // GetMD5 gets the MD5 value from data.
 // Param base64Encode determines whether use Base64Encode meanwhile.
-func GetMD5(data interface{}, base64Encode bool) string {
+func GetMD5(data interface{}, base64Encode bool) (string, error) {
 	hash := md5.New()
 
-	if str, ok := data.(string); ok {
-		io.Copy(hash, strings.NewReader(str))
-	} else if byteArray, ok := data.([]byte); ok {
-		io.Copy(hash, bytes.NewReader(byteArray))
-	} else if reader, ok := data.(io.Reader); ok {
-		if f, ok := data.(io.Seeker); ok {
-			f.Seek(0, 0)
-			io.Copy(hash, reader)
-			f.Seek(0, 0)
-		} else {
-			io.Copy(hash, reader)
-		}
-	} else {
-		panic("data type should be string or []byte or io.Reader.")
+	if err := writeHash(hash, data); err != nil {
+		return "", err
 	}
 
 	if base64Encode {
-		return Base64Encode(hash.Sum(nil))
+		return Base64Encode(hash.Sum(nil)), nil
 	}
 
-	return hex.EncodeToString(hash.Sum(nil))
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 // GetSha256 gets SHA256 value from data.
-func GetSha256(data interface{}) string {
+func GetSha256(data interface{}) (string, error) {
 	hash := sha256.New()
 
+	if err := writeHash(hash, data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeHash writes data to hash, a seekable reader is read from the beginning and rewound after reading.
+func writeHash(hash hash.Hash, data interface{}) error {
+	var err error
+
 	if str, ok := data.(string); ok {
-		io.Copy(hash, strings.NewReader(str))
+		_, err = io.Copy(hash, strings.NewReader(str))
 	} else if byteArray, ok := data.([]byte); ok {
-		io.Copy(hash, bytes.NewReader(byteArray))
+		_, err = hash.Write(byteArray)
 	} else if reader, ok := data.(io.Reader); ok {
 		if f, ok := data.(io.Seeker); ok {
-			f.Seek(0, 0)
-			io.Copy(hash, reader)
-			f.Seek(0, 0)
+			if _, err := f.Seek(0, 0); err != nil {
+				return err
+			}
+
+			if _, err := io.Copy(hash, reader); err != nil {
+				return err
+			}
+
+			_, err = f.Seek(0, 0)
 		} else {
-			io.Copy(hash, reader)
+			_, err = io.Copy(hash, reader)
 		}
 	} else {
-		panic("data type should be string or []byte or io.Reader.")
+		err = errors.New("data type should be string or []byte or io.Reader.")
 	}
 
-	return hex.EncodeToString(hash.Sum(nil))
+	return err
 }
 
 // Base64Encode gets base64 encoded string from data.
@@ -203,16 +210,18 @@ func TimeToUTCString(t time.Time) string {
 }
 
 // TimeStringToRFC1123 returns a formatted string of `time.RFC1123` format.
-func TimeStringToRFC1123(str string) string {
+//
+// An error is returned if the time format is neither `time.RFC3339` nor `time.RFC1123`.
+func TimeStringToRFC1123(str string) (string, error) {
 	t, err := time.Parse(time.RFC3339, str)
 	if err != nil {
 		t, err = time.Parse(time.RFC1123, str)
 		if err != nil {
-			panic("Time format invalid. The time format must be time.RFC3339 or time.RFC1123")
+			return "", fmt.Errorf("Invalid time %s. The time format must be time.RFC3339 or time.RFC1123.", str)
 		}
 	}
 
-	return t.Format(time.RFC1123)
+	return t.Format(time.RFC1123), nil
 }
 
 // HostToURL returns the whole URL string.
@@ -362,6 +371,36 @@ func CheckFileExists(filename string) bool {
 	return exist
 }
 
+// JoinLocalPath joins the slash separated relative path, such as the part of an object key after the prefix,
+// to the local dir. It returns an error if the relative path is absolute or has ".." components,
+// so a path from remote data never leaves the local dir.
+func JoinLocalPath(dir, relativePath string) (string, error) {
+	invalidError := fmt.Errorf("Invalid path %s, it should be under %s.", relativePath, dir)
+
+	if relativePath == "" || strings.HasPrefix(relativePath, "/") || filepath.IsAbs(relativePath) ||
+		filepath.VolumeName(relativePath) != "" {
+
+		return "", invalidError
+	}
+
+	for _, component := range strings.FieldsFunc(relativePath, func(r rune) bool {
+		return r == '/' || r == '\\' || r == filepath.Separator
+	}) {
+		if component == ".." {
+			return "", invalidError
+		}
+	}
+
+	localPath := filepath.Join(dir, filepath.FromSlash(relativePath))
+	rel, err := filepath.Rel(filepath.Clean(dir), localPath)
+
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", invalidError
+	}
+
+	return localPath, nil
+}
+
 // TempFileWithSize generates a temp file with specified size.
 func TempFileWithSize(fileSize int64) (*os.File, error) {
 	f, err := TempFile(nil, "", "")
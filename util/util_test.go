@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -74,21 +75,21 @@ func TestHmacSha256Hex(t *testing.T) {
 
 func TestGetMD5(t *testing.T) {
 	expected := "de22e061b93b832dd8af907ca9002fd7"
-	result := GetMD5("baidubce-sdk-go", false)
+	result, err := GetMD5("baidubce-sdk-go", false)
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetMD5", result, expected))
 	}
 
-	result = GetMD5([]byte("baidubce-sdk-go"), false)
+	result, err = GetMD5([]byte("baidubce-sdk-go"), false)
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetMD5", result, expected))
 	}
 
-	result = GetMD5(strings.NewReader("baidubce-sdk-go"), false)
+	result, err = GetMD5(strings.NewReader("baidubce-sdk-go"), false)
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetMD5", result, expected))
 	}
 
@@ -102,52 +103,48 @@ func TestGetMD5(t *testing.T) {
 			os.Remove(f.Name())
 		}()
 
-		result = GetMD5(f, false)
+		result, err = GetMD5(f, false)
 
-		if result != expected {
+		if err != nil || result != expected {
 			t.Error(FormatTest("GetMD5", result, expected))
 		}
 	}
 
-	result = GetMD5(bufio.NewReader(strings.NewReader("baidubce-sdk-go")), false)
+	result, err = GetMD5(bufio.NewReader(strings.NewReader("baidubce-sdk-go")), false)
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetMD5", result, expected))
 	}
 
-	result = GetMD5("baidubce-sdk-go", true)
+	result, err = GetMD5("baidubce-sdk-go", true)
 	expected = "3iLgYbk7gy3Yr5B8qQAv1w=="
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetMD5", result, expected))
 	}
 
-	defer func() {
-		if err := recover(); err == nil {
-			t.Error(FormatTest("GetMD5", "nil", "error"))
-		}
-	}()
-
-	result = GetMD5(1, false)
+	if _, err := GetMD5(1, false); err == nil {
+		t.Error(FormatTest("GetMD5", "nil", "error"))
+	}
 }
 
 func TestGetSha256(t *testing.T) {
 	expected := "b39aa8e24bcfc4b20c77f7ab36021e5c23cce79df034279ca9991e0472368b89"
-	result := GetSha256("baidubce-sdk-go")
+	result, err := GetSha256("baidubce-sdk-go")
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetSha256", result, expected))
 	}
 
-	result = GetSha256([]byte("baidubce-sdk-go"))
+	result, err = GetSha256([]byte("baidubce-sdk-go"))
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetSha256", result, expected))
 	}
 
-	result = GetSha256(strings.NewReader("baidubce-sdk-go"))
+	result, err = GetSha256(strings.NewReader("baidubce-sdk-go"))
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetSha256", result, expected))
 	}
 
@@ -161,26 +158,22 @@ func TestGetSha256(t *testing.T) {
 			os.Remove(f.Name())
 		}()
 
-		result = GetSha256(f)
+		result, err = GetSha256(f)
 
-		if result != expected {
+		if err != nil || result != expected {
 			t.Error(FormatTest("GetSha256", result, expected))
 		}
 	}
 
-	result = GetSha256(bufio.NewReader(strings.NewReader("baidubce-sdk-go")))
+	result, err = GetSha256(bufio.NewReader(strings.NewReader("baidubce-sdk-go")))
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("GetSha256", result, expected))
 	}
 
-	defer func() {
-		if err := recover(); err == nil {
-			t.Error(FormatTest("GetSha256", "nil", "error"))
-		}
-	}()
-
-	result = GetSha256(1)
+	if _, err := GetSha256(1); err == nil {
+		t.Error(FormatTest("GetSha256", "nil", "error"))
+	}
 }
 
 func TestBase64Encode(t *testing.T) {
@@ -350,19 +343,15 @@ func TestTimeToUTCString(t *testing.T) {
 
 func TestTimeStringToRFC1123(t *testing.T) {
 	expected := "Mon, 16 Nov 2015 07:33:15 UTC"
-	result := TimeStringToRFC1123("2015-11-16T07:33:15Z")
+	result, err := TimeStringToRFC1123("2015-11-16T07:33:15Z")
 
-	if result != expected {
+	if err != nil || result != expected {
 		t.Error(FormatTest("TimeStringToRFC1123", result, expected))
 	}
 
-	defer func() {
-		if err := recover(); err == nil {
-			t.Error(FormatTest("TimeStringToRFC1123", "nil", "error"))
-		}
-	}()
-
-	TimeStringToRFC1123("Invalid")
+	if _, err := TimeStringToRFC1123("Invalid"); err == nil {
+		t.Error(FormatTest("TimeStringToRFC1123", "nil", "error"))
+	}
 }
 
 func TestHostToURL(t *testing.T) {
@@ -592,6 +581,35 @@ func TestCheckFileExists(t *testing.T) {
 	}
 }
 
+func TestJoinLocalPath(t *testing.T) {
+	dir := filepath.Join("local", "dir")
+	valid := map[string]string{
+		"a.txt":      filepath.Join(dir, "a.txt"),
+		"sub/b.txt":  filepath.Join(dir, "sub", "b.txt"),
+		"sub//c.txt": filepath.Join(dir, "sub", "c.txt"),
+		"./d.txt":    filepath.Join(dir, "d.txt"),
+		"..e/f..txt": filepath.Join(dir, "..e", "f..txt"),
+	}
+
+	for relativePath, expected := range valid {
+		if localPath, err := JoinLocalPath(dir, relativePath); err != nil || localPath != expected {
+			t.Error(FormatTest("JoinLocalPath "+relativePath, localPath, expected))
+		}
+	}
+
+	for _, relativePath := range []string{"", ".", "..", "../a.txt", "sub/../../a.txt", "/etc/passwd",
+		"sub/..", "..\\a.txt"} {
+
+		if localPath, err := JoinLocalPath(dir, relativePath); err == nil {
+			t.Error(FormatTest("JoinLocalPath "+relativePath, localPath, "error"))
+		}
+	}
+
+	if localPath, err := JoinLocalPath(".", "a.txt"); err != nil || localPath != "a.txt" {
+		t.Error(FormatTest("JoinLocalPath", localPath, "a.txt"))
+	}
+}
+
 func TestTempFileWithSize(t *testing.T) {
 	var size int64 = 1024
 	f, err := TempFileWithSize(size)